@@ -0,0 +1,150 @@
+package eaopt
+
+import (
+	"math"
+	"sync"
+)
+
+// metropolis returns the probability of accepting an energy increase from e0
+// to e1 at temperature t.
+func metropolis(t, e0, e1 float64) float64 {
+	if t <= 0 {
+		return 0
+	}
+	return math.Exp(-(e1 - e0) / t)
+}
+
+// SAExponential returns an SAAcceptance function with the Metropolis
+// criterion and an exponential cooling schedule: the temperature at
+// generation g is t0 * alpha^g, alpha being usually comprised between 0.8 and
+// 0.99.
+func SAExponential(t0, alpha float64) SAAcceptance {
+	return func(gen, nGen uint, e0, e1 float64) float64 {
+		return metropolis(t0*math.Pow(alpha, float64(gen)), e0, e1)
+	}
+}
+
+// SALinear returns an SAAcceptance function with the Metropolis criterion and
+// a temperature that decreases linearly from t0 to 0 over the run.
+func SALinear(t0 float64) SAAcceptance {
+	return func(gen, nGen uint, e0, e1 float64) float64 {
+		return metropolis(t0*(1-float64(gen)/float64(nGen)), e0, e1)
+	}
+}
+
+// SALogarithmic returns an SAAcceptance function with the Metropolis
+// criterion and a logarithmic cooling schedule: the temperature at generation
+// g is t0 / ln(g + 1). The schedule cools down very slowly but is the one
+// for which convergence to the global optimum is guaranteed.
+// Reference: https://doi.org/10.1109/TPAMI.1984.4767596
+func SALogarithmic(t0 float64) SAAcceptance {
+	return func(gen, nGen uint, e0, e1 float64) float64 {
+		return metropolis(t0/math.Log(float64(gen)+1), e0, e1)
+	}
+}
+
+// lamTarget returns the acceptance ratio targeted by Lam's schedule after a
+// proportion p of the run.
+func lamTarget(p float64) float64 {
+	switch {
+	case p < 0.15:
+		return 0.44 + 0.56*math.Pow(560, -p/0.15)
+	case p < 0.65:
+		return 0.44
+	default:
+		return 0.44 * math.Pow(440, -(p-0.65)/0.35)
+	}
+}
+
+// SALam returns an SAAcceptance function with the Metropolis criterion and
+// Lam's adaptive schedule. The temperature starts at t0 and is adjusted after
+// each call so that the ratio of accepted energy increases follows a target
+// curve which starts close to 1, stays at 0.44 for most of the run and then
+// drops to 0. The ratio is estimated with an exponential moving average of
+// the acceptance probabilities. The returned function holds state and thus
+// shouldn't be shared between GAs.
+// Reference: https://doi.org/10.1016/0167-9260(88)90013-0
+func SALam(t0 float64) SAAcceptance {
+	var (
+		mu    sync.Mutex
+		t     = t0
+		ratio = 0.5
+	)
+	return func(gen, nGen uint, e0, e1 float64) float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		var prob = metropolis(t, e0, e1)
+		ratio = 0.99*ratio + 0.01*prob
+		if ratio > lamTarget(float64(gen)/float64(nGen)) {
+			t *= 0.999
+		} else {
+			t /= 0.999
+		}
+		return prob
+	}
+}
+
+// SAReheating returns an SAAcceptance function with the Metropolis criterion
+// and an exponential cooling schedule which is restarted when the current
+// energies haven't improved during patience generations. The temperature is
+// t0 * alpha^k, k being the number of generations since the last reheat. The
+// returned function holds state and thus shouldn't be shared between GAs.
+func SAReheating(t0, alpha float64, patience uint) SAAcceptance {
+	var (
+		mu                    sync.Mutex
+		best                  = math.Inf(1)
+		lastImprov, lastReset uint
+	)
+	return func(gen, nGen uint, e0, e1 float64) float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		if e0 < best {
+			best, lastImprov = e0, gen
+		}
+		if gen-lastImprov >= patience {
+			lastImprov, lastReset = gen, gen
+		}
+		return metropolis(t0*math.Pow(alpha, float64(gen-lastReset)), e0, e1)
+	}
+}
+
+// SAThresholdAccepting returns an SAAcceptance function that implements
+// threshold accepting: an energy increase is always accepted if it is below a
+// threshold and always rejected otherwise. The threshold decreases linearly
+// from t0 to 0 over the run.
+// Reference: https://doi.org/10.1016/0021-9991(90)90201-B
+func SAThresholdAccepting(t0 float64) SAAcceptance {
+	return func(gen, nGen uint, e0, e1 float64) float64 {
+		if e1-e0 < t0*(1-float64(gen)/float64(nGen)) {
+			return 1
+		}
+		return 0
+	}
+}
+
+// SAGreatDeluge returns an SAAcceptance function that implements the great
+// deluge algorithm: a worse energy is accepted if it is below a water level.
+// The level starts at the first energy the function is given and is lowered
+// by rain at each generation. The returned function holds state and thus
+// shouldn't be shared between GAs.
+// Reference: https://doi.org/10.1006/jcph.1993.1010
+func SAGreatDeluge(rain float64) SAAcceptance {
+	var (
+		mu      sync.Mutex
+		level   = math.NaN()
+		lastGen uint
+	)
+	return func(gen, nGen uint, e0, e1 float64) float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		if math.IsNaN(level) {
+			level, lastGen = e0, gen
+		}
+		level -= rain * float64(gen-lastGen)
+		lastGen = gen
+		if e1 <= level {
+			return 1
+		}
+		return 0
+	}
+}
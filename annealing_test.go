@@ -0,0 +1,86 @@
+package eaopt
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSASchedules(t *testing.T) {
+	var schedules = map[string]SAAcceptance{
+		"exponential": SAExponential(10, 0.9),
+		"linear":      SALinear(10),
+		"logarithmic": SALogarithmic(10),
+		"reheating":   SAReheating(10, 0.9, 1000),
+	}
+	for name, accept := range schedules {
+		var (
+			early = accept(1, 100, 0, 1)
+			late  = accept(99, 100, 0, 1)
+		)
+		if early <= 0 || early > 1 || late < 0 || late > 1 {
+			t.Errorf("%s: probabilities should be between 0 and 1, got %f and %f", name, early, late)
+		}
+		if late >= early {
+			t.Errorf("%s: expected the probability to decrease, got %f and %f", name, early, late)
+		}
+		// Larger increases are less likely to be accepted
+		if accept(50, 100, 0, 1) <= accept(50, 100, 0, 5) {
+			t.Errorf("%s: larger energy increases should be less likely to be accepted", name)
+		}
+	}
+	// The linear temperature ends at 0
+	if p := SALinear(10)(100, 100, 0, 1); p != 0 {
+		t.Errorf("Expected 0, got %f", p)
+	}
+}
+
+func TestSALam(t *testing.T) {
+	// The temperature increases if too few moves are accepted
+	var (
+		accept = SALam(0.01)
+		p0     = accept(1, 1000, 0, 1)
+		p      float64
+	)
+	for i := 0; i < 1000; i++ {
+		p = accept(1, 1000, 0, 1)
+	}
+	if p <= p0 {
+		t.Errorf("Expected the probability to increase, got %f and %f", p0, p)
+	}
+	if math.Abs(lamTarget(0)-1) > 1e-9 || lamTarget(0.5) != 0.44 || lamTarget(1) > 0.01 {
+		t.Error("Unexpected target acceptance ratios")
+	}
+}
+
+func TestSAReheating(t *testing.T) {
+	var (
+		accept = SAReheating(10, 0.5, 5)
+		p1     = accept(1, 100, 0, 1)
+		p5     = accept(5, 100, 0, 1)
+	)
+	if p5 >= p1 {
+		t.Errorf("Expected the temperature to decrease, got %f and %f", p1, p5)
+	}
+	// The energy hasn't improved for 5 generations so the schedule restarts
+	if p6 := accept(6, 100, 0, 1); p6 != metropolis(10, 0, 1) {
+		t.Errorf("Expected %f, got %f", metropolis(10, 0, 1), p6)
+	}
+}
+
+func TestSAThresholdAccepting(t *testing.T) {
+	var accept = SAThresholdAccepting(2)
+	if accept(1, 4, 0, 1) != 1 || accept(1, 4, 0, 2) != 0 || accept(3, 4, 0, 1) != 0 {
+		t.Error("Unexpected acceptance")
+	}
+}
+
+func TestSAGreatDeluge(t *testing.T) {
+	var accept = SAGreatDeluge(1)
+	if accept(1, 10, 5, 5) != 1 || accept(1, 10, 5, 6) != 0 {
+		t.Error("Energies below the level should be accepted")
+	}
+	// The level has dropped to 3
+	if accept(3, 10, 2, 4) != 0 || accept(3, 10, 2, 3) != 1 {
+		t.Error("The level should drop at each generation")
+	}
+}
@@ -19,3 +19,47 @@ func BenchmarkIndividualsEvaluateParallel(b *testing.B) {
 		indis.Evaluate(true)
 	}
 }
+
+func BenchmarkCrossPMX(b *testing.B) {
+	var rng = newRand()
+	b.Run("Slice", func(b *testing.B) {
+		var p1, p2 = IntSlice(rng.Perm(100)), IntSlice(rng.Perm(100))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			CrossPMX(p1, p2, rng)
+		}
+	})
+	b.Run("Generic", func(b *testing.B) {
+		var p1, p2 = rng.Perm(100), rng.Perm(100)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			CrossPMXInt(p1, p2, rng)
+		}
+	})
+}
+
+func BenchmarkSpeciation(b *testing.B) {
+	var indis = newIndividuals(1000, false, NewVector, newRand())
+	b.Run("KMedoids", func(b *testing.B) {
+		var spec = SpecKMedoids{K: 4, MinPerCluster: 1, Metric: l1Distance, MaxIterations: 10}
+		for i := 0; i < b.N; i++ {
+			spec.Apply(indis, newRand())
+		}
+	})
+	b.Run("CLARA", func(b *testing.B) {
+		var spec = SpecCLARA{K: 4, Metric: l1Distance, NSamples: 5, SampleSize: 48, MaxIterations: 10}
+		for i := 0; i < b.N; i++ {
+			spec.Apply(indis, newRand())
+		}
+	})
+	b.Run("DistanceMatrix", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewDistanceMatrix(indis, l1Distance, false)
+		}
+	})
+	b.Run("DistanceMatrixParallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewDistanceMatrix(indis, l1Distance, true)
+		}
+	})
+}
@@ -0,0 +1,274 @@
+package eaopt
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// A Bitstring is a fixed-length sequence of bits packed into 64-bit words.
+// Bit i is stored in Words[i/64] at position i%64, the unused bits of the last
+// word are always 0.
+type Bitstring struct {
+	N     uint
+	Words []uint64
+}
+
+// NewBitstring returns a Bitstring of n bits which are all set to 0.
+func NewBitstring(n uint) *Bitstring {
+	return &Bitstring{N: n, Words: make([]uint64, (n+63)/64)}
+}
+
+// Bits returns the Bitstring itself, which means that any type that embeds a
+// Bitstring satisfies the BitGenome interface.
+func (b *Bitstring) Bits() *Bitstring { return b }
+
+// Len returns the number of bits.
+func (b *Bitstring) Len() int { return int(b.N) }
+
+// Get returns true if bit i is set.
+func (b *Bitstring) Get(i int) bool {
+	return b.Words[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Set sets bit i to 1 if v is true and to 0 otherwise.
+func (b *Bitstring) Set(i int, v bool) {
+	if v {
+		b.Words[i/64] |= 1 << uint(i%64)
+	} else {
+		b.Words[i/64] &^= 1 << uint(i%64)
+	}
+}
+
+// Flip inverts bit i.
+func (b *Bitstring) Flip(i int) {
+	b.Words[i/64] ^= 1 << uint(i%64)
+}
+
+// Count returns the number of bits that are set.
+func (b *Bitstring) Count() (n int) {
+	for _, w := range b.Words {
+		n += bits.OnesCount64(w)
+	}
+	return
+}
+
+// Uint returns the n bits starting at index start as an unsigned integer, the
+// bit at index start being the most significant one. n has to be at most 64.
+func (b *Bitstring) Uint(start, n int) (v uint64) {
+	for i := start; i < start+n; i++ {
+		v <<= 1
+		if b.Get(i) {
+			v |= 1
+		}
+	}
+	return
+}
+
+// SetUint writes the n lowest bits of v starting at index start, the most
+// significant bit being written first. It is the inverse of Uint.
+func (b *Bitstring) SetUint(start, n int, v uint64) {
+	for i := start + n - 1; i >= start; i-- {
+		b.Set(i, v&1 == 1)
+		v >>= 1
+	}
+}
+
+// Copy returns a deep copy of a Bitstring.
+func (b *Bitstring) Copy() *Bitstring {
+	var words = make([]uint64, len(b.Words))
+	copy(words, b.Words)
+	return &Bitstring{N: b.N, Words: words}
+}
+
+// String returns the bits as a sequence of 0s and 1s, starting with bit 0.
+func (b *Bitstring) String() string {
+	var sb strings.Builder
+	for i := 0; i < b.Len(); i++ {
+		if b.Get(i) {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
+
+// swapRange exchanges the bits in [lo, hi) of two Bitstrings of equal length.
+func swapRange(b1, b2 *Bitstring, lo, hi int) {
+	for lo < hi {
+		var (
+			w    = lo / 64
+			from = uint(lo % 64)
+			to   = uint(minInt(hi-w*64, 64))
+			mask = ^uint64(0) << from
+		)
+		if to < 64 {
+			mask &= (1 << to) - 1
+		}
+		var diff = (b1.Words[w] ^ b2.Words[w]) & mask
+		b1.Words[w] ^= diff
+		b2.Words[w] ^= diff
+		lo = (w + 1) * 64
+	}
+}
+
+// A BitGenome is a Genome that is encoded with a Bitstring. Embedding a
+// Bitstring in a struct is enough to implement it.
+type BitGenome interface {
+	Bits() *Bitstring
+}
+
+// HammingDistance is a Metric that counts the number of positions at which the
+// Bitstrings of two BitGenomes differ.
+func HammingDistance(a, b Individual) float64 {
+	var (
+		b1 = unwrapped(a.Genome).(BitGenome).Bits()
+		b2 = unwrapped(b.Genome).(BitGenome).Bits()
+		d  int
+	)
+	for i := range b1.Words {
+		d += bits.OnesCount64(b1.Words[i] ^ b2.Words[i])
+	}
+	return float64(d)
+}
+
+// InitUnifBits generates a Bitstring of n bits where each bit is set with
+// probability 0.5.
+func InitUnifBits(n uint, rng *rand.Rand) *Bitstring {
+	var b = NewBitstring(n)
+	for i := range b.Words {
+		b.Words[i] = rng.Uint64()
+	}
+	if r := n % 64; r != 0 {
+		b.Words[len(b.Words)-1] &= (1 << r) - 1
+	}
+	return b
+}
+
+// InitBiasedBits generates a Bitstring of n bits where each bit is set with
+// probability p.
+func InitBiasedBits(n uint, p float64, rng *rand.Rand) *Bitstring {
+	var b = NewBitstring(n)
+	for i := 0; i < int(n); i++ {
+		if rng.Float64() < p {
+			b.Set(i, true)
+		}
+	}
+	return b
+}
+
+// MutFlipBits flips each bit of a Bitstring with probability rate. Instead of
+// tossing a coin for each bit, the gaps between consecutive flips are sampled
+// from a geometric distribution, which is much faster for low rates.
+func MutFlipBits(b *Bitstring, rate float64, rng *rand.Rand) {
+	if rate <= 0 {
+		return
+	}
+	if rate >= 1 {
+		for i := 0; i < b.Len(); i++ {
+			b.Flip(i)
+		}
+		return
+	}
+	var logq = math.Log1p(-rate)
+	for i := 0; ; i++ {
+		var gap = math.Floor(math.Log(1-rng.Float64()) / logq)
+		if gap >= float64(b.Len()-i) {
+			return
+		}
+		i += int(gap)
+		b.Flip(i)
+	}
+}
+
+// crossGNXBits contains the deterministic part of CrossGNXBits for testing
+// purposes.
+func crossGNXBits(b1, b2 *Bitstring, indexes []int) {
+	indexes = append(indexes, b1.Len())
+	for i := 0; i+1 < len(indexes); i += 2 {
+		swapRange(b1, b2, indexes[i], indexes[i+1])
+	}
+}
+
+// CrossGNXBits applies n-point crossover to two Bitstrings of equal length: n
+// distinct cut points are chosen and every other segment is exchanged. Use n =
+// 1 for one-point crossover and n = 2 for two-point crossover. n has to be
+// lower than the number of bits.
+func CrossGNXBits(b1, b2 *Bitstring, n uint, rng *rand.Rand) {
+	var indexes = randomInts(n, 1, b1.Len(), rng)
+	sort.Ints(indexes)
+	crossGNXBits(b1, b2, indexes)
+}
+
+// CrossUniformBits exchanges each bit of two Bitstrings of equal length with
+// probability 0.5.
+func CrossUniformBits(b1, b2 *Bitstring, rng *rand.Rand) {
+	for i := range b1.Words {
+		var diff = (b1.Words[i] ^ b2.Words[i]) & rng.Uint64()
+		b1.Words[i] ^= diff
+		b2.Words[i] ^= diff
+	}
+}
+
+// GrayToBinary converts a Gray-coded integer to its binary value.
+func GrayToBinary(g uint64) uint64 {
+	for shift := uint(1); shift < 64; shift <<= 1 {
+		g ^= g >> shift
+	}
+	return g
+}
+
+// BinaryToGray converts an integer to its Gray code.
+func BinaryToGray(v uint64) uint64 {
+	return v ^ (v >> 1)
+}
+
+// decodeUint reads n bits and returns their value along with the largest
+// value n bits can represent.
+func (b *Bitstring) decodeUint(start, n int, gray bool) (uint64, uint64) {
+	var v = b.Uint(start, n)
+	if gray {
+		v = GrayToBinary(v)
+	}
+	return v, math.MaxUint64 >> uint(64-n)
+}
+
+// DecodeFloat64 maps the n bits starting at index start to a float64 in
+// [lower, upper]. The bits are interpreted as a Gray code if gray is true. n
+// has to be between 1 and 64.
+func (b *Bitstring) DecodeFloat64(start, n int, lower, upper float64, gray bool) float64 {
+	var v, max = b.decodeUint(start, n, gray)
+	return lower + float64(v)/float64(max)*(upper-lower)
+}
+
+// DecodeInt maps the n bits starting at index start to an int in [lower,
+// upper] by scaling the decoded value and rounding it to the nearest integer.
+// The bits are interpreted as a Gray code if gray is true. n has to be between
+// 1 and 64.
+func (b *Bitstring) DecodeInt(start, n int, lower, upper int, gray bool) int {
+	var v, max = b.decodeUint(start, n, gray)
+	return lower + int(math.Round(float64(v)/float64(max)*float64(upper-lower)))
+}
+
+// DecodeFloat64s splits a Bitstring into consecutive chunks of n bits and
+// maps chunk i to a float64 in [lower[i], upper[i]] with DecodeFloat64.
+func (b *Bitstring) DecodeFloat64s(n int, lower, upper []float64, gray bool) []float64 {
+	var floats = make([]float64, len(lower))
+	for i := range floats {
+		floats[i] = b.DecodeFloat64(i*n, n, lower[i], upper[i], gray)
+	}
+	return floats
+}
+
+// DecodeInts splits a Bitstring into consecutive chunks of n bits and maps
+// chunk i to an int in [lower[i], upper[i]] with DecodeInt.
+func (b *Bitstring) DecodeInts(n int, lower, upper []int, gray bool) []int {
+	var ints = make([]int, len(lower))
+	for i := range ints {
+		ints[i] = b.DecodeInt(i*n, n, lower[i], upper[i], gray)
+	}
+	return ints
+}
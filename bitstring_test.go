@@ -0,0 +1,216 @@
+package eaopt
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+// OneMax is a BitGenome which has to maximize the number of bits that are set.
+type OneMax struct {
+	Bitstring
+}
+
+func (g *OneMax) Evaluate() (float64, error) { return float64(g.Len() - g.Count()), nil }
+func (g *OneMax) Mutate(rng *rand.Rand)      { MutFlipBits(&g.Bitstring, 1/float64(g.N), rng) }
+func (g *OneMax) Crossover(q Genome, rng *rand.Rand) {
+	CrossUniformBits(&g.Bitstring, &q.(*OneMax).Bitstring, rng)
+}
+func (g *OneMax) Clone() Genome { return &OneMax{*g.Copy()} }
+
+func bitstringFromString(s string) *Bitstring {
+	var b = NewBitstring(uint(len(s)))
+	for i, c := range s {
+		b.Set(i, c == '1')
+	}
+	return b
+}
+
+func TestBitstring(t *testing.T) {
+	var b = NewBitstring(70)
+	if len(b.Words) != 2 || b.Count() != 0 {
+		t.Errorf("Unexpected empty bitstring %v", b.Words)
+	}
+	b.Set(0, true)
+	b.Set(69, true)
+	b.Flip(64)
+	if !b.Get(0) || !b.Get(64) || !b.Get(69) || b.Get(1) || b.Count() != 3 {
+		t.Errorf("Unexpected bitstring %s", b)
+	}
+	b.Set(69, false)
+	if b.Get(69) || b.Count() != 2 {
+		t.Errorf("Unexpected bitstring %s", b)
+	}
+	b.SetUint(60, 8, 0xA5)
+	if v := b.Uint(60, 8); v != 0xA5 {
+		t.Errorf("Expected %d, got %d", 0xA5, v)
+	}
+	var c = b.Copy()
+	c.Flip(3)
+	if b.Get(3) {
+		t.Error("Copy should be deep")
+	}
+	if s := bitstringFromString("0110").String(); s != "0110" {
+		t.Errorf("Expected 0110, got %s", s)
+	}
+}
+
+func TestCrossGNXBits(t *testing.T) {
+	var testCases = []struct {
+		indexes []int
+		o1, o2  string
+	}{
+		{[]int{3}, "000111", "111000"},
+		{[]int{1, 4}, "011100", "100011"},
+		{[]int{1, 2, 5}, "010001", "101110"},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var b1, b2 = bitstringFromString("000000"), bitstringFromString("111111")
+			crossGNXBits(b1, b2, tc.indexes)
+			if b1.String() != tc.o1 || b2.String() != tc.o2 {
+				t.Errorf("Expected %s and %s, got %s and %s", tc.o1, tc.o2, b1, b2)
+			}
+		})
+	}
+	// Check segments that span several words
+	var (
+		rng    = newRand()
+		b1, b2 = NewBitstring(200), InitUnifBits(200, rng)
+		c1, c2 = b1.Copy(), b2.Copy()
+	)
+	crossGNXBits(b1, b2, []int{30, 150})
+	for i := 0; i < 200; i++ {
+		var swapped = i >= 30 && i < 150
+		if (b1.Get(i) == c2.Get(i)) != swapped && c1.Get(i) != c2.Get(i) {
+			t.Fatalf("Bit %d was not crossed correctly", i)
+		}
+	}
+	CrossGNXBits(b1, b2, 2, rng)
+	if b1.Count()+b2.Count() != c1.Count()+c2.Count() {
+		t.Error("Crossover should preserve the bits")
+	}
+}
+
+func TestCrossUniformBits(t *testing.T) {
+	var (
+		rng    = newRand()
+		b1, b2 = NewBitstring(130), NewBitstring(130)
+	)
+	for i := range b2.Words {
+		b2.Words[i] = math.MaxUint64
+	}
+	b2.Words[2] = 3
+	CrossUniformBits(b1, b2, rng)
+	for i := 0; i < 130; i++ {
+		if b1.Get(i) == b2.Get(i) {
+			t.Fatalf("Bit %d should be set in exactly one offspring", i)
+		}
+	}
+	if b1.Words[2]>>2 != 0 || b2.Words[2]>>2 != 0 {
+		t.Error("Unused bits should remain unset")
+	}
+}
+
+func TestMutFlipBits(t *testing.T) {
+	var rng = newRand()
+	var b = NewBitstring(10000)
+	MutFlipBits(b, 0, rng)
+	if b.Count() != 0 {
+		t.Errorf("Expected 0 flips, got %d", b.Count())
+	}
+	MutFlipBits(b, 0.1, rng)
+	if n := b.Count(); n < 900 || n > 1100 {
+		t.Errorf("Expected around 1000 flips, got %d", n)
+	}
+	b = NewBitstring(10)
+	MutFlipBits(b, 1, rng)
+	if b.Count() != 10 {
+		t.Errorf("Expected 10 flips, got %d", b.Count())
+	}
+}
+
+func TestInitBits(t *testing.T) {
+	var rng = newRand()
+	var b = InitUnifBits(1000, rng)
+	if n := b.Count(); n < 400 || n > 600 {
+		t.Errorf("Expected around 500 bits set, got %d", n)
+	}
+	if b.Words[len(b.Words)-1]>>(1000%64) != 0 {
+		t.Error("Unused bits should remain unset")
+	}
+	b = InitBiasedBits(1000, 0.9, rng)
+	if n := b.Count(); n < 850 || n > 950 {
+		t.Errorf("Expected around 900 bits set, got %d", n)
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	var (
+		a = Individual{Genome: &OneMax{*bitstringFromString("0110")}}
+		b = Individual{Genome: &OneMax{*bitstringFromString("1100")}}
+	)
+	if d := HammingDistance(a, b); d != 2 {
+		t.Errorf("Expected 2, got %f", d)
+	}
+	if d := HammingDistance(a, a); d != 0 {
+		t.Errorf("Expected 0, got %f", d)
+	}
+}
+
+func TestGrayCode(t *testing.T) {
+	for v := uint64(0); v < 1000; v++ {
+		var g = BinaryToGray(v)
+		if GrayToBinary(g) != v {
+			t.Errorf("Expected %d, got %d", v, GrayToBinary(g))
+		}
+		// Consecutive values only differ by one bit
+		if v > 0 && bits.OnesCount64(BinaryToGray(v-1)^g) != 1 {
+			t.Errorf("Gray codes of %d and %d differ by more than one bit", v-1, v)
+		}
+	}
+}
+
+func TestBitstringDecode(t *testing.T) {
+	var b = NewBitstring(12)
+	b.SetUint(0, 4, 15)
+	b.SetUint(4, 4, 5)
+	b.SetUint(8, 4, BinaryToGray(5))
+	if x := b.DecodeFloat64(0, 4, -1, 1, false); x != 1 {
+		t.Errorf("Expected 1, got %f", x)
+	}
+	if x := b.DecodeFloat64(4, 4, 0, 3, false); x != 1 {
+		t.Errorf("Expected 1, got %f", x)
+	}
+	if x := b.DecodeFloat64(8, 4, 0, 3, true); x != 1 {
+		t.Errorf("Expected 1, got %f", x)
+	}
+	if ints := b.DecodeInts(4, []int{0, -3, 0}, []int{5, 3, 30}, false); fmt.Sprint(ints) != "[5 -1 14]" {
+		t.Errorf("Expected [5 -1 14], got %v", ints)
+	}
+	if floats := b.DecodeFloat64s(4, []float64{0, 0, 0}, []float64{15, 15, 15}, true); fmt.Sprint(floats) != "[10 6 5]" {
+		t.Errorf("Expected [10 6 5], got %v", floats)
+	}
+	b = InitUnifBits(64, newRand())
+	if x := b.DecodeInt(0, 64, 0, 10, false); x < 0 || x > 10 {
+		t.Errorf("Expected a value in [0, 10], got %d", x)
+	}
+}
+
+func TestBitstringMinimize(t *testing.T) {
+	var conf = NewDefaultGAConfig()
+	conf.RNG = rand.New(rand.NewSource(42))
+	conf.NGenerations = 100
+	var ga, _ = conf.NewGA()
+	var err = ga.Minimize(func(rng *rand.Rand) Genome {
+		return &OneMax{*InitUnifBits(30, rng)}
+	})
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if ga.HallOfFame[0].Fitness != 0 {
+		t.Errorf("Expected 0, got %f", ga.HallOfFame[0].Fitness)
+	}
+}
@@ -0,0 +1,340 @@
+package eaopt
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// A Contest pits two genomes against each other and returns a score for each
+// one of them. Scores are minimized, hence they should be understood as losses
+// or penalties.
+type Contest func(a, b Genome) (scoreA, scoreB float64, err error)
+
+// A Match indicates two players that have to face each other. Player A belongs
+// to the first group of players and B to the second one. If ArchivedA (resp.
+// ArchivedB) is true then A (resp. B) is an index in the archive of the first
+// (resp. second) group. The score of an archived player is not recorded.
+type Match struct {
+	A, B                 int
+	ArchivedA, ArchivedB bool
+}
+
+// A Scheduler decides which matches are played between two groups of nA and
+// nB players. The groups have archives of past champions of size archA and
+// archB. If same is true then both groups are one and the same, in which case
+// a player should not be matched with itself.
+type Scheduler interface {
+	Apply(nA, nB, archA, archB int, same bool, rng *rand.Rand) []Match
+	Validate() error
+}
+
+// SchedRoundRobin matches each player with every player of the other group.
+// When both groups are the same each pair of players faces each other once.
+type SchedRoundRobin struct{}
+
+// Apply SchedRoundRobin.
+func (sched SchedRoundRobin) Apply(nA, nB, archA, archB int, same bool, rng *rand.Rand) []Match {
+	var matches []Match
+	for a := 0; a < nA; a++ {
+		var start int
+		if same {
+			start = a + 1
+		}
+		for b := start; b < nB; b++ {
+			matches = append(matches, Match{A: a, B: b})
+		}
+	}
+	return matches
+}
+
+// Validate SchedRoundRobin fields.
+func (sched SchedRoundRobin) Validate() error {
+	return nil
+}
+
+// sampleOpponents returns k distinct opponents sampled from n players. The
+// player at index self is excluded if it is non-negative.
+func sampleOpponents(k, n, self int, rng *rand.Rand) []int {
+	var candidates = make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		if i != self {
+			candidates = append(candidates, i)
+		}
+	}
+	var opponents, _, _ = sampleInts(candidates, uint(minInt(k, len(candidates))), rng)
+	return opponents
+}
+
+// SchedRandom matches each player with NOpponents players sampled at random
+// from the other group.
+type SchedRandom struct {
+	NOpponents uint
+}
+
+// Apply SchedRandom.
+func (sched SchedRandom) Apply(nA, nB, archA, archB int, same bool, rng *rand.Rand) []Match {
+	var matches []Match
+	for a := 0; a < nA; a++ {
+		var self = -1
+		if same {
+			self = a
+		}
+		for _, b := range sampleOpponents(int(sched.NOpponents), nB, self, rng) {
+			matches = append(matches, Match{A: a, B: b})
+		}
+	}
+	if same {
+		return matches
+	}
+	for b := 0; b < nB; b++ {
+		for _, a := range sampleOpponents(int(sched.NOpponents), nA, -1, rng) {
+			matches = append(matches, Match{A: a, B: b})
+		}
+	}
+	return matches
+}
+
+// Validate SchedRandom fields.
+func (sched SchedRandom) Validate() error {
+	if sched.NOpponents == 0 {
+		return errors.New("NOpponents should be higher than 0")
+	}
+	return nil
+}
+
+// SchedHallOfFame matches each player with NOpponents past champions of the
+// other group sampled at random. Players are matched with the current players
+// of the other group as long as the archive is empty. The CompCoevo it is
+// used with needs an archiveSize higher than 0.
+// Reference: https://doi.org/10.1162/evco.1997.5.1.1
+type SchedHallOfFame struct {
+	NOpponents uint
+}
+
+// Apply SchedHallOfFame.
+func (sched SchedHallOfFame) Apply(nA, nB, archA, archB int, same bool, rng *rand.Rand) []Match {
+	if archB == 0 || (!same && archA == 0) {
+		return SchedRandom{NOpponents: sched.NOpponents}.Apply(nA, nB, archA, archB, same, rng)
+	}
+	var matches []Match
+	for a := 0; a < nA; a++ {
+		for _, b := range sampleOpponents(int(sched.NOpponents), archB, -1, rng) {
+			matches = append(matches, Match{A: a, B: b, ArchivedB: true})
+		}
+	}
+	if same {
+		return matches
+	}
+	for b := 0; b < nB; b++ {
+		for _, a := range sampleOpponents(int(sched.NOpponents), archA, -1, rng) {
+			matches = append(matches, Match{A: a, B: b, ArchivedA: true})
+		}
+	}
+	return matches
+}
+
+// Validate SchedHallOfFame fields.
+func (sched SchedHallOfFame) Validate() error {
+	if sched.NOpponents == 0 {
+		return errors.New("NOpponents should be higher than 0")
+	}
+	return nil
+}
+
+// A Competitor wraps a Genome that has no absolute fitness. The fitness of a
+// Competitor is determined by CompCoevo once all the Competitors of a
+// generation are known, hence Evaluate returns +Inf as a placeholder and the
+// Evaluate method of the wrapped Genome is never called.
+type Competitor struct {
+	Genome
+}
+
+// Evaluate returns +Inf, the actual fitness is assigned by CompCoevo.
+func (c *Competitor) Evaluate() (float64, error) { return math.Inf(1), nil }
+
+// Crossover the wrapped Genome with the Genome wrapped by another Competitor.
+func (c *Competitor) Crossover(q Genome, rng *rand.Rand) {
+	c.Genome.Crossover(q.(*Competitor).Genome, rng)
+}
+
+// Clone returns a deep copy of a Competitor.
+func (c Competitor) Clone() Genome {
+	return &Competitor{Genome: c.Genome.Clone()}
+}
+
+// CompCoevo implements competitive coevolution, where genomes are only scored
+// against opponents. If the GA has a single Population then the individuals
+// compete against each other. If it has two Populations then the individuals
+// of the first one (the hosts) compete against those of the second one (the
+// parasites). The fitness of an individual is the average score it obtained
+// over the matches it played. Fitnesses are relative to the current
+// opponents, which means that they can't be compared across generations.
+type CompCoevo struct {
+	Contest     Contest
+	Scheduler   Scheduler
+	ArchiveSize uint          // Maximum number of champions kept per Population
+	Archives    []Individuals // Best individual of each Population at each generation, most recent last
+	GA          *GA
+
+	err error
+}
+
+// NewCompCoevo instantiates and returns a CompCoevo instance after having
+// checked for input errors. nPops has to be 1 for competition within a single
+// population or 2 for host/parasite competition.
+func NewCompCoevo(nPops, popSize, nSteps uint, contest Contest, scheduler Scheduler,
+	archiveSize uint, rng *rand.Rand) (*CompCoevo, error) {
+	// Check inputs
+	if nPops != 1 && nPops != 2 {
+		return nil, errors.New("nPops should be 1 or 2")
+	}
+	if contest == nil {
+		return nil, errors.New("contest has to be provided")
+	}
+	if scheduler == nil {
+		return nil, errors.New("scheduler has to be provided")
+	}
+	if err := scheduler.Validate(); err != nil {
+		return nil, err
+	}
+	switch scheduler.(type) {
+	case SchedHallOfFame, *SchedHallOfFame:
+		if archiveSize == 0 {
+			return nil, errors.New("archiveSize should be higher than 0 with SchedHallOfFame")
+		}
+	}
+	if rng == nil {
+		rng = newRand()
+	}
+	// Instantiate a GA
+	var ga, err = GAConfig{
+		NPops:        nPops,
+		PopSize:      popSize,
+		NGenerations: nSteps,
+		HofSize:      1,
+		Model: ModGenerational{
+			Selector:  SelTournament{NContestants: 3},
+			MutRate:   0.5,
+			CrossRate: 0.7,
+		},
+		RNG: rand.New(rand.NewSource(rng.Int63())),
+	}.NewGA()
+	if err != nil {
+		return nil, err
+	}
+	return &CompCoevo{
+		Contest:     contest,
+		Scheduler:   scheduler,
+		ArchiveSize: archiveSize,
+		GA:          ga,
+	}, nil
+}
+
+// player returns the Individual referenced by a Match.
+func (cc *CompCoevo) player(pop, idx int, archived bool) Individual {
+	if archived {
+		return cc.Archives[pop][idx]
+	}
+	return cc.GA.Populations[pop].Individuals[idx]
+}
+
+// assignFitnesses plays the matches decided by the Scheduler and aggregates
+// the scores into the fitness of each individual.
+func (cc *CompCoevo) assignFitnesses(ga *GA) error {
+	var (
+		same   = len(ga.Populations) == 1
+		pa, pb = 0, len(ga.Populations) - 1
+		nA     = len(ga.Populations[pa].Individuals)
+		nB     = len(ga.Populations[pb].Individuals)
+		totals = [][]float64{make([]float64, nA), make([]float64, nB)}
+		counts = [][]int{make([]int, nA), make([]int, nB)}
+	)
+	var matches = cc.Scheduler.Apply(nA, nB, len(cc.Archives[pa]), len(cc.Archives[pb]), same, ga.RNG)
+	for _, m := range matches {
+		var (
+			a            = cc.player(pa, m.A, m.ArchivedA)
+			b            = cc.player(pb, m.B, m.ArchivedB)
+			sa, sb, err  = cc.Contest(a.Genome.(*Competitor).Genome, b.Genome.(*Competitor).Genome)
+			sideA, sideB = 0, 1
+		)
+		if err != nil {
+			return err
+		}
+		if same {
+			sideB = 0
+		}
+		if !m.ArchivedA {
+			totals[sideA][m.A] += sa
+			counts[sideA][m.A]++
+		}
+		if !m.ArchivedB {
+			totals[sideB][m.B] += sb
+			counts[sideB][m.B]++
+		}
+	}
+	// Assign the average scores and update the archives
+	for i := range ga.Populations {
+		var indis = ga.Populations[i].Individuals
+		for j := range indis {
+			indis[j].Fitness = math.Inf(1)
+			if counts[i][j] > 0 {
+				indis[j].Fitness = totals[i][j] / float64(counts[i][j])
+			}
+			indis[j].Evaluated = true
+		}
+		indis.SortByFitness()
+		if cc.ArchiveSize > 0 {
+			cc.Archives[i] = append(cc.Archives[i], indis[0].Clone(ga.RNG))
+			if len(cc.Archives[i]) > int(cc.ArchiveSize) {
+				cc.Archives[i] = cc.Archives[i][1:]
+			}
+		}
+	}
+	// The hall of fame is updated with the fitnesses of the current
+	// generation
+	for i := range ga.HallOfFame {
+		ga.HallOfFame[i] = Individual{Fitness: math.Inf(1)}
+	}
+	for _, pop := range ga.Populations {
+		updateHallOfFame(ga.HallOfFame, pop.Individuals, pop.RNG)
+	}
+	return nil
+}
+
+// Minimize evolves the Populations of competitors. newGenome generates the
+// genomes that are wrapped inside Competitors, the first Population contains
+// the hosts and the second one, if there is one, contains the parasites.
+func (cc *CompCoevo) Minimize(newGenome func(rng *rand.Rand) Genome) error {
+	if cc.GA.NPops != 1 && cc.GA.NPops != 2 {
+		return errors.New("NPops should be 1 or 2")
+	}
+	cc.Archives = make([]Individuals, cc.GA.NPops)
+	cc.err = nil
+	var (
+		callback  = cc.GA.Callback
+		earlyStop = cc.GA.EarlyStop
+	)
+	cc.GA.Callback = func(ga *GA) {
+		if cc.err = cc.assignFitnesses(ga); cc.err != nil {
+			return
+		}
+		if callback != nil {
+			callback(ga)
+		}
+	}
+	cc.GA.EarlyStop = func(ga *GA) bool {
+		return cc.err != nil || (earlyStop != nil && earlyStop(ga))
+	}
+	defer func() {
+		cc.GA.Callback = callback
+		cc.GA.EarlyStop = earlyStop
+	}()
+	var err = cc.GA.Minimize(func(rng *rand.Rand) Genome {
+		return &Competitor{Genome: newGenome(rng)}
+	})
+	if err != nil {
+		return err
+	}
+	return cc.err
+}
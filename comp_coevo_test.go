@@ -0,0 +1,141 @@
+package eaopt
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// Higher number wins
+func numberContest(a, b Genome) (float64, float64, error) {
+	var x, y = a.(Vector), b.(Vector)
+	var sx, sy = sumFloat64s(x), sumFloat64s(y)
+	switch {
+	case sx > sy:
+		return 0, 1, nil
+	case sx < sy:
+		return 1, 0, nil
+	}
+	return 0.5, 0.5, nil
+}
+
+func TestSchedulers(t *testing.T) {
+	var testCases = []struct {
+		sched                Scheduler
+		nA, nB, archA, archB int
+		same                 bool
+		nMatches             int
+	}{
+		{SchedRoundRobin{}, 4, 4, 0, 0, true, 6},
+		{SchedRoundRobin{}, 3, 5, 0, 0, false, 15},
+		{SchedRandom{NOpponents: 2}, 4, 4, 0, 0, true, 8},
+		{SchedRandom{NOpponents: 2}, 3, 5, 0, 0, false, 16},
+		{SchedHallOfFame{NOpponents: 2}, 4, 4, 0, 0, true, 8},
+		{SchedHallOfFame{NOpponents: 2}, 4, 4, 3, 3, true, 8},
+		{SchedHallOfFame{NOpponents: 2}, 3, 5, 3, 3, false, 16},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var matches = tc.sched.Apply(tc.nA, tc.nB, tc.archA, tc.archB, tc.same, newRand())
+			if len(matches) != tc.nMatches {
+				t.Errorf("Expected %d matches, got %d", tc.nMatches, len(matches))
+			}
+			for _, m := range matches {
+				if tc.same && m.A == m.B && !m.ArchivedA && !m.ArchivedB {
+					t.Errorf("Player %d was matched with itself", m.A)
+				}
+				var limA, limB = tc.nA, tc.nB
+				if m.ArchivedA {
+					limA = tc.archA
+				}
+				if m.ArchivedB {
+					limB = tc.archB
+				}
+				if m.A < 0 || m.A >= limA || m.B < 0 || m.B >= limB {
+					t.Errorf("Match %v is out of bounds", m)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulersValidate(t *testing.T) {
+	for _, sched := range []Scheduler{SchedRandom{}, SchedHallOfFame{}} {
+		if sched.Validate() == nil {
+			t.Errorf("Expected error for %v", sched)
+		}
+	}
+}
+
+func TestNewCompCoevoErrors(t *testing.T) {
+	var testCases = []struct {
+		nPops   uint
+		contest Contest
+		sched   Scheduler
+	}{
+		{0, numberContest, SchedRoundRobin{}},
+		{3, numberContest, SchedRoundRobin{}},
+		{1, nil, SchedRoundRobin{}},
+		{1, numberContest, nil},
+		{1, numberContest, SchedRandom{}},
+		{1, numberContest, SchedHallOfFame{NOpponents: 2}},
+		{2, numberContest, &SchedHallOfFame{NOpponents: 2}},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			if _, err := NewCompCoevo(tc.nPops, 10, 10, tc.contest, tc.sched, 0, nil); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestCompCoevoMinimize(t *testing.T) {
+	var schedulers = []Scheduler{
+		SchedRoundRobin{},
+		SchedRandom{NOpponents: 3},
+		SchedHallOfFame{NOpponents: 3},
+	}
+	for _, nPops := range []uint{1, 2} {
+		for i, sched := range schedulers {
+			t.Run(fmt.Sprintf("NPops %d TC %d", nPops, i), func(t *testing.T) {
+				var cc, err = NewCompCoevo(nPops, 10, 5, numberContest, sched, 4,
+					rand.New(rand.NewSource(42)))
+				if err != nil {
+					t.Errorf("Expected nil, got %v", err)
+				}
+				if err = cc.Minimize(NewVector); err != nil {
+					t.Errorf("Expected nil, got %v", err)
+				}
+				for _, pop := range cc.GA.Populations {
+					if !pop.Individuals.IsSortedByFitness() {
+						t.Error("Population should be sorted by fitness")
+					}
+					for _, indi := range pop.Individuals {
+						if indi.Fitness < 0 || indi.Fitness > 1 {
+							t.Errorf("Expected fitness in [0, 1], got %f", indi.Fitness)
+						}
+					}
+				}
+				for _, archive := range cc.Archives {
+					if len(archive) != 4 {
+						t.Errorf("Expected 4 archived champions, got %d", len(archive))
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestCompCoevoContestError(t *testing.T) {
+	var (
+		contest = func(a, b Genome) (float64, float64, error) {
+			return 0, 0, errors.New("")
+		}
+		cc, _ = NewCompCoevo(1, 10, 5, contest, SchedRoundRobin{}, 0, nil)
+	)
+	if err := cc.Minimize(NewVector); err == nil {
+		t.Error("Expected error")
+	}
+}
@@ -0,0 +1,356 @@
+package eaopt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// A Grouper decomposes the dimensions of a problem into disjoint groups of
+// variables. Each group is then evolved by a separate Population of a
+// CoopCoevo.
+type Grouper interface {
+	Apply(f func(x []float64) float64, nDims uint, min, max float64, rng *rand.Rand) ([][]int, error)
+	Validate() error
+}
+
+// splitDims splits a slice of dimensions into k contiguous groups whose sizes
+// differ by at most one.
+func splitDims(dims []int, k int) [][]int {
+	var (
+		groups = make([][]int, k)
+		n      = len(dims)
+		a      int
+	)
+	for i := range groups {
+		var b = a + n/k
+		if i < n%k {
+			b++
+		}
+		groups[i] = dims[a:b]
+		a = b
+	}
+	return groups
+}
+
+// GroupStatic splits the dimensions into NGroups contiguous groups of
+// (roughly) equal size.
+type GroupStatic struct {
+	NGroups uint
+}
+
+// Apply GroupStatic.
+func (gr GroupStatic) Apply(f func(x []float64) float64, nDims uint, min, max float64,
+	rng *rand.Rand) ([][]int, error) {
+	if gr.NGroups > nDims {
+		return nil, fmt.Errorf("cannot split %d dimensions into %d groups", nDims, gr.NGroups)
+	}
+	return splitDims(newInts(nDims), int(gr.NGroups)), nil
+}
+
+// Validate GroupStatic fields.
+func (gr GroupStatic) Validate() error {
+	if gr.NGroups == 0 {
+		return errors.New("NGroups should be higher than 0")
+	}
+	return nil
+}
+
+// GroupRandom shuffles the dimensions and then splits them into NGroups groups
+// of (roughly) equal size.
+type GroupRandom struct {
+	NGroups uint
+}
+
+// Apply GroupRandom.
+func (gr GroupRandom) Apply(f func(x []float64) float64, nDims uint, min, max float64,
+	rng *rand.Rand) ([][]int, error) {
+	if gr.NGroups > nDims {
+		return nil, fmt.Errorf("cannot split %d dimensions into %d groups", nDims, gr.NGroups)
+	}
+	var dims = newInts(nDims)
+	rng.Shuffle(len(dims), func(i, j int) { dims[i], dims[j] = dims[j], dims[i] })
+	return splitDims(dims, int(gr.NGroups)), nil
+}
+
+// Validate GroupRandom fields.
+func (gr GroupRandom) Validate() error {
+	if gr.NGroups == 0 {
+		return errors.New("NGroups should be higher than 0")
+	}
+	return nil
+}
+
+// GroupDifferential implements differential grouping. Two variables are
+// deemed to interact if the change in f caused by perturbing the first one
+// depends on the value of the second one by more than Epsilon. Interacting
+// variables are put in the same group whereas all the separable variables are
+// put together in a single group. Differential grouping costs O(n²)
+// evaluations of f.
+// Reference: https://doi.org/10.1109/TEVC.2013.2281543
+type GroupDifferential struct {
+	Epsilon float64
+}
+
+// Apply GroupDifferential.
+func (gr GroupDifferential) Apply(f func(x []float64) float64, nDims uint, min, max float64,
+	rng *rand.Rand) ([][]int, error) {
+	var (
+		groups    [][]int
+		separable []int
+		dims      = newInts(nDims)
+		mid       = (min + max) / 2
+	)
+	for len(dims) > 0 {
+		var (
+			i     = dims[0]
+			group = []int{i}
+			rest  []int
+		)
+		for _, j := range dims[1:] {
+			var p1, p2 = make([]float64, nDims), make([]float64, nDims)
+			for k := range p1 {
+				p1[k], p2[k] = min, min
+			}
+			p2[i] = max
+			var delta1 = f(p1) - f(p2)
+			p1[j], p2[j] = mid, mid
+			var delta2 = f(p1) - f(p2)
+			if math.Abs(delta1-delta2) > gr.Epsilon {
+				group = append(group, j)
+			} else {
+				rest = append(rest, j)
+			}
+		}
+		if len(group) == 1 {
+			separable = append(separable, i)
+		} else {
+			groups = append(groups, group)
+		}
+		dims = rest
+	}
+	if len(separable) > 0 {
+		groups = append(groups, separable)
+	}
+	return groups, nil
+}
+
+// Validate GroupDifferential fields.
+func (gr GroupDifferential) Validate() error {
+	if gr.Epsilon <= 0 {
+		return errors.New("epsilon should be positive")
+	}
+	return nil
+}
+
+// A CoopBlock is the part of a solution that is evolved by one of the
+// Populations of a CoopCoevo. It is evaluated by plugging it into the current
+// context vector, which is made of the representatives of the other
+// Populations.
+type CoopBlock struct {
+	x             []float64
+	group         int
+	Collaborators []string // IDs of the representatives used during the last evaluation, indexed by Population
+	CC            *CoopCoevo
+}
+
+// Evaluate the CoopBlock by combining it with the representatives of the other
+// Populations. The IDs of the representatives are recorded in the
+// Collaborators field.
+func (b *CoopBlock) Evaluate() (float64, error) {
+	var x = b.CC.combine(b.group, b.x)
+	b.Collaborators = make([]string, len(b.CC.repIDs))
+	copy(b.Collaborators, b.CC.repIDs)
+	b.Collaborators[b.group] = ""
+	var y = b.CC.F(x)
+	b.CC.mutex.Lock()
+	if y < b.CC.BestY {
+		b.CC.BestX = x
+		b.CC.BestY = y
+	}
+	b.CC.mutex.Unlock()
+	return y, nil
+}
+
+// Mutate the CoopBlock by adding Gaussian noise to each of it's values with
+// probability 1/n.
+func (b *CoopBlock) Mutate(rng *rand.Rand) {
+	var rate = 1 / float64(len(b.x))
+	for i := range b.x {
+		if rng.Float64() < rate {
+			b.x[i] += rng.NormFloat64() * b.CC.Sigma * (b.CC.Max - b.CC.Min)
+		}
+	}
+	b.clip()
+}
+
+// Crossover a CoopBlock with another CoopBlock by using uniform crossover.
+func (b *CoopBlock) Crossover(q Genome, rng *rand.Rand) {
+	CrossUniformFloat64(b.x, q.(*CoopBlock).x, rng)
+}
+
+// Clone returns a deep copy of a CoopBlock.
+func (b CoopBlock) Clone() Genome {
+	var clone = &CoopBlock{
+		x:     copyFloat64s(b.x),
+		group: b.group,
+		CC:    b.CC,
+	}
+	if b.Collaborators != nil {
+		clone.Collaborators = make([]string, len(b.Collaborators))
+		copy(clone.Collaborators, b.Collaborators)
+	}
+	return clone
+}
+
+func (b *CoopBlock) clip() {
+	for i, v := range b.x {
+		b.x[i] = math.Max(b.CC.Min, math.Min(b.CC.Max, v))
+	}
+}
+
+// CoopCoevo implements cooperative coevolution. The dimensions of the problem
+// are split into groups by a Grouper and each group is evolved by one of the
+// GA's Populations. Once each generation is over the best CoopBlock of each
+// Population becomes the representative that the other Populations are
+// evaluated with.
+// Reference: https://doi.org/10.1162/106365600568086
+type CoopCoevo struct {
+	Min, Max float64 // Boundaries for the values
+	Sigma    float64 // Standard deviation of the mutation, relative to Max - Min
+	Grouper  Grouper
+	Groups   [][]int // Groups of dimensions, set by Minimize
+	F        func(x []float64) float64
+	BestX    []float64 // Best vector encountered
+	BestY    float64   // Value of F at BestX
+	GA       *GA
+
+	context []float64 // Values of the current representatives
+	repIDs  []string  // IDs of the current representatives
+	mutex   sync.Mutex
+}
+
+// NewCoopCoevo instantiates and returns a CoopCoevo instance after having
+// checked for input errors. The number of Populations of the GA is determined
+// by the Grouper once Minimize is called.
+func NewCoopCoevo(popSize, nSteps uint, min, max, sigma float64, grouper Grouper,
+	parallel bool, rng *rand.Rand) (*CoopCoevo, error) {
+	// Check inputs
+	if min >= max {
+		return nil, errors.New("min should be stricly inferior to max")
+	}
+	if sigma <= 0 {
+		return nil, errors.New("sigma should be positive")
+	}
+	if grouper == nil {
+		return nil, errors.New("grouper has to be provided")
+	}
+	if err := grouper.Validate(); err != nil {
+		return nil, err
+	}
+	if rng == nil {
+		rng = newRand()
+	}
+	// Instantiate a GA
+	var ga, err = GAConfig{
+		NPops:        1,
+		PopSize:      popSize,
+		NGenerations: nSteps,
+		HofSize:      1,
+		Model: ModGenerational{
+			Selector:  SelTournament{NContestants: 3},
+			MutRate:   0.5,
+			CrossRate: 0.7,
+		},
+		ParallelEval: parallel,
+		RNG:          rand.New(rand.NewSource(rng.Int63())),
+	}.NewGA()
+	if err != nil {
+		return nil, err
+	}
+	return &CoopCoevo{
+		Min:     min,
+		Max:     max,
+		Sigma:   sigma,
+		Grouper: grouper,
+		GA:      ga,
+	}, nil
+}
+
+// NewDefaultCoopCoevo calls NewCoopCoevo with default values.
+func NewDefaultCoopCoevo() (*CoopCoevo, error) {
+	return NewCoopCoevo(30, 50, -5, 5, 0.1, GroupStatic{NGroups: 2}, false, nil)
+}
+
+// combine returns a copy of the context vector where the values of group i
+// have been replaced with x.
+func (cc *CoopCoevo) combine(i int, x []float64) []float64 {
+	var full = copyFloat64s(cc.context)
+	for j, dim := range cc.Groups[i] {
+		full[dim] = x[j]
+	}
+	return full
+}
+
+func (cc *CoopCoevo) newBlock(i int, rng *rand.Rand) Genome {
+	return &CoopBlock{
+		x:     InitUnifFloat64(uint(len(cc.Groups[i])), cc.Min, cc.Max, rng),
+		group: i,
+		CC:    cc,
+	}
+}
+
+// updateRepresentatives makes the best Individual of each Population the
+// representative of it's group. Every Individual is then flagged for
+// re-evaluation because the context it was evaluated in has changed.
+func (cc *CoopCoevo) updateRepresentatives(ga *GA) {
+	for i, pop := range ga.Populations {
+		var best = pop.Individuals[0]
+		for _, indi := range pop.Individuals[1:] {
+			if indi.Fitness < best.Fitness {
+				best = indi
+			}
+		}
+		for j, dim := range cc.Groups[i] {
+			cc.context[dim] = best.Genome.(*CoopBlock).x[j]
+		}
+		cc.repIDs[i] = best.ID
+	}
+	for i := range ga.Populations {
+		for j := range ga.Populations[i].Individuals {
+			ga.Populations[i].Individuals[j].Evaluated = false
+		}
+	}
+}
+
+// Minimize finds the minimum of a given real-valued function.
+func (cc *CoopCoevo) Minimize(f func([]float64) float64, nDims uint) ([]float64, float64, error) {
+	// Decompose the problem
+	var groups, err = cc.Grouper.Apply(f, nDims, cc.Min, cc.Max, cc.GA.RNG)
+	if err != nil {
+		return nil, 0, err
+	}
+	cc.F = f
+	cc.Groups = groups
+	cc.BestX = nil
+	cc.BestY = math.Inf(1)
+	// The initial context is random because the representatives are not known
+	// yet
+	cc.context = InitUnifFloat64(nDims, cc.Min, cc.Max, cc.GA.RNG)
+	cc.repIDs = make([]string, len(groups))
+	// Evolve one Population per group and update the representatives after
+	// each generation
+	cc.GA.NPops = uint(len(groups))
+	var callback = cc.GA.Callback
+	cc.GA.Callback = func(ga *GA) {
+		cc.updateRepresentatives(ga)
+		if callback != nil {
+			callback(ga)
+		}
+	}
+	defer func() { cc.GA.Callback = callback }()
+	err = cc.GA.minimizeIslands(cc.newBlock)
+	return cc.BestX, cc.BestY, err
+}
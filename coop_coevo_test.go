@@ -0,0 +1,140 @@
+package eaopt
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func sphere(x []float64) (y float64) {
+	for _, xi := range x {
+		y += xi * xi
+	}
+	return
+}
+
+func TestGroupers(t *testing.T) {
+	var testCases = []struct {
+		grouper Grouper
+		nDims   uint
+		nGroups int
+	}{
+		{GroupStatic{NGroups: 3}, 10, 3},
+		{GroupStatic{NGroups: 1}, 4, 1},
+		{GroupRandom{NGroups: 4}, 10, 4},
+		{GroupDifferential{Epsilon: 1e-3}, 5, 1},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var groups, err = tc.grouper.Apply(sphere, tc.nDims, -5, 5, newRand())
+			if err != nil {
+				t.Errorf("Expected nil, got %v", err)
+			}
+			if len(groups) != tc.nGroups {
+				t.Errorf("Expected %d groups, got %d", tc.nGroups, len(groups))
+			}
+			// Check each dimension is present exactly once
+			var dims []int
+			for _, group := range groups {
+				dims = append(dims, group...)
+			}
+			sort.Ints(dims)
+			if !reflect.DeepEqual(dims, newInts(tc.nDims)) {
+				t.Errorf("Expected %v, got %v", newInts(tc.nDims), dims)
+			}
+		})
+	}
+}
+
+func TestGroupersTooManyGroups(t *testing.T) {
+	for _, grouper := range []Grouper{GroupStatic{NGroups: 5}, GroupRandom{NGroups: 5}} {
+		if _, err := grouper.Apply(sphere, 4, -5, 5, newRand()); err == nil {
+			t.Error("Expected error")
+		}
+	}
+}
+
+func TestGroupDifferential(t *testing.T) {
+	// x0 and x2 interact, as do x1 and x4, x3 is separable
+	var (
+		f = func(x []float64) float64 {
+			return x[0]*x[2] + x[1]*x[4] + x[3]*x[3]
+		}
+		groups, _ = GroupDifferential{Epsilon: 1e-6}.Apply(f, 5, -1, 1, newRand())
+		expected  = [][]int{{0, 2}, {1, 4}, {3}}
+	)
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("Expected %v, got %v", expected, groups)
+	}
+}
+
+func TestGroupersValidate(t *testing.T) {
+	var invalid = []Grouper{
+		GroupStatic{},
+		GroupRandom{},
+		GroupDifferential{},
+	}
+	for _, grouper := range invalid {
+		if grouper.Validate() == nil {
+			t.Errorf("Expected error for %v", grouper)
+		}
+	}
+}
+
+func TestNewCoopCoevoErrors(t *testing.T) {
+	var testCases = []struct {
+		min, max, sigma float64
+		grouper         Grouper
+	}{
+		{1, 0, 0.1, GroupStatic{NGroups: 2}},
+		{0, 1, 0, GroupStatic{NGroups: 2}},
+		{0, 1, 0.1, nil},
+		{0, 1, 0.1, GroupStatic{}},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var _, err = NewCoopCoevo(10, 10, tc.min, tc.max, tc.sigma, tc.grouper, false, nil)
+			if err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestCoopCoevoMinimize(t *testing.T) {
+	var cc, err = NewCoopCoevo(20, 30, -5, 5, 0.1, GroupStatic{NGroups: 3}, false,
+		rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	x, y, err := cc.Minimize(sphere, 6)
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if len(cc.GA.Populations) != 3 {
+		t.Errorf("Expected 3 populations, got %d", len(cc.GA.Populations))
+	}
+	if len(x) != 6 {
+		t.Errorf("Expected 6 dimensions, got %d", len(x))
+	}
+	if y != sphere(x) {
+		t.Errorf("Expected %f, got %f", sphere(x), y)
+	}
+	if y > 1 {
+		t.Errorf("Expected a value lower than 1, got %f", y)
+	}
+	// Check the collaborators have been recorded
+	for i, pop := range cc.GA.Populations {
+		var collabs = pop.Individuals[0].Genome.(*CoopBlock).Collaborators
+		if len(collabs) != 3 {
+			t.Errorf("Expected 3 collaborators, got %d", len(collabs))
+		}
+		for j, id := range collabs {
+			if (i == j) != (id == "") {
+				t.Errorf("Unexpected collaborator %q for population %d", id, i)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package eaopt
 
 import (
+	"math"
 	"math/rand"
 	"sort"
 )
@@ -22,269 +23,500 @@ func CrossUniformFloat64(p1 []float64, p2 []float64, rng *rand.Rand) {
 	}
 }
 
+// CrossSBXFloat64 applies simulated binary crossover to two float64 slices.
+// Each pair of genes is recombined with probability 0.5 so as to mimic the
+// spread of offsprings produced by one-point crossover on binary strings. The
+// distribution index eta controls how far the offsprings can be from their
+// parents, large values produce offsprings close to the parents. The
+// probability distribution is truncated so that gene i stays in [lower[i],
+// upper[i]].
+// Reference: https://www.complex-systems.com/abstracts/v09_i02_a02/
+func CrossSBXFloat64(p1, p2 []float64, eta float64, lower, upper []float64, rng *rand.Rand) {
+	var betaq = func(beta, u float64) float64 {
+		var alpha = 2 - math.Pow(beta, -(eta+1))
+		if u <= 1/alpha {
+			return math.Pow(u*alpha, 1/(eta+1))
+		}
+		return math.Pow(1/(2-u*alpha), 1/(eta+1))
+	}
+	for i := range p1 {
+		if rng.Float64() > 0.5 || math.Abs(p1[i]-p2[i]) < 1e-14 {
+			continue
+		}
+		var (
+			y1 = math.Min(p1[i], p2[i])
+			y2 = math.Max(p1[i], p2[i])
+			u  = rng.Float64()
+			c1 = 0.5 * ((y1 + y2) - betaq(1+2*(y1-lower[i])/(y2-y1), u)*(y2-y1))
+			c2 = 0.5 * ((y1 + y2) + betaq(1+2*(upper[i]-y2)/(y2-y1), u)*(y2-y1))
+		)
+		c1 = clampFloat64(c1, lower[i], upper[i])
+		c2 = clampFloat64(c2, lower[i], upper[i])
+		if rng.Float64() < 0.5 {
+			c1, c2 = c2, c1
+		}
+		p1[i], p2[i] = c1, c2
+	}
+}
+
+// CrossBLXFloat64 applies blend crossover to two float64 slices. For each gene
+// the offsprings are sampled uniformly in the interval spanned by the parents,
+// extended on both sides by alpha times its width. The interval is clipped to
+// [lower[i], upper[i]]. alpha = 0.5 is a common choice.
+func CrossBLXFloat64(p1, p2 []float64, alpha float64, lower, upper []float64, rng *rand.Rand) {
+	for i := range p1 {
+		var (
+			d  = math.Abs(p1[i] - p2[i])
+			lo = math.Max(math.Min(p1[i], p2[i])-alpha*d, lower[i])
+			hi = math.Min(math.Max(p1[i], p2[i])+alpha*d, upper[i])
+		)
+		p1[i] = lo + rng.Float64()*(hi-lo)
+		p2[i] = lo + rng.Float64()*(hi-lo)
+	}
+}
+
+// CrossArithmeticFloat64 applies whole arithmetic crossover to two float64
+// slices. A single random weight p is drawn and the offsprings are p*p1 +
+// (1-p)*p2 and (1-p)*p1 + p*p2. The offsprings lie on the segment between the
+// parents, hence they satisfy any bounds the parents satisfy.
+func CrossArithmeticFloat64(p1, p2 []float64, rng *rand.Rand) {
+	var p = rng.Float64()
+	for i := range p1 {
+		p1[i], p2[i] = p*p1[i]+(1-p)*p2[i], (1-p)*p1[i]+p*p2[i]
+	}
+}
+
+// CrossIntermediateFloat64 applies extended intermediate crossover to two
+// float64 slices. Each gene of each offspring is p1[i] + a*(p2[i]-p1[i]) where
+// a is drawn uniformly in [-d, 1+d] for each gene, which allows the offsprings
+// to lie slightly outside of the hyper-rectangle defined by their parents.
+// d = 0.25 is a common choice. Gene i is clipped to [lower[i], upper[i]].
+func CrossIntermediateFloat64(p1, p2 []float64, d float64, lower, upper []float64, rng *rand.Rand) {
+	for i := range p1 {
+		var (
+			a1 = -d + rng.Float64()*(1+2*d)
+			a2 = -d + rng.Float64()*(1+2*d)
+			o1 = p1[i] + a1*(p2[i]-p1[i])
+			o2 = p1[i] + a2*(p2[i]-p1[i])
+		)
+		p1[i] = clampFloat64(o1, lower[i], upper[i])
+		p2[i] = clampFloat64(o2, lower[i], upper[i])
+	}
+}
+
+// CrossUniformInt exchanges each gene of two int slices with probability 0.5.
+// Contrary to CrossUniformFloat64 the genes are not blended, hence the
+// offsprings satisfy any bounds the parents satisfy.
+func CrossUniformInt(p1, p2 []int, rng *rand.Rand) {
+	for i := range p1 {
+		if rng.Float64() < 0.5 {
+			p1[i], p2[i] = p2[i], p1[i]
+		}
+	}
+}
+
+// CrossSBXInt applies simulated binary crossover to two int slices by running
+// CrossSBXFloat64 on their values and rounding the offsprings to the nearest
+// integers in [lower[i], upper[i]].
+func CrossSBXInt(p1, p2 []int, eta float64, lower, upper []int, rng *rand.Rand) {
+	var (
+		n      = len(p1)
+		f1, f2 = make([]float64, n), make([]float64, n)
+		lo, hi = make([]float64, n), make([]float64, n)
+	)
+	for i := range p1 {
+		f1[i], f2[i] = float64(p1[i]), float64(p2[i])
+		lo[i], hi[i] = float64(lower[i]), float64(upper[i])
+	}
+	CrossSBXFloat64(f1, f2, eta, lo, hi, rng)
+	for i := range p1 {
+		p1[i] = clampInt(int(math.Round(f1[i])), lower[i], upper[i])
+		p2[i] = clampInt(int(math.Round(f2[i])), lower[i], upper[i])
+	}
+}
+
+// orthogonalNoise returns a vector of normal noise with standard deviation std
+// from which the component along direction d has been removed.
+func orthogonalNoise(d []float64, std float64, rng *rand.Rand) []float64 {
+	var (
+		z        = make([]float64, len(d))
+		dot, nd2 float64
+	)
+	for i := range z {
+		z[i] = rng.NormFloat64() * std
+		dot += z[i] * d[i]
+		nd2 += d[i] * d[i]
+	}
+	if nd2 > 0 {
+		for i := range z {
+			z[i] -= dot / nd2 * d[i]
+		}
+	}
+	return z
+}
+
+// perpendicularDistance returns the distance between x and the line that goes
+// through origin with direction d.
+func perpendicularDistance(x, origin, d []float64) float64 {
+	var dot, nd2, nx2 float64
+	for i := range x {
+		var v = x[i] - origin[i]
+		dot += v * d[i]
+		nd2 += d[i] * d[i]
+		nx2 += v * v
+	}
+	if nd2 == 0 {
+		return math.Sqrt(nx2)
+	}
+	return math.Sqrt(math.Max(nx2-dot*dot/nd2, 0))
+}
+
+// CrossUNDXFloat64 applies unimodal normal distribution crossover to three
+// float64 slices and returns one offspring. The offspring is sampled around
+// the midpoint of the first two parents: along the line joining them with
+// standard deviation sigmaXi times their distance, and orthogonally to that
+// line with standard deviation sigmaEta times the distance between the third
+// parent and the line. Ono and Kobayashi recommend sigmaXi = 0.5 and sigmaEta
+// = 0.35/sqrt(n). Gene i is clipped to [lower[i], upper[i]].
+// Reference: https://doi.org/10.1527/tjsai.14.1146
+func CrossUNDXFloat64(parents [][]float64, sigmaXi, sigmaEta float64, lower, upper []float64, rng *rand.Rand) []float64 {
+	var (
+		p1, p2, p3 = parents[0], parents[1], parents[2]
+		n          = len(p1)
+		m          = make([]float64, n)
+		d          = make([]float64, n)
+	)
+	for i := range m {
+		m[i] = (p1[i] + p2[i]) / 2
+		d[i] = p2[i] - p1[i]
+	}
+	var (
+		dist      = perpendicularDistance(p3, p1, d)
+		xi        = rng.NormFloat64() * sigmaXi
+		eta       = orthogonalNoise(d, sigmaEta*dist, rng)
+		offspring = make([]float64, n)
+	)
+	for i := range offspring {
+		offspring[i] = clampFloat64(m[i]+xi*d[i]+eta[i], lower[i], upper[i])
+	}
+	return offspring
+}
+
+// CrossPCXFloat64 applies parent-centric crossover to two or more float64
+// slices and returns one offspring. The offspring is sampled around the first
+// parent: along the direction joining the centroid of the parents to the first
+// parent with standard deviation sigmaZeta times their distance, and
+// orthogonally to that direction with standard deviation sigmaEta times the
+// average distance of the other parents to that direction. Deb et al.
+// recommend sigmaZeta = sigmaEta = 0.1. Gene i is clipped to [lower[i],
+// upper[i]].
+// Reference: https://doi.org/10.1162/106365602760972767
+func CrossPCXFloat64(parents [][]float64, sigmaZeta, sigmaEta float64, lower, upper []float64, rng *rand.Rand) []float64 {
+	var (
+		n = len(parents[0])
+		g = make([]float64, n)
+		d = make([]float64, n)
+	)
+	for _, p := range parents {
+		for i := range g {
+			g[i] += p[i] / float64(len(parents))
+		}
+	}
+	for i := range d {
+		d[i] = parents[0][i] - g[i]
+	}
+	var meanDist float64
+	for _, p := range parents[1:] {
+		meanDist += perpendicularDistance(p, g, d) / float64(len(parents)-1)
+	}
+	var (
+		zeta      = rng.NormFloat64() * sigmaZeta
+		eta       = orthogonalNoise(d, sigmaEta*meanDist, rng)
+		offspring = make([]float64, n)
+	)
+	for i := range offspring {
+		offspring[i] = clampFloat64(parents[0][i]+zeta*d[i]+eta[i], lower[i], upper[i])
+	}
+	return offspring
+}
+
 // Generic mutations for slices
 
+// crossBoxed applies a generic crossover to two Slices by boxing their genes.
+func crossBoxed(p1, p2 Slice, cross func(s1, s2 []interface{})) {
+	var s1, s2 = boxSlice(p1), boxSlice(p2)
+	cross(s1, s2)
+	unboxSlice(p1, s1)
+	unboxSlice(p2, s2)
+}
+
 // Contains the deterministic part of the GNX method for testing purposes.
-func gnx(p1, p2 Slice, indexes []int) {
+func gnx[T any](p1, p2 []T, indexes []int) {
 	var (
-		n      = p1.Len()
-		o1     = p1.Copy()
-		o2     = p2.Copy()
+		n      = len(p1)
 		toggle = true
 	)
 	// Add the first and last indexes
 	indexes = append([]int{0}, indexes...)
 	indexes = append(indexes, n)
 	for i := 0; i < len(indexes)-1; i++ {
-		if toggle {
-			o1.Slice(indexes[i], indexes[i+1]).Replace(p1.Slice(indexes[i], indexes[i+1]))
-			o2.Slice(indexes[i], indexes[i+1]).Replace(p2.Slice(indexes[i], indexes[i+1]))
-		} else {
-			o1.Slice(indexes[i], indexes[i+1]).Replace(p2.Slice(indexes[i], indexes[i+1]))
-			o2.Slice(indexes[i], indexes[i+1]).Replace(p1.Slice(indexes[i], indexes[i+1]))
+		if !toggle {
+			for j := indexes[i]; j < indexes[i+1]; j++ {
+				p1[j], p2[j] = p2[j], p1[j]
+			}
 		}
 		toggle = !toggle // Alternate for the new copying
 	}
-	p1.Replace(o1)
-	p2.Replace(o2)
 }
 
-// CrossGNX (Generalized N-point Crossover). An identical point is chosen on
+// CrossGNXOf (Generalized N-point Crossover). An identical point is chosen on
 // each parent's genome and the mirroring segments are switched. n determines
 // the number of crossovers (aka mirroring segments) to perform. n has to be
 // equal or lower than the number of genes in each parent.
-func CrossGNX(p1 Slice, p2 Slice, n uint, rng *rand.Rand) {
-	var indexes = randomInts(n, 1, p1.Len(), rng)
+func CrossGNXOf[T any](p1 []T, p2 []T, n uint, rng *rand.Rand) {
+	var indexes = randomInts(n, 1, len(p1), rng)
 	sort.Ints(indexes)
 	gnx(p1, p2, indexes)
 }
 
-// CrossGNXInt calls CrossGNX on two int slices.
+// CrossGNX calls CrossGNXOf on two Slices.
+func CrossGNX(p1 Slice, p2 Slice, n uint, rng *rand.Rand) {
+	crossBoxed(p1, p2, func(s1, s2 []interface{}) { CrossGNXOf(s1, s2, n, rng) })
+}
+
+// CrossGNXInt calls CrossGNXOf on two int slices.
 func CrossGNXInt(s1 []int, s2 []int, n uint, rng *rand.Rand) {
-	CrossGNX(IntSlice(s1), IntSlice(s2), n, rng)
+	CrossGNXOf(s1, s2, n, rng)
 }
 
-// CrossGNXFloat64 calls CrossGNX on two float64 slices.
+// CrossGNXFloat64 calls CrossGNXOf on two float64 slices.
 func CrossGNXFloat64(s1 []float64, s2 []float64, n uint, rng *rand.Rand) {
-	CrossGNX(Float64Slice(s1), Float64Slice(s2), n, rng)
+	CrossGNXOf(s1, s2, n, rng)
 }
 
-// CrossGNXString calls CrossGNX on two string slices.
+// CrossGNXString calls CrossGNXOf on two string slices.
 func CrossGNXString(s1 []string, s2 []string, n uint, rng *rand.Rand) {
-	CrossGNX(StringSlice(s1), StringSlice(s2), n, rng)
+	CrossGNXOf(s1, s2, n, rng)
 }
 
 // Contains the deterministic part of the PMX method for testing purposes.
-func pmx(p1, p2 Slice, a, b int) {
+func pmx[T comparable](p1, p2 []T, a, b int) {
 	var (
-		n  = p1.Len()
-		o1 = p1.Copy()
-		o2 = p2.Copy()
+		n  = len(p1)
+		o1 = append([]T(nil), p1...)
+		o2 = append([]T(nil), p2...)
 	)
 	// Create lookup maps to quickly see if a gene has been visited
 	var (
-		p1Visited, p2Visited = make(set), make(set)
-		o1Visited, o2Visited = make(set), make(set)
+		p1Visited, p2Visited = make(map[T]bool), make(map[T]bool)
+		o1Visited, o2Visited = make([]bool, n), make([]bool, n)
 	)
 	for i := a; i < b; i++ {
-		p1Visited[p1.At(i)] = true
-		p2Visited[p2.At(i)] = true
+		p1Visited[p1[i]] = true
+		p2Visited[p2[i]] = true
 		o1Visited[i] = true
 		o2Visited[i] = true
 	}
 	for i := a; i < b; i++ {
 		// Find the element in the second parent that has not been copied in the first offspring
-		if !p1Visited[p2.At(i)] {
+		if !p1Visited[p2[i]] {
 			var j = i
 			for o1Visited[j] {
-				j, _ = search(o1.At(j), p2)
+				j, _ = search(o1[j], p2)
 			}
-			o1.Set(j, p2.At(i))
+			o1[j] = p2[i]
 			o1Visited[j] = true
 		}
 		// Find the element in the first parent that has not been copied in the second offspring
-		if !p2Visited[p1.At(i)] {
+		if !p2Visited[p1[i]] {
 			var j = i
 			for o2Visited[j] {
-				j, _ = search(o2.At(j), p1)
+				j, _ = search(o2[j], p1)
 			}
-			o2.Set(j, p1.At(i))
+			o2[j] = p1[i]
 			o2Visited[j] = true
 		}
 	}
 	// Fill in the offspring's missing values with the opposite parent's values
 	for i := 0; i < n; i++ {
 		if !o1Visited[i] {
-			o1.Set(i, p2.At(i))
+			o1[i] = p2[i]
 		}
 		if !o2Visited[i] {
-			o2.Set(i, p1.At(i))
+			o2[i] = p1[i]
 		}
 	}
-	p1.Replace(o1)
-	p2.Replace(o2)
+	copy(p1, o1)
+	copy(p2, o2)
 }
 
-// CrossPMX (Partially Mapped Crossover). The offsprings are generated by
+// CrossPMXOf (Partially Mapped Crossover). The offsprings are generated by
 // copying one of the parents and then copying the other parent's values up to a
 // randomly chosen crossover point. Each gene that is replaced is permuted with
 // the gene that is copied in the first parent's genome. Two offsprings are
 // generated in such a way (because there are two parents). The PMX method
 // preserves gene uniqueness.
-func CrossPMX(p1 Slice, p2 Slice, rng *rand.Rand) {
-	var indexes = randomInts(2, 1, p1.Len(), rng)
+func CrossPMXOf[T comparable](p1 []T, p2 []T, rng *rand.Rand) {
+	var indexes = randomInts(2, 1, len(p1), rng)
 	sort.Ints(indexes)
 	pmx(p1, p2, indexes[0], indexes[1])
 }
 
-// CrossPMXInt calls CrossPMX on an int slice.
+// CrossPMX calls CrossPMXOf on two Slices.
+func CrossPMX(p1 Slice, p2 Slice, rng *rand.Rand) {
+	crossBoxed(p1, p2, func(s1, s2 []interface{}) { CrossPMXOf(s1, s2, rng) })
+}
+
+// CrossPMXInt calls CrossPMXOf on an int slice.
 func CrossPMXInt(s1 []int, s2 []int, rng *rand.Rand) {
-	CrossPMX(IntSlice(s1), IntSlice(s2), rng)
+	CrossPMXOf(s1, s2, rng)
 }
 
-// CrossPMXFloat64 calls CrossPMX on a float64 slice.
+// CrossPMXFloat64 calls CrossPMXOf on a float64 slice.
 func CrossPMXFloat64(s1 []float64, s2 []float64, rng *rand.Rand) {
-	CrossPMX(Float64Slice(s1), Float64Slice(s2), rng)
+	CrossPMXOf(s1, s2, rng)
 }
 
-// CrossPMXString calls CrossPMX on a string slice.
+// CrossPMXString calls CrossPMXOf on a string slice.
 func CrossPMXString(s1 []string, s2 []string, rng *rand.Rand) {
-	CrossPMX(StringSlice(s1), StringSlice(s2), rng)
+	CrossPMXOf(s1, s2, rng)
 }
 
 // Contains the deterministic part of the OX method for testing purposes.
-func ox(p1, p2 Slice, a, b int) {
+func ox[T comparable](p1, p2 []T, a, b int) {
 	var (
-		n  = p1.Len()
-		o1 = p1.Copy()
-		o2 = p2.Copy()
+		n  = len(p1)
+		o1 = append([]T(nil), p1...)
+		o2 = append([]T(nil), p2...)
 	)
 	// Create lookup maps to quickly see if a gene has been copied from a parent or not
-	var p1Occurences, p2Occurences = make(setInt), make(setInt)
+	var p1Occurences, p2Occurences = make(map[T]int), make(map[T]int)
 	for i := b; i < a+n; i++ {
 		var k = i % n
-		p1Occurences[p1.At(k)]++
-		p2Occurences[p2.At(k)]++
+		p1Occurences[p1[k]]++
+		p2Occurences[p2[k]]++
 	}
 	// Keep two indicators to know where to fill the offsprings
 	var j1, j2 = b, b
 	for i := b; i < b+n; i++ {
 		var k = i % n
-		if p1Occurences[p2.At(k)] > 0 {
-			p1Occurences[p2.At(k)]--
-			o1.Set(j1%n, p2.At(k))
+		if p1Occurences[p2[k]] > 0 {
+			p1Occurences[p2[k]]--
+			o1[j1%n] = p2[k]
 			j1++
 		}
-		if p2Occurences[p1.At(k)] > 0 {
-			p2Occurences[p1.At(k)]--
-			o2.Set(j2%n, p1.At(k))
+		if p2Occurences[p1[k]] > 0 {
+			p2Occurences[p1[k]]--
+			o2[j2%n] = p1[k]
 			j2++
 		}
 	}
-	p1.Replace(o1)
-	p2.Replace(o2)
+	copy(p1, o1)
+	copy(p2, o2)
 }
 
-// CrossOX (Ordered Crossover). Part of the first parent's genome is copied onto
-// the first offspring's genome. Then the second parent's genome is iterated
-// over, starting on the right of the part that was copied. Each gene of the
-// second parent's genome is copied onto the next blank gene of the first
-// offspring's genome if it wasn't already copied from the first parent. The OX
-// method preserves gene uniqueness.
-func CrossOX(p1 Slice, p2 Slice, rng *rand.Rand) {
-	var indexes = randomInts(2, 1, p1.Len(), rng)
+// CrossOXOf (Ordered Crossover). Part of the first parent's genome is copied
+// onto the first offspring's genome. Then the second parent's genome is
+// iterated over, starting on the right of the part that was copied. Each gene
+// of the second parent's genome is copied onto the next blank gene of the
+// first offspring's genome if it wasn't already copied from the first parent.
+// The OX method preserves gene uniqueness.
+func CrossOXOf[T comparable](p1 []T, p2 []T, rng *rand.Rand) {
+	var indexes = randomInts(2, 1, len(p1), rng)
 	sort.Ints(indexes)
 	ox(p1, p2, indexes[0], indexes[1])
 }
 
-// CrossOXInt calls CrossOX on a int slice.
+// CrossOX calls CrossOXOf on two Slices.
+func CrossOX(p1 Slice, p2 Slice, rng *rand.Rand) {
+	crossBoxed(p1, p2, func(s1, s2 []interface{}) { CrossOXOf(s1, s2, rng) })
+}
+
+// CrossOXInt calls CrossOXOf on a int slice.
 func CrossOXInt(s1 []int, s2 []int, rng *rand.Rand) {
-	CrossOX(IntSlice(s1), IntSlice(s2), rng)
+	CrossOXOf(s1, s2, rng)
 }
 
-// CrossOXFloat64 calls CrossOX on a float64 slice.
+// CrossOXFloat64 calls CrossOXOf on a float64 slice.
 func CrossOXFloat64(s1 []float64, s2 []float64, rng *rand.Rand) {
-	CrossOX(Float64Slice(s1), Float64Slice(s2), rng)
+	CrossOXOf(s1, s2, rng)
 }
 
-// CrossOXString calls CrossOX on a string slice.
+// CrossOXString calls CrossOXOf on a string slice.
 func CrossOXString(s1 []string, s2 []string, rng *rand.Rand) {
-	CrossOX(StringSlice(s1), StringSlice(s2), rng)
+	CrossOXOf(s1, s2, rng)
 }
 
-// CrossCX (Cycle Crossover). Cycles between the parents are indentified, they
-// are then copied alternatively onto the offsprings. The CX method is
+// CrossCXOf (Cycle Crossover). Cycles between the parents are indentified,
+// they are then copied alternatively onto the offsprings. The CX method is
 // deterministic and preserves gene uniqueness.
-func CrossCX(p1, p2 Slice) {
+func CrossCXOf[T comparable](p1, p2 []T) {
 	var (
-		o1     = p1.Copy()
-		o2     = p2.Copy()
 		cycles = getCycles(p1, p2)
 		toggle = true
 	)
 	for i := 0; i < len(cycles); i++ {
-		for _, j := range cycles[i] {
-			if toggle {
-				o1.Set(j, p1.At(j))
-				o2.Set(j, p2.At(j))
-			} else {
-				o2.Set(j, p1.At(j))
-				o1.Set(j, p2.At(j))
+		if !toggle {
+			for _, j := range cycles[i] {
+				p1[j], p2[j] = p2[j], p1[j]
 			}
 		}
 		toggle = !toggle
 	}
-	p1.Replace(o1)
-	p2.Replace(o2)
 }
 
-// CrossCXInt calls CrossCX on an int slice.
+// CrossCX calls CrossCXOf on two Slices.
+func CrossCX(p1, p2 Slice) {
+	crossBoxed(p1, p2, func(s1, s2 []interface{}) { CrossCXOf(s1, s2) })
+}
+
+// CrossCXInt calls CrossCXOf on an int slice.
 func CrossCXInt(s1 []int, s2 []int) {
-	CrossCX(IntSlice(s1), IntSlice(s2))
+	CrossCXOf(s1, s2)
 }
 
-// CrossCXFloat64 calls CrossCX on a float64 slice.
+// CrossCXFloat64 calls CrossCXOf on a float64 slice.
 func CrossCXFloat64(s1 []float64, s2 []float64) {
-	CrossCX(Float64Slice(s1), Float64Slice(s2))
+	CrossCXOf(s1, s2)
 }
 
-// CrossCXString calls CrossCX on a string slice.
+// CrossCXString calls CrossCXOf on a string slice.
 func CrossCXString(s1 []string, s2 []string) {
-	CrossCX(StringSlice(s1), StringSlice(s2))
+	CrossCXOf(s1, s2)
 }
 
-// CrossERX (Edge Recombination Crossover).
-func CrossERX(p1, p2 Slice) {
+// CrossERXOf (Edge Recombination Crossover).
+func CrossERXOf[T comparable](p1, p2 []T) {
 	var (
-		n            = p1.Len()
-		o1           = p1.Copy()
-		o2           = p2.Copy()
-		parents      = []Slice{p1, p2}
-		offsprings   = []Slice{o1, o2}
+		n            = len(p1)
+		o1           = make([]T, n)
+		o2           = make([]T, n)
+		parents      = [][]T{p1, p2}
+		offsprings   = [][]T{o1, o2}
 		p1Neighbours = getNeighbours(p1)
 		p2Neighbours = getNeighbours(p2)
-		pNeighbours  = make(map[interface{}]set)
+		pNeighbours  = make(map[T]map[T]bool)
 	)
 	// Merge the neighbours of each parent whilst ignoring duplicates
 	for i := range p1Neighbours {
 		pNeighbours[i] = union(p1Neighbours[i], p2Neighbours[i])
 	}
 	// Hold two copies of the parent neighbours (one for each offspring)
-	var neighbours = []map[interface{}]set{pNeighbours, nil}
-	neighbours[1] = make(map[interface{}]set)
+	var neighbours = []map[T]map[T]bool{pNeighbours, nil}
+	neighbours[1] = make(map[T]map[T]bool)
 	for k, v := range pNeighbours {
 		neighbours[1][k] = v
 	}
 	// Set the first element of each offspring to be the one of the
 	// corresponding parent
-	o1.Set(0, p1.At(0))
-	o2.Set(0, p2.At(0))
+	o1[0] = p1[0]
+	o2[0] = p2[0]
 	// Delete the neighbour from the adjacency set
 	for i := range neighbours {
-		delete(neighbours[i], parents[i].At(0))
+		delete(neighbours[i], parents[i][0])
 		for j := range neighbours[i] {
-			if neighbours[i][j][parents[i].At(0)] {
-				delete(neighbours[i][j], parents[i].At(0))
+			if neighbours[i][j][parents[i][0]] {
+				delete(neighbours[i][j], parents[i][0])
 			}
 		}
 	}
@@ -292,7 +524,7 @@ func CrossERX(p1, p2 Slice) {
 		for i := 1; i < n; i++ {
 			// Find the gene with the least neighbours
 			var (
-				j   interface{}
+				j   T
 				min = 5 // There can't be more than 5 neighbours between 2 parents
 			)
 			for k, v := range neighbours[o] {
@@ -301,7 +533,7 @@ func CrossERX(p1, p2 Slice) {
 					min = len(v)
 				}
 			}
-			offsprings[o].Set(i, j)
+			offsprings[o][i] = j
 			delete(neighbours[o], j)
 			for k := range neighbours[o] {
 				if neighbours[o][k][j] {
@@ -310,21 +542,425 @@ func CrossERX(p1, p2 Slice) {
 			}
 		}
 	}
-	p1.Replace(o1)
-	p2.Replace(o2)
+	copy(p1, o1)
+	copy(p2, o2)
 }
 
-// CrossERXInt calls CrossERX on an int slice.
+// CrossERX calls CrossERXOf on two Slices.
+func CrossERX(p1, p2 Slice) {
+	crossBoxed(p1, p2, func(s1, s2 []interface{}) { CrossERXOf(s1, s2) })
+}
+
+// CrossERXInt calls CrossERXOf on an int slice.
 func CrossERXInt(s1 []int, s2 []int) {
-	CrossERX(IntSlice(s1), IntSlice(s2))
+	CrossERXOf(s1, s2)
 }
 
-// CrossERXFloat64 callsCrossERX on a float64 slice.
+// CrossERXFloat64 calls CrossERXOf on a float64 slice.
 func CrossERXFloat64(s1 []float64, s2 []float64) {
-	CrossERX(Float64Slice(s1), Float64Slice(s2))
+	CrossERXOf(s1, s2)
 }
 
-// CrossERXString calls CrossERX on a string slice.
+// CrossERXString calls CrossERXOf on a string slice.
 func CrossERXString(s1 []string, s2 []string) {
-	CrossERX(StringSlice(s1), StringSlice(s2))
+	CrossERXOf(s1, s2)
+}
+
+// randomPositions returns the indexes of a slice of length n that are chosen
+// independently with probability 0.5.
+func randomPositions(n int, rng *rand.Rand) []int {
+	var positions []int
+	for i := 0; i < n; i++ {
+		if rng.Float64() < 0.5 {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// Contains the deterministic part of the PBX method for testing purposes.
+func pbx[T comparable](p1, p2 []T, positions []int) {
+	var (
+		n       = len(p1)
+		o1      = append([]T(nil), p1...)
+		o2      = append([]T(nil), p2...)
+		fixed   = make([]bool, n)
+		parents = [][]T{p1, p2}
+	)
+	for _, i := range positions {
+		fixed[i] = true
+	}
+	for k, o := range [][]T{o1, o2} {
+		var (
+			keep  = parents[k]
+			other = parents[1-k]
+			kept  = make(map[T]bool, len(positions))
+		)
+		for _, i := range positions {
+			kept[keep[i]] = true
+		}
+		// Fill the free positions with the genes of the other parent in the
+		// order in which they appear
+		var j int
+		for i := 0; i < n; i++ {
+			if fixed[i] {
+				continue
+			}
+			for kept[other[j]] {
+				j++
+			}
+			o[i] = other[j]
+			j++
+		}
+	}
+	copy(p1, o1)
+	copy(p2, o2)
+}
+
+// CrossPBXOf (Position Based Crossover). Random positions are chosen, each
+// offspring keeps the genes its parent has at those positions and the
+// remaining genes are placed in the order in which they appear in the other
+// parent. The parents have to be permutations of each other.
+func CrossPBXOf[T comparable](p1 []T, p2 []T, rng *rand.Rand) {
+	pbx(p1, p2, randomPositions(len(p1), rng))
+}
+
+// CrossPBX calls CrossPBXOf on two Slices.
+func CrossPBX(p1 Slice, p2 Slice, rng *rand.Rand) {
+	crossBoxed(p1, p2, func(s1, s2 []interface{}) { CrossPBXOf(s1, s2, rng) })
+}
+
+// CrossPBXInt calls CrossPBXOf on two int slices.
+func CrossPBXInt(s1 []int, s2 []int, rng *rand.Rand) {
+	CrossPBXOf(s1, s2, rng)
+}
+
+// CrossPBXFloat64 calls CrossPBXOf on two float64 slices.
+func CrossPBXFloat64(s1 []float64, s2 []float64, rng *rand.Rand) {
+	CrossPBXOf(s1, s2, rng)
+}
+
+// CrossPBXString calls CrossPBXOf on two string slices.
+func CrossPBXString(s1 []string, s2 []string, rng *rand.Rand) {
+	CrossPBXOf(s1, s2, rng)
+}
+
+// Contains the deterministic part of the OX2 method for testing purposes.
+func ox2[T comparable](p1, p2 []T, positions []int) {
+	var (
+		o1      = append([]T(nil), p1...)
+		o2      = append([]T(nil), p2...)
+		parents = [][]T{p1, p2}
+	)
+	for k, o := range [][]T{o1, o2} {
+		var (
+			base   = parents[k]
+			other  = parents[1-k]
+			lookup = newIndexLookup(base)
+			slots  = make([]int, len(positions))
+		)
+		// Find where the selected genes of the other parent are located in
+		// the base parent
+		for i, p := range positions {
+			slots[i] = lookup[other[p]]
+		}
+		sort.Ints(slots)
+		// Place them in the order in which they appear in the other parent
+		for i, p := range positions {
+			o[slots[i]] = other[p]
+		}
+	}
+	copy(p1, o1)
+	copy(p2, o2)
+}
+
+// CrossOX2Of (Order Based Crossover). Random positions are chosen on the
+// second parent, the genes at those positions are located in the first parent
+// and reordered so that they appear in the same order as in the second parent.
+// The second offspring is obtained by swapping the roles of the parents. The
+// parents have to be permutations of each other.
+func CrossOX2Of[T comparable](p1 []T, p2 []T, rng *rand.Rand) {
+	ox2(p1, p2, randomPositions(len(p1), rng))
+}
+
+// CrossOX2 calls CrossOX2Of on two Slices.
+func CrossOX2(p1 Slice, p2 Slice, rng *rand.Rand) {
+	crossBoxed(p1, p2, func(s1, s2 []interface{}) { CrossOX2Of(s1, s2, rng) })
+}
+
+// CrossOX2Int calls CrossOX2Of on two int slices.
+func CrossOX2Int(s1 []int, s2 []int, rng *rand.Rand) {
+	CrossOX2Of(s1, s2, rng)
+}
+
+// CrossOX2Float64 calls CrossOX2Of on two float64 slices.
+func CrossOX2Float64(s1 []float64, s2 []float64, rng *rand.Rand) {
+	CrossOX2Of(s1, s2, rng)
+}
+
+// CrossOX2String calls CrossOX2Of on two string slices.
+func CrossOX2String(s1 []string, s2 []string, rng *rand.Rand) {
+	CrossOX2Of(s1, s2, rng)
+}
+
+// A tourGraph stores the two neighbours of each city of a tour, cities being
+// numbered from 0 to n-1.
+type tourGraph [][2]int
+
+func newTourGraph(tour []int) tourGraph {
+	var (
+		n = len(tour)
+		g = make(tourGraph, n)
+	)
+	for i, c := range tour {
+		g[c] = [2]int{tour[(i+n-1)%n], tour[(i+1)%n]}
+	}
+	return g
+}
+
+func (g tourGraph) has(u, v int) bool {
+	return g[u][0] == v || g[u][1] == v
+}
+
+// replace the neighbour old of u with new.
+func (g tourGraph) replace(u, old, new int) {
+	if g[u][0] == old {
+		g[u][0] = new
+	} else {
+		g[u][1] = new
+	}
+}
+
+// tours returns the subtours contained in a tourGraph.
+func (g tourGraph) tours() [][]int {
+	var (
+		tours   [][]int
+		visited = make([]bool, len(g))
+	)
+	for s := range g {
+		if visited[s] {
+			continue
+		}
+		var (
+			tour      = []int{s}
+			prev, cur = s, g[s][0]
+		)
+		visited[s] = true
+		for cur != s {
+			tour = append(tour, cur)
+			visited[cur] = true
+			var next = g[cur][0]
+			if next == prev {
+				next = g[cur][1]
+			}
+			prev, cur = cur, next
+		}
+		tours = append(tours, tour)
+	}
+	return tours
+}
+
+// abCycles decomposes the edges that belong to only one of two tours into
+// cycles that alternate between edges of a and edges of b. Each cycle is
+// returned as a list of cities c where the edge between c[i] and c[i+1]
+// belongs to a if i is even and to b otherwise, the last city being connected
+// to the first one.
+func abCycles(a, b tourGraph, rng *rand.Rand) [][]int {
+	var (
+		n          = len(a)
+		remA, remB = make([][]int, n), make([][]int, n)
+		cycles     [][]int
+	)
+	for u := 0; u < n; u++ {
+		for _, v := range a[u] {
+			if !b.has(u, v) {
+				remA[u] = append(remA[u], v)
+			}
+		}
+		for _, v := range b[u] {
+			if !a.has(u, v) {
+				remB[u] = append(remB[u], v)
+			}
+		}
+	}
+	var removeEdge = func(rem [][]int, u, v int) {
+		for _, e := range [][2]int{{u, v}, {v, u}} {
+			for i, w := range rem[e[0]] {
+				if w == e[1] {
+					rem[e[0]] = append(rem[e[0]][:i], rem[e[0]][i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	for _, start := range rng.Perm(n) {
+		var (
+			path = []int{start}
+			// Indexes at which each city was reached with a b edge
+			evenIdx = map[int]int{start: 0}
+		)
+		for {
+			var (
+				k   = len(path) - 1
+				cur = path[k]
+				rem = remA
+			)
+			if k%2 == 1 {
+				rem = remB
+			}
+			if len(rem[cur]) == 0 {
+				break
+			}
+			var next = rem[cur][rng.Intn(len(rem[cur]))]
+			removeEdge(rem, cur, next)
+			path = append(path, next)
+			if (k+1)%2 == 1 {
+				continue
+			}
+			// Close a cycle if the walk goes back to a city from which an a
+			// edge was taken
+			if i, ok := evenIdx[next]; ok {
+				cycles = append(cycles, append([]int(nil), path[i:k+1]...))
+				path = path[:i+1]
+				evenIdx = make(map[int]int)
+				for j := 0; j < len(path); j += 2 {
+					evenIdx[path[j]] = j
+				}
+				continue
+			}
+			evenIdx[next] = k + 1
+		}
+	}
+	return cycles
+}
+
+// eax applies an AB-cycle chosen at random to the base tour and merges the
+// resulting subtours.
+func eax(base, other []int, dist func(i, j int) float64, rng *rand.Rand) []int {
+	var (
+		a      = newTourGraph(base)
+		cycles = abCycles(a, newTourGraph(other), rng)
+	)
+	if len(cycles) == 0 {
+		return base
+	}
+	// Replace the a edges of the cycle with its b edges
+	var cycle = cycles[rng.Intn(len(cycles))]
+	for i := 0; i < len(cycle); i += 2 {
+		var u, v = cycle[i], cycle[i+1]
+		a.replace(u, v, -1)
+		a.replace(v, u, -1)
+	}
+	for i := 1; i < len(cycle); i += 2 {
+		var u, v = cycle[i], cycle[(i+1)%len(cycle)]
+		a.replace(u, -1, v)
+		a.replace(v, -1, u)
+	}
+	// Merge the smallest subtour with another one until there is only one
+	// tour left by using the cheapest 2-opt style exchange of edges
+	for {
+		var tours = a.tours()
+		if len(tours) == 1 {
+			break
+		}
+		var smallest = tours[0]
+		for _, t := range tours[1:] {
+			if len(t) < len(smallest) {
+				smallest = t
+			}
+		}
+		var inSmallest = make([]bool, len(a))
+		for _, u := range smallest {
+			inSmallest[u] = true
+		}
+		var (
+			best               = math.Inf(1)
+			bu1, bu2, bv1, bv2 int
+		)
+		for i, u1 := range smallest {
+			var u2 = smallest[(i+1)%len(smallest)]
+			for v1 := range a {
+				if inSmallest[v1] {
+					continue
+				}
+				for _, v2 := range a[v1] {
+					if v2 < v1 {
+						continue
+					}
+					var cost = dist(u1, u2) + dist(v1, v2)
+					if gain := dist(u1, v1) + dist(u2, v2) - cost; gain < best {
+						best, bu1, bu2, bv1, bv2 = gain, u1, u2, v1, v2
+					}
+					if gain := dist(u1, v2) + dist(u2, v1) - cost; gain < best {
+						best, bu1, bu2, bv1, bv2 = gain, u1, u2, v2, v1
+					}
+				}
+			}
+		}
+		a.replace(bu1, bu2, bv1)
+		a.replace(bu2, bu1, bv2)
+		a.replace(bv1, bv2, bu1)
+		a.replace(bv2, bv1, bu2)
+	}
+	// Read the tour starting from the first city of the base tour
+	var tour = a.tours()[0]
+	for i, c := range tour {
+		if c == base[0] {
+			return append(tour[i:], tour[:i]...)
+		}
+	}
+	return tour
+}
+
+// CrossEAXOf (Edge Assembly Crossover). The edges that belong to only one of
+// the parents are decomposed into cycles that alternate between edges of each
+// parent. An offspring is obtained by replacing the edges of the first parent
+// that belong to one of those cycles, chosen at random, with the edges of the
+// second parent. The subtours this creates are then merged by exchanging
+// edges greedily according to dist, which returns the distance between two
+// genes. The second offspring is obtained by swapping the roles of the
+// parents. The genomes are considered to be tours, meaning that the last gene
+// is connected to the first one, and have to be permutations of each other.
+// Reference: https://doi.org/10.1287/ijoc.1120.0506
+func CrossEAXOf[T comparable](p1 []T, p2 []T, dist func(a, b T) float64, rng *rand.Rand) {
+	var n = len(p1)
+	if n <= 3 {
+		return
+	}
+	var (
+		values = append([]T(nil), p1...)
+		lookup = newIndexLookup(p1)
+		t1     = make([]int, n)
+		t2     = make([]int, n)
+		d      = func(i, j int) float64 { return dist(values[i], values[j]) }
+	)
+	for i := range t1 {
+		t1[i] = i
+		t2[i] = lookup[p2[i]]
+	}
+	var o1, o2 = eax(t1, t2, d, rng), eax(t2, t1, d, rng)
+	for i := 0; i < n; i++ {
+		p1[i] = values[o1[i]]
+		p2[i] = values[o2[i]]
+	}
+}
+
+// CrossEAX calls CrossEAXOf on two Slices.
+func CrossEAX(p1 Slice, p2 Slice, dist func(a, b interface{}) float64, rng *rand.Rand) {
+	crossBoxed(p1, p2, func(s1, s2 []interface{}) { CrossEAXOf(s1, s2, dist, rng) })
+}
+
+// CrossEAXInt calls CrossEAXOf on two int slices.
+func CrossEAXInt(s1 []int, s2 []int, dist func(a, b int) float64, rng *rand.Rand) {
+	CrossEAXOf(s1, s2, dist, rng)
+}
+
+// CrossEAXFloat64 calls CrossEAXOf on two float64 slices.
+func CrossEAXFloat64(s1 []float64, s2 []float64, dist func(a, b float64) float64, rng *rand.Rand) {
+	CrossEAXOf(s1, s2, dist, rng)
+}
+
+// CrossEAXString calls CrossEAXOf on two string slices.
+func CrossEAXString(s1 []string, s2 []string, dist func(a, b string) float64, rng *rand.Rand) {
+	CrossEAXOf(s1, s2, dist, rng)
 }
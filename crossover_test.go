@@ -47,6 +47,149 @@ func TestCrossUniformFloat64(t *testing.T) {
 	}
 }
 
+func checkBoundsFloat64(t *testing.T, x, lower, upper []float64) {
+	for i := range x {
+		if x[i] < lower[i] || x[i] > upper[i] {
+			t.Errorf("Gene %d = %f is not in [%f, %f]", i, x[i], lower[i], upper[i])
+		}
+	}
+}
+
+func TestRealCodedCrossovers(t *testing.T) {
+	var (
+		rng   = newRand()
+		lower = []float64{-1, 0, -5, 2}
+		upper = []float64{1, 1, 5, 2}
+	)
+	var testCases = []func(p1, p2 []float64){
+		func(p1, p2 []float64) { CrossSBXFloat64(p1, p2, 2, lower, upper, rng) },
+		func(p1, p2 []float64) { CrossBLXFloat64(p1, p2, 0.5, lower, upper, rng) },
+		func(p1, p2 []float64) { CrossArithmeticFloat64(p1, p2, rng) },
+		func(p1, p2 []float64) { CrossIntermediateFloat64(p1, p2, 0.25, lower, upper, rng) },
+	}
+	for i, cross := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var changed bool
+			for j := 0; j < 100; j++ {
+				var (
+					p1 = []float64{-0.99, 0.9, 4.9, 2}
+					p2 = []float64{0.99, 0.1, -4.9, 2}
+				)
+				cross(p1, p2)
+				checkBoundsFloat64(t, p1, lower, upper)
+				checkBoundsFloat64(t, p2, lower, upper)
+				changed = changed || p1[0] != -0.99
+			}
+			if !changed {
+				t.Error("Crossover never modified the parents")
+			}
+		})
+	}
+}
+
+func TestCrossSBXFloat64Spread(t *testing.T) {
+	// A large distribution index produces offsprings close to the parents
+	var (
+		rng          = newRand()
+		lower, upper = []float64{-100}, []float64{100}
+		spread       = func(eta float64) (s float64) {
+			for i := 0; i < 1000; i++ {
+				var p1, p2 = []float64{-1}, []float64{1}
+				CrossSBXFloat64(p1, p2, eta, lower, upper, rng)
+				s += math.Abs(p1[0]-p2[0]) / 1000
+			}
+			return
+		}
+	)
+	if spread(1) <= spread(20) {
+		t.Error("Expected a smaller spread with a larger distribution index")
+	}
+}
+
+func TestCrossArithmeticFloat64(t *testing.T) {
+	var (
+		rng    = newRand()
+		p1, p2 = []float64{0, 2}, []float64{4, 6}
+	)
+	CrossArithmeticFloat64(p1, p2, rng)
+	// The same weight is used for every gene
+	if math.Abs((p1[0]-0)-(p1[1]-2)) > 1e-12 || math.Abs(p1[0]+p2[0]-4) > 1e-12 {
+		t.Errorf("Unexpected offsprings %v and %v", p1, p2)
+	}
+}
+
+func TestMultiParentCrossovers(t *testing.T) {
+	var (
+		rng     = newRand()
+		lower   = []float64{-10, -10, -10}
+		upper   = []float64{10, 10, 10}
+		parents = [][]float64{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}}
+	)
+	// UNDX offsprings are centered on the midpoint of the first two parents
+	var mean = make([]float64, 3)
+	for i := 0; i < 2000; i++ {
+		var o = CrossUNDXFloat64(parents, 0.5, 0.35/math.Sqrt(3), lower, upper, rng)
+		checkBoundsFloat64(t, o, lower, upper)
+		for j := range mean {
+			mean[j] += o[j] / 2000
+		}
+	}
+	for j := range mean {
+		if math.Abs(mean[j]) > 0.1 {
+			t.Errorf("Expected UNDX offsprings to be centered on 0, got %v", mean)
+		}
+	}
+	// PCX offsprings are centered on the first parent
+	mean = make([]float64, 3)
+	for i := 0; i < 2000; i++ {
+		var o = CrossPCXFloat64(parents, 0.1, 0.1, lower, upper, rng)
+		checkBoundsFloat64(t, o, lower, upper)
+		for j := range mean {
+			mean[j] += o[j] / 2000
+		}
+	}
+	if math.Abs(mean[0]-1) > 0.05 || math.Abs(mean[1]) > 0.05 || math.Abs(mean[2]) > 0.05 {
+		t.Errorf("Expected PCX offsprings to be centered on the first parent, got %v", mean)
+	}
+	// Offsprings are clipped
+	var o = CrossUNDXFloat64([][]float64{{0}, {100}, {0}}, 0.5, 0.5, []float64{0}, []float64{1}, rng)
+	checkBoundsFloat64(t, o, []float64{0}, []float64{1})
+}
+
+func TestCrossUniformInt(t *testing.T) {
+	var (
+		rng    = newRand()
+		p1, p2 = []int{1, 2, 3, 4, 5, 6}, []int{7, 8, 9, 10, 11, 12}
+	)
+	CrossUniformInt(p1, p2, rng)
+	for i := range p1 {
+		if p1[i]+p2[i] != 2*i+8 || (p1[i] != i+1 && p1[i] != i+7) {
+			t.Errorf("Gene %d was not exchanged correctly: %d and %d", i, p1[i], p2[i])
+		}
+	}
+}
+
+func TestCrossSBXInt(t *testing.T) {
+	var (
+		rng          = newRand()
+		lower, upper = []int{0, -10}, []int{10, 10}
+		changed      bool
+	)
+	for i := 0; i < 100; i++ {
+		var p1, p2 = []int{1, -9}, []int{9, 9}
+		CrossSBXInt(p1, p2, 2, lower, upper, rng)
+		for j := range p1 {
+			if p1[j] < lower[j] || p1[j] > upper[j] || p2[j] < lower[j] || p2[j] > upper[j] {
+				t.Errorf("Offsprings %v and %v are out of bounds", p1, p2)
+			}
+		}
+		changed = changed || (p1[0] != 1 && p1[0] != 9)
+	}
+	if !changed {
+		t.Error("CrossSBXInt never produced new values")
+	}
+}
+
 func TestGNX(t *testing.T) {
 	var testCases = []struct {
 		p1      []int
@@ -543,3 +686,209 @@ func TestCrossERXString(t *testing.T) {
 		t.Errorf("Expected %v, got %v", o2, p2)
 	}
 }
+
+func isPermutation(s, of []int) bool {
+	var counts = make(map[int]int)
+	for _, v := range of {
+		counts[v]++
+	}
+	for _, v := range s {
+		counts[v]--
+	}
+	for _, c := range counts {
+		if c != 0 {
+			return false
+		}
+	}
+	return len(s) == len(of)
+}
+
+func TestPBX(t *testing.T) {
+	var (
+		p1 = []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+		p2 = []int{9, 3, 7, 8, 2, 6, 5, 1, 4}
+	)
+	pbx(IntSlice(p1), IntSlice(p2), []int{1, 2, 5})
+	if fmt.Sprint(p1) != "[9 2 3 7 8 6 5 1 4]" || fmt.Sprint(p2) != "[1 3 7 2 4 6 5 8 9]" {
+		t.Errorf("Unexpected offsprings %v and %v", p1, p2)
+	}
+}
+
+func TestOX2(t *testing.T) {
+	var (
+		p1 = []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+		p2 = []int{9, 3, 7, 8, 2, 6, 5, 1, 4}
+	)
+	ox2(IntSlice(p1), IntSlice(p2), []int{1, 2, 5})
+	if fmt.Sprint(p1) != "[1 2 3 4 5 7 6 8 9]" || fmt.Sprint(p2) != "[9 2 7 8 3 6 5 1 4]" {
+		t.Errorf("Unexpected offsprings %v and %v", p1, p2)
+	}
+}
+
+func TestPermutationCrossovers(t *testing.T) {
+	var (
+		rng  = newRand()
+		dist = func(a, b int) float64 { return math.Abs(float64(a - b)) }
+	)
+	var testCases = []func(s1, s2 []int){
+		func(s1, s2 []int) { CrossPBXInt(s1, s2, rng) },
+		func(s1, s2 []int) { CrossOX2Int(s1, s2, rng) },
+		func(s1, s2 []int) { CrossEAXInt(s1, s2, dist, rng) },
+	}
+	for i, cross := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			for j := 0; j < 20; j++ {
+				var (
+					p1 = rng.Perm(30)
+					p2 = rng.Perm(30)
+				)
+				cross(p1, p2)
+				if !isPermutation(p1, p2) || !isPermutation(p1, rng.Perm(30)) {
+					t.Fatalf("Offsprings %v and %v are not permutations", p1, p2)
+				}
+			}
+		})
+	}
+	// Check the typed wrappers
+	var (
+		f1, f2 = []float64{1, 2, 3, 4, 5}, []float64{5, 3, 1, 4, 2}
+		s1, s2 = []string{"a", "b", "c", "d", "e"}, []string{"e", "c", "a", "d", "b"}
+	)
+	CrossPBXFloat64(f1, f2, rng)
+	CrossOX2Float64(f1, f2, rng)
+	CrossEAXFloat64(f1, f2, func(a, b float64) float64 { return math.Abs(a - b) }, rng)
+	CrossPBXString(s1, s2, rng)
+	CrossOX2String(s1, s2, rng)
+	CrossEAXString(s1, s2, func(a, b string) float64 { return math.Abs(float64(a[0]) - float64(b[0])) }, rng)
+}
+
+func TestABCycles(t *testing.T) {
+	var (
+		rng    = newRand()
+		t1, t2 = rng.Perm(20), rng.Perm(20)
+		a, b   = newTourGraph(t1), newTourGraph(t2)
+		counts = make(map[[2]int]int)
+		key    = func(u, v int) [2]int {
+			if u > v {
+				u, v = v, u
+			}
+			return [2]int{u, v}
+		}
+	)
+	for _, cycle := range abCycles(a, b, rng) {
+		if len(cycle)%2 != 0 {
+			t.Fatalf("Cycle %v has an odd number of edges", cycle)
+		}
+		for i, u := range cycle {
+			var v = cycle[(i+1)%len(cycle)]
+			if (i%2 == 0 && !a.has(u, v)) || (i%2 == 1 && !b.has(u, v)) {
+				t.Fatalf("Cycle %v does not alternate between the parents", cycle)
+			}
+			counts[key(u, v)]++
+		}
+	}
+	// Each edge that belongs to only one parent is used exactly once
+	for u := 0; u < 20; u++ {
+		for _, g := range []tourGraph{a, b} {
+			for _, v := range g[u] {
+				var shared = a.has(u, v) && b.has(u, v)
+				if c := counts[key(u, v)]; (shared && c != 0) || (!shared && c != 1) {
+					t.Errorf("Edge (%d, %d) is used %d times", u, v, c)
+				}
+			}
+		}
+	}
+}
+
+func TestCrossEAXIdenticalParents(t *testing.T) {
+	var (
+		rng    = newRand()
+		p1, p2 = []int{3, 1, 4, 0, 2}, []int{3, 1, 4, 0, 2}
+	)
+	CrossEAXInt(p1, p2, func(a, b int) float64 { return 1 }, rng)
+	if fmt.Sprint(p1) != "[3 1 4 0 2]" || fmt.Sprint(p2) != "[3 1 4 0 2]" {
+		t.Errorf("Identical parents should produce identical offsprings, got %v and %v", p1, p2)
+	}
+}
+
+type city struct{ X, Y int }
+
+func TestGenericPermutationOperators(t *testing.T) {
+	var (
+		rng    = newRand()
+		cities = []city{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {1, 1}, {0, 1}}
+		dist   = func(a, b city) float64 { return math.Hypot(float64(a.X-b.X), float64(a.Y-b.Y)) }
+		perm   = func() []city {
+			var p = make([]city, len(cities))
+			for i, j := range rng.Perm(len(cities)) {
+				p[i] = cities[j]
+			}
+			return p
+		}
+	)
+	var testCases = []func(p1, p2 []city){
+		func(p1, p2 []city) { CrossPMXOf(p1, p2, rng) },
+		func(p1, p2 []city) { CrossOXOf(p1, p2, rng) },
+		func(p1, p2 []city) { CrossCXOf(p1, p2) },
+		func(p1, p2 []city) { CrossERXOf(p1, p2) },
+		func(p1, p2 []city) { CrossPBXOf(p1, p2, rng) },
+		func(p1, p2 []city) { CrossOX2Of(p1, p2, rng) },
+		func(p1, p2 []city) { CrossEAXOf(p1, p2, dist, rng) },
+		func(p1, p2 []city) { MutPermuteOf(p1, 3, rng) },
+		func(p1, p2 []city) { MutSpliceOf(p1, rng) },
+		func(p1, p2 []city) { MutInversionOf(p1, rng) },
+		func(p1, p2 []city) { MutScrambleOf(p1, rng) },
+		func(p1, p2 []city) { MutInsertionOf(p1, rng) },
+		func(p1, p2 []city) { MutDisplacementOf(p1, rng) },
+		func(p1, p2 []city) { MutTwoOptOf(p1, rng) },
+	}
+	for i, op := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var p1, p2 = perm(), perm()
+			op(p1, p2)
+			for _, p := range [][]city{p1, p2} {
+				var seen = make(map[city]bool)
+				for _, c := range p {
+					seen[c] = true
+				}
+				if len(seen) != len(cities) {
+					t.Errorf("%v is not a permutation", p)
+				}
+			}
+		})
+	}
+}
+
+// citySlice is a custom Slice implementation that does not rely on SliceOf.
+type citySlice []city
+
+func (s citySlice) At(i int) interface{}     { return s[i] }
+func (s citySlice) Set(i int, v interface{}) { s[i] = v.(city) }
+func (s citySlice) Len() int                 { return len(s) }
+func (s citySlice) Swap(i, j int)            { s[i], s[j] = s[j], s[i] }
+func (s citySlice) Slice(a, b int) Slice     { return s[a:b] }
+func (s citySlice) Split(k int) (Slice, Slice) {
+	return s[:k], s[k:]
+}
+func (s citySlice) Append(t Slice) Slice { return append(s, t.(citySlice)...) }
+func (s citySlice) Replace(t Slice)      { copy(s, t.(citySlice)) }
+func (s citySlice) Copy() Slice          { return append(citySlice(nil), s...) }
+
+func TestCustomSlice(t *testing.T) {
+	var (
+		p1 = citySlice{{0, 0}, {1, 0}, {2, 0}, {3, 0}}
+		p2 = citySlice{{3, 0}, {2, 0}, {1, 0}, {0, 0}}
+	)
+	CrossCX(p1, p2)
+	CrossPMX(p1, p2, newRand())
+	MutPermute(p1, 2, newRand())
+	for _, p := range []citySlice{p1, p2} {
+		var sum int
+		for _, c := range p {
+			sum += c.X
+		}
+		if sum != 6 {
+			t.Errorf("%v is not a permutation", p)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package eaopt
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// reproduce generates two offsprings from two parents by cloning them,
+// applying crossover with probability crossRate and then mutating each
+// offspring with probability mutRate.
+func reproduce(a, b Individual, crossRate, mutRate float64, rng *rand.Rand) (Individual, Individual) {
+	var c1, c2 = a.Clone(rng), b.Clone(rng)
+	if rng.Float64() < crossRate {
+		c1.Crossover(c2, rng)
+		// Crossover receives the mate by value and can't reset its flag
+		c2.Evaluated = false
+	}
+	if rng.Float64() < mutRate {
+		c1.Mutate(rng)
+	}
+	if rng.Float64() < mutRate {
+		c2.Mutate(rng)
+	}
+	return c1, c2
+}
+
+// validateCrowding checks the fields shared by the crowding models.
+func validateCrowding(crossRate, mutRate float64, metric Metric) error {
+	if mutRate < 0 || mutRate > 1 {
+		return errInvalidMutRate
+	}
+	if crossRate < 0 || crossRate > 1 {
+		return errInvalidCrossRate
+	}
+	if metric == nil {
+		return errNilMetric
+	}
+	return nil
+}
+
+// crowd pairs the individuals of a population at random and produces two
+// offsprings from each pair. Each offspring competes against the parent it is
+// the most similar to, the pairing being the one that minimizes the sum of the
+// distances between the parents and the offsprings. The replace function
+// decides if an offspring replaces its parent given their fitnesses.
+func crowd(pop *Population, crossRate, mutRate float64, metric Metric,
+	replace func(parent, offspring float64, rng *rand.Rand) bool) error {
+	var (
+		dm   = newDistanceMemoizer(metric)
+		perm = pop.RNG.Perm(len(pop.Individuals))
+	)
+	for k := 0; k+1 < len(perm); k += 2 {
+		var (
+			i, j   = perm[k], perm[k+1]
+			p1, p2 = &pop.Individuals[i], &pop.Individuals[j]
+		)
+		var c1, c2 = reproduce(*p1, *p2, crossRate, mutRate, pop.RNG)
+		for _, indi := range []*Individual{p1, p2, &c1, &c2} {
+			if err := pop.evaluate(indi); err != nil {
+				return err
+			}
+		}
+		if dm.GetDistance(*p1, c1)+dm.GetDistance(*p2, c2) > dm.GetDistance(*p1, c2)+dm.GetDistance(*p2, c1) {
+			c1, c2 = c2, c1
+		}
+		if replace(p1.Fitness, c1.Fitness, pop.RNG) {
+			pop.Individuals[i] = c1
+		}
+		if replace(p2.Fitness, c2.Fitness, pop.RNG) {
+			pop.Individuals[j] = c2
+		}
+	}
+	return nil
+}
+
+// ModDeterministicCrowding implements deterministic crowding. The individuals
+// are paired at random and each pair produces two offsprings, each of which
+// competes against the parent it is the most similar to according to Metric.
+// An offspring replaces its parent if it has a strictly lower fitness. Because
+// offsprings only replace similar individuals, distinct niches are preserved.
+// Reference: Mahfoud, S. W. (1995), Niching methods for genetic algorithms
+type ModDeterministicCrowding struct {
+	CrossRate float64
+	MutRate   float64
+	Metric    Metric
+}
+
+// Apply ModDeterministicCrowding.
+func (mod ModDeterministicCrowding) Apply(pop *Population) error {
+	return crowd(pop, mod.CrossRate, mod.MutRate, mod.Metric, func(parent, offspring float64, rng *rand.Rand) bool {
+		return offspring < parent
+	})
+}
+
+// Validate ModDeterministicCrowding fields.
+func (mod ModDeterministicCrowding) Validate() error {
+	return validateCrowding(mod.CrossRate, mod.MutRate, mod.Metric)
+}
+
+// ModProbabilisticCrowding implements probabilistic crowding. It works like
+// ModDeterministicCrowding except that an offspring replaces its parent with
+// probability 1 / (1 + exp((fo - fp) / Temperature)), fo and fp being the
+// fitnesses of the offspring and the parent. Worse offsprings thus have a
+// chance of winning, which tends to produce niches whose sizes are
+// proportional to the quality of their optima. A low Temperature makes the
+// model behave like ModDeterministicCrowding.
+// Reference: Mengshoel, O. J., & Goldberg, D. E. (1999), Probabilistic
+// crowding: Deterministic crowding with probabilistic replacement
+type ModProbabilisticCrowding struct {
+	CrossRate   float64
+	MutRate     float64
+	Metric      Metric
+	Temperature float64
+}
+
+// Apply ModProbabilisticCrowding.
+func (mod ModProbabilisticCrowding) Apply(pop *Population) error {
+	return crowd(pop, mod.CrossRate, mod.MutRate, mod.Metric, func(parent, offspring float64, rng *rand.Rand) bool {
+		var p = 1 / (1 + math.Exp((offspring-parent)/mod.Temperature))
+		if math.IsNaN(p) {
+			p = 0.5
+		}
+		return rng.Float64() < p
+	})
+}
+
+// Validate ModProbabilisticCrowding fields.
+func (mod ModProbabilisticCrowding) Validate() error {
+	if err := validateCrowding(mod.CrossRate, mod.MutRate, mod.Metric); err != nil {
+		return err
+	}
+	if mod.Temperature <= 0 {
+		return errors.New("Temperature should be higher than 0")
+	}
+	return nil
+}
+
+// ModRestrictedTournament implements restricted tournament selection. Two
+// parents are picked at random to produce two offsprings. For each offspring,
+// WindowSize individuals are sampled from the population and the offspring
+// replaces the one it is the most similar to according to Metric if it has a
+// strictly lower fitness. This is repeated until as many offsprings as there
+// are individuals in the population have been produced.
+// Reference: Harik, G. R. (1995), Finding multimodal solutions using
+// restricted tournament selection
+type ModRestrictedTournament struct {
+	CrossRate  float64
+	MutRate    float64
+	Metric     Metric
+	WindowSize uint
+}
+
+// Apply ModRestrictedTournament.
+func (mod ModRestrictedTournament) Apply(pop *Population) error {
+	var (
+		n      = len(pop.Individuals)
+		dm     = newDistanceMemoizer(mod.Metric)
+		window = minUint(mod.WindowSize, uint(n))
+	)
+	for k := 0; k+1 < n; k += 2 {
+		var (
+			parents = randomInts(2, 0, n, pop.RNG)
+			c1, c2  = reproduce(pop.Individuals[parents[0]], pop.Individuals[parents[1]], mod.CrossRate, mod.MutRate, pop.RNG)
+		)
+		for _, offspring := range []Individual{c1, c2} {
+			if err := pop.evaluate(&offspring); err != nil {
+				return err
+			}
+			var (
+				idxs       = randomInts(window, 0, n, pop.RNG)
+				candidates = make(Individuals, len(idxs))
+			)
+			for i, idx := range idxs {
+				candidates[i] = pop.Individuals[idx]
+			}
+			var closest = idxs[offspring.IdxOfClosest(candidates, dm)]
+			if err := pop.evaluate(&pop.Individuals[closest]); err != nil {
+				return err
+			}
+			if offspring.Fitness < pop.Individuals[closest].Fitness {
+				pop.Individuals[closest] = offspring
+			}
+		}
+	}
+	return nil
+}
+
+// Validate ModRestrictedTournament fields.
+func (mod ModRestrictedTournament) Validate() error {
+	if err := validateCrowding(mod.CrossRate, mod.MutRate, mod.Metric); err != nil {
+		return err
+	}
+	if mod.WindowSize == 0 {
+		return errors.New("WindowSize should be higher than 0")
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package eaopt
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCrowdingKeepsParents(t *testing.T) {
+	// Offsprings that are identical to their parents never replace them
+	var models = []Model{
+		ModDeterministicCrowding{Metric: l1Distance},
+		ModRestrictedTournament{Metric: l1Distance, WindowSize: 4},
+	}
+	for _, mod := range models {
+		var pop = Population{Individuals: newLineIndividuals(0, 1, 2, 3), RNG: newRand()}
+		if err := pop.evaluateAll(false); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		pop.Run = &RunContext{}
+		var ids = make([]string, len(pop.Individuals))
+		for i, indi := range pop.Individuals {
+			ids[i] = indi.ID
+		}
+		if err := mod.Apply(&pop); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		for i, indi := range pop.Individuals {
+			if indi.ID != ids[i] {
+				t.Errorf("%T: individual %d was replaced", mod, i)
+			}
+		}
+		if n := pop.Run.Evaluations(); n != 0 {
+			t.Errorf("%T: expected 0 evaluations, got %d", mod, n)
+		}
+	}
+}
+
+func TestCrowdingEvaluations(t *testing.T) {
+	var models = []Model{
+		ModDeterministicCrowding{MutRate: 1, Metric: l1Distance},
+		ModProbabilisticCrowding{MutRate: 1, Metric: l1Distance, Temperature: 1},
+		ModRestrictedTournament{MutRate: 1, Metric: l1Distance, WindowSize: 2},
+	}
+	for _, mod := range models {
+		var pop = Population{Individuals: newLineIndividuals(1, 2, 3, 4), RNG: newRand()}
+		if err := pop.evaluateAll(false); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		pop.Run = &RunContext{}
+		if err := mod.Apply(&pop); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		// Only the 4 mutated offsprings are evaluated
+		if n := pop.Run.Evaluations(); n != 4 {
+			t.Errorf("%T: expected 4 evaluations, got %d", mod, n)
+		}
+		for i, indi := range pop.Individuals {
+			if !indi.Evaluated {
+				t.Errorf("%T: individual %d isn't evaluated", mod, i)
+			}
+		}
+	}
+}
+
+func TestCrowdingErrors(t *testing.T) {
+	var valid = []Model{
+		ModDeterministicCrowding{CrossRate: 0.5, MutRate: 0.5, Metric: l1Distance},
+		ModProbabilisticCrowding{Metric: l1Distance, Temperature: 1},
+		ModRestrictedTournament{Metric: l1Distance, WindowSize: 1},
+	}
+	for _, mod := range valid {
+		if err := mod.Validate(); err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+		var pop = Population{Individuals: newIndividuals(4, false, NewErrorGenome, newRand()), RNG: newRand()}
+		if err := mod.Apply(&pop); err == nil {
+			t.Errorf("%T: expected error", mod)
+		}
+	}
+	var invalid = []Model{
+		ModDeterministicCrowding{MutRate: 2, Metric: l1Distance},
+		ModDeterministicCrowding{CrossRate: -1, Metric: l1Distance},
+		ModDeterministicCrowding{},
+		ModProbabilisticCrowding{Metric: l1Distance},
+		ModProbabilisticCrowding{Temperature: 1},
+		ModRestrictedTournament{Metric: l1Distance},
+		ModRestrictedTournament{WindowSize: 1},
+	}
+	for i, mod := range invalid {
+		if err := mod.Validate(); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+}
+
+func TestGACrowding(t *testing.T) {
+	var (
+		metric = func(a, b Individual) float64 {
+			return math.Abs(a.Genome.(twoWells).Vector[0] - b.Genome.(twoWells).Vector[0])
+		}
+		models = []Model{
+			ModDeterministicCrowding{CrossRate: 0.5, MutRate: 1, Metric: metric},
+			ModProbabilisticCrowding{CrossRate: 0.5, MutRate: 1, Metric: metric, Temperature: 0.01},
+			ModRestrictedTournament{CrossRate: 0.5, MutRate: 1, Metric: metric, WindowSize: 10},
+		}
+	)
+	for _, model := range models {
+		var ga, err = GAConfig{
+			NPops:        1,
+			PopSize:      40,
+			NGenerations: 30,
+			HofSize:      1,
+			Model:        model,
+			RNG:          newRand(),
+		}.NewGA()
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		err = ga.Minimize(func(rng *rand.Rand) Genome {
+			return twoWells{Vector(InitUnifFloat64(1, -10, 10, rng))}
+		})
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		// Both optima are found
+		var left, right bool
+		for _, indi := range ga.Populations[0].Individuals {
+			var x = indi.Genome.(twoWells).Vector[0]
+			left = left || math.Abs(x+5) < 0.5
+			right = right || math.Abs(x-5) < 0.5
+		}
+		if !left || !right {
+			t.Errorf("%T: expected both optima to be found", model)
+		}
+	}
+}
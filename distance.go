@@ -1,8 +1,11 @@
 package eaopt
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"runtime"
+	"sync"
 )
 
 // A Metric returns the distance between two genomes.
@@ -124,3 +127,194 @@ func rebalanceClusters(clusters []Individuals, dm DistanceMemoizer, minPerCluste
 	}
 	return nil
 }
+
+// A DistanceMatrix stores the pairwise distances of a slice of Individuals in
+// a dense, index-based layout: the distance between the i-th and the j-th
+// Individual is given by At(i, j). Only the upper triangle is stored, which
+// takes n * (n - 1) / 2 floats for n Individuals. Contrary to the
+// DistanceMemoizer there are no map lookups and every distance is computed
+// exactly once.
+type DistanceMatrix struct {
+	n         int
+	distances []float64
+}
+
+// NewDistanceMatrix computes the pairwise distances of a slice of Individuals.
+// If parallel is true then the distances are computed concurrently, in which
+// case the Metric has to be safe for concurrent use.
+func NewDistanceMatrix(indis Individuals, metric Metric, parallel bool) DistanceMatrix {
+	var (
+		n  = len(indis)
+		dm = DistanceMatrix{n: n, distances: make([]float64, n*(n-1)/2)}
+		// Fill the rows i, i + step, i + 2 * step, ... of the upper triangle
+		fill = func(i, step int) {
+			for ; i < n; i += step {
+				var offset = dm.offset(i)
+				for j := i + 1; j < n; j++ {
+					dm.distances[offset+j] = metric(indis[i], indis[j])
+				}
+			}
+		}
+	)
+	if !parallel {
+		fill(0, 1)
+		return dm
+	}
+	// The rows get shorter and shorter, hence they are interleaved between the
+	// workers to balance the load
+	var (
+		nWorkers = minInt(runtime.GOMAXPROCS(-1), maxInt(n, 1))
+		wg       sync.WaitGroup
+	)
+	for w := 0; w < nWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			fill(w, nWorkers)
+		}(w)
+	}
+	wg.Wait()
+	return dm
+}
+
+// offset returns the position of row i in the distances slice, minus i + 1 so
+// that the distance between i and j > i is located at offset(i) + j.
+func (dm DistanceMatrix) offset(i int) int {
+	return i*dm.n - i*(i+1)/2 - i - 1
+}
+
+// Len returns the number of Individuals of the DistanceMatrix.
+func (dm DistanceMatrix) Len() int {
+	return dm.n
+}
+
+// At returns the distance between the i-th and the j-th Individuals.
+func (dm DistanceMatrix) At(i, j int) float64 {
+	switch {
+	case i == j:
+		return 0
+	case i > j:
+		i, j = j, i
+	}
+	return dm.distances[dm.offset(i)+j]
+}
+
+// set overwrites the distance between the i-th and the j-th Individuals, which
+// have to be different.
+func (dm DistanceMatrix) set(i, j int, dist float64) {
+	if i > j {
+		i, j = j, i
+	}
+	dm.distances[dm.offset(i)+j] = dist
+}
+
+// Medoid returns the index, among idxs, of the Individual that has the lowest
+// total distance to the other Individuals of idxs, which can't be empty.
+func (dm DistanceMatrix) Medoid(idxs []int) int {
+	var (
+		medoid = idxs[0]
+		min    = math.Inf(1)
+	)
+	for _, i := range idxs {
+		var total float64
+		for _, j := range idxs {
+			total += dm.At(i, j)
+		}
+		if total < min {
+			medoid, min = i, total
+		}
+	}
+	return medoid
+}
+
+// A DistanceCache memoizes the distances between Individuals based on the
+// contents of their Genomes and is meant to be reused across generations: the
+// distances between the Individuals that survive from one generation to the
+// next, including the ones that were cloned, don't have to be computed again.
+// The contents of a Genome are summarized by Key, which has to be exact: two
+// Genomes should share a key only if Metric can't tell them apart. In
+// particular the key has to change whenever the Genome is modified, for
+// instance by Mutate or Crossover, otherwise the cached distances would be
+// stale. Key should also be cheap compared to Metric, for instance a hash or
+// an identifier that is renewed at each modification. Its Distance method is
+// a Metric, hence it can be given to any Speciator or Model that expects a
+// Metric, as long as Metric only depends on the Genomes. A DistanceCache is
+// safe for concurrent use. Retain should be called regularly, for instance in
+// the Callback of the GA, so that the distances of the Individuals that have
+// disappeared are forgotten.
+type DistanceCache struct {
+	Metric    Metric
+	Key       func(genome Genome) string
+	mu        sync.RWMutex
+	distances map[[2]string]float64
+}
+
+// NewDistanceCache returns an empty DistanceCache after having checked that
+// metric and key are provided.
+func NewDistanceCache(metric Metric, key func(genome Genome) string) (*DistanceCache, error) {
+	if metric == nil {
+		return nil, errors.New("metric has to be provided")
+	}
+	if key == nil {
+		return nil, errors.New("key has to be provided")
+	}
+	return &DistanceCache{
+		Metric:    metric,
+		Key:       key,
+		distances: make(map[[2]string]float64),
+	}, nil
+}
+
+// key returns the key of the Genome of an Individual.
+func (dc *DistanceCache) key(indi Individual) string {
+	return dc.Key(indi.Genome)
+}
+
+// cacheKey returns the key under which the distance between two Genome keys is
+// stored.
+func cacheKey(a, b string) [2]string {
+	if a < b {
+		return [2]string{a, b}
+	}
+	return [2]string{b, a}
+}
+
+// Distance returns the distance between two Individuals, computing it only if
+// it isn't already in the cache.
+func (dc *DistanceCache) Distance(a, b Individual) float64 {
+	var key = cacheKey(dc.key(a), dc.key(b))
+	dc.mu.RLock()
+	var dist, ok = dc.distances[key]
+	dc.mu.RUnlock()
+	if ok {
+		return dist
+	}
+	dist = dc.Metric(a, b)
+	dc.mu.Lock()
+	dc.distances[key] = dist
+	dc.mu.Unlock()
+	return dist
+}
+
+// Retain forgets the distances involving Genomes that are not part of the
+// given Individuals.
+func (dc *DistanceCache) Retain(indis Individuals) {
+	var alive = make(map[string]bool, len(indis))
+	for _, indi := range indis {
+		alive[dc.key(indi)] = true
+	}
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	for key := range dc.distances {
+		if !alive[key[0]] || !alive[key[1]] {
+			delete(dc.distances, key)
+		}
+	}
+}
+
+// Len returns the number of distances stored in the DistanceCache.
+func (dc *DistanceCache) Len() int {
+	dc.mu.RLock()
+	defer dc.mu.RUnlock()
+	return len(dc.distances)
+}
@@ -3,6 +3,8 @@ package eaopt
 import (
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -188,3 +190,98 @@ func TestRebalanceClustersTooManyMissing(t *testing.T) {
 		t.Error("rebalanceClusters should have returned an error")
 	}
 }
+
+func TestDistanceMatrix(t *testing.T) {
+	for _, n := range []uint{0, 1, 2, 7, 30} {
+		for _, parallel := range []bool{false, true} {
+			var (
+				indis = newIndividuals(n, false, NewVector, newRand())
+				dm    = NewDistanceMatrix(indis, l1Distance, parallel)
+			)
+			if dm.Len() != int(n) {
+				t.Errorf("Expected %d, got %d", n, dm.Len())
+			}
+			for i := range indis {
+				for j := range indis {
+					if dm.At(i, j) != l1Distance(indis[i], indis[j]) {
+						t.Fatalf("Wrong distance between %d and %d", i, j)
+					}
+				}
+			}
+		}
+	}
+	var dm = NewDistanceMatrix(newLineIndividuals(0, 1, 2, 10), l1Distance, false)
+	if m := dm.Medoid([]int{0, 1, 2, 3}); m != 1 && m != 2 {
+		t.Errorf("Expected 1 or 2, got %d", m)
+	}
+	if m := dm.Medoid([]int{3, 0, 1}); m != 1 {
+		t.Errorf("Expected 1, got %d", m)
+	}
+}
+
+func TestDistanceCache(t *testing.T) {
+	var (
+		nCalls  int64
+		key     = func(genome Genome) string { return fmt.Sprint(genome) }
+		dc, err = NewDistanceCache(func(a, b Individual) float64 {
+			atomic.AddInt64(&nCalls, 1)
+			return l1Distance(a, b)
+		}, key)
+		indis = newLineIndividuals(0, 1, 3)
+		wg    sync.WaitGroup
+	)
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	// The cache can be used concurrently
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, a := range indis {
+				for _, b := range indis {
+					if dc.Distance(a, b) != l1Distance(a, b) {
+						t.Error("Wrong distance")
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	// The distances of the individuals to themselves are stored too
+	if dc.Len() != 6 || nCalls < 6 {
+		t.Errorf("Expected 6 distances, got %d with %d calls", dc.Len(), nCalls)
+	}
+	// Distances are reused across generations
+	nCalls = 0
+	var next = append(indis[1:], newLineIndividuals(4)...)
+	dc.Retain(next)
+	if dc.Len() != 3 {
+		t.Errorf("Expected 3 distances, got %d", dc.Len())
+	}
+	for _, a := range next {
+		for _, b := range next {
+			dc.Distance(a, b)
+		}
+	}
+	if nCalls != 3 || dc.Len() != 6 {
+		t.Errorf("Expected 3 calls and 6 distances, got %d and %d", nCalls, dc.Len())
+	}
+	// Clones hit the cache whereas mutated individuals don't
+	nCalls = 0
+	var clone = next[0].Clone(newRand())
+	if dc.Distance(clone, next[1]) != 2 || nCalls != 0 {
+		t.Errorf("Expected a cached distance of 2, got %f with %d calls", dc.Distance(clone, next[1]), nCalls)
+	}
+	clone.Genome.(Vector)[0] = 2
+	if d := dc.Distance(clone, next[1]); d != 1 || nCalls != 1 {
+		t.Errorf("Expected a distance of 1 computed once, got %f with %d calls", d, nCalls)
+	}
+	// The Metric and the key are required
+	if _, err = NewDistanceCache(nil, key); err == nil {
+		t.Error("Expected error")
+	}
+	if _, err = NewDistanceCache(l1Distance, nil); err == nil {
+		t.Error("Expected error")
+	}
+}
@@ -16,6 +16,14 @@ type GA struct {
 	HallOfFame  Individuals   `json:"hall_of_fame"` // Sorted best Individuals ever encountered
 	Age         time.Duration `json:"duration"`     // Duration during which the GA has been evolved
 	Generations uint          `json:"generations"`  // Number of generations the GA has been evolved
+
+	run *RunContext
+}
+
+// Evaluations returns the number of Genome evaluations performed during the
+// current or last run.
+func (ga *GA) Evaluations() uint64 {
+	return ga.run.Evaluations()
 }
 
 // Find the best current Individual in each population and then compare the best
@@ -40,16 +48,31 @@ func updateHallOfFame(hof Individuals, indis Individuals, rng *rand.Rand) {
 }
 
 func (ga *GA) init(newGenome func(rng *rand.Rand) Genome) error {
+	return ga.initIslands(func(i int, rng *rand.Rand) Genome { return newGenome(rng) })
+}
+
+// initIslands is the same as init except that newGenome is also given the
+// index of the Population the Genome is being generated for.
+func (ga *GA) initIslands(newGenome func(i int, rng *rand.Rand) Genome) error {
 	// Reset counters
 	ga.Generations = 0
 	ga.Age = 0
+	ga.run = newRunContext(ga.NGenerations)
+	ga.run.parallelEval = ga.ParallelEval
 
 	// Create the initial Populations
 	ga.Populations = make(Populations, ga.NPops)
 	for i := range ga.Populations {
-		ga.Populations[i] = newPopulation(ga.PopSize, ga.ParallelInit, newGenome, ga.RNG)
+		var i = i // https://golang.org/doc/faq#closures_and_goroutines
+		ga.Populations[i] = newPopulation(
+			ga.island(i).PopSize,
+			ga.ParallelInit,
+			func(rng *rand.Rand) Genome { return newGenome(i, rng) },
+			ga.RNG,
+		)
+		ga.Populations[i].Run = ga.run
 		// Evaluate and sort
-		err := ga.Populations[i].Individuals.Evaluate(ga.ParallelEval)
+		err := ga.Populations[i].evaluateAll(ga.ParallelEval)
 		if err != nil {
 			return err
 		}
@@ -81,31 +104,41 @@ func (ga *GA) init(newGenome func(rng *rand.Rand) Genome) error {
 func (ga *GA) evolve() error {
 	var start = time.Now()
 	ga.Generations++
+	ga.run.Generation = ga.Generations
 
 	// Migrate the individuals between the populations if there are at least 2
 	// Populations and that there is a migrator and that the migration frequency
 	// divides the generation count
 	if len(ga.Populations) > 1 && ga.Migrator != nil && ga.Generations%ga.MigFrequency == 0 {
-		ga.Migrator.Apply(ga.Populations, ga.RNG)
+		if mig, ok := ga.Migrator.(FallibleMigrator); ok {
+			if err := mig.TryApply(ga.Populations, ga.RNG); err != nil {
+				return err
+			}
+		} else {
+			ga.Migrator.Apply(ga.Populations, ga.RNG)
+		}
 	}
 
-	var f = func(pop *Population) error {
-		var err error
+	var f = func(i int, pop *Population) error {
+		var (
+			island = ga.island(i)
+			err    error
+		)
 		// Apply speciation if a positive number of species has been specified
-		if ga.Speciator != nil {
-			err = pop.speciateEvolveMerge(ga.Speciator, ga.Model)
+		if island.Speciator != nil {
+			err = pop.speciateEvolveMerge(island.Speciator, island.Model)
 			if err != nil {
 				return err
 			}
 		} else {
 			// Else apply the evolution model to the entire population
-			err = ga.Model.Apply(pop)
+			err = island.Model.Apply(pop)
 			if err != nil {
 				return err
 			}
 		}
 		// Evaluate and sort
-		err = pop.Individuals.Evaluate(ga.ParallelEval)
+		err = pop.evaluateAll(ga.ParallelEval)
 		if err != nil {
 			return err
 		}
@@ -120,7 +153,7 @@ func (ga *GA) evolve() error {
 		return err
 	}
 
-	var err = ga.Populations.Apply(f)
+	var err = ga.Populations.applyIndexed(f)
 	if err != nil {
 		return err
 	}
@@ -142,8 +175,14 @@ func (ga *GA) evolve() error {
 // Minimize evolves the GA's Populations following the given evolutionary
 // method. The GA's hall of fame is updated after each generation.
 func (ga *GA) Minimize(newGenome func(rng *rand.Rand) Genome) error {
+	return ga.minimizeIslands(func(i int, rng *rand.Rand) Genome { return newGenome(rng) })
+}
+
+// minimizeIslands is the same as Minimize except that newGenome is also given
+// the index of the Population the Genome is being generated for.
+func (ga *GA) minimizeIslands(newGenome func(i int, rng *rand.Rand) Genome) error {
 	// Initialize the GA
-	var err = ga.init(newGenome)
+	var err = ga.initIslands(newGenome)
 	if err != nil {
 		return err
 	}
@@ -162,6 +201,9 @@ func (ga *GA) Minimize(newGenome func(rng *rand.Rand) Genome) error {
 }
 
 func (pop *Population) speciateEvolveMerge(spec Speciator, model Model) error {
+	if tracker, ok := spec.(speciesTracker); ok {
+		return pop.evolveSpecies(tracker, model)
+	}
 	var (
 		species, err = spec.Apply(pop.Individuals, pop.RNG)
 		pops         = make([]Population, len(species))
@@ -178,6 +220,7 @@ func (pop *Population) speciateEvolveMerge(spec Speciator, model Model) error {
 			Generations: pop.Generations,
 			ID:          randString(len(pop.ID), pop.RNG),
 			RNG:         pop.RNG,
+			Run:         pop.Run,
 		}
 		err = model.Apply(&pops[i])
 		if err != nil {
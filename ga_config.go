@@ -22,12 +22,42 @@ type GAConfig struct {
 	Migrator     Migrator
 	MigFrequency uint // Frequency at which migrations occur
 	Speciator    Speciator
+	Islands      []IslandConfig // Per Population overrides of PopSize, Model and Speciator
 	Logger       *log.Logger
 	Callback     func(ga *GA)
 	EarlyStop    func(ga *GA) bool
 	RNG          *rand.Rand
 }
 
+// IslandConfig overrides some fields of a GAConfig for a single Population,
+// which makes it possible to run heterogeneous island models. The selector and
+// the mutation and crossover rates are part of the Model. Fields that are left
+// to their zero value fall back on the ones of the GAConfig.
+type IslandConfig struct {
+	PopSize   uint
+	Model     Model
+	Speciator Speciator
+}
+
+// island returns the configuration of the i-th Population, the fields of the
+// GAConfig being used for the ones the IslandConfig doesn't override.
+func (conf GAConfig) island(i int) IslandConfig {
+	var island = IslandConfig{PopSize: conf.PopSize, Model: conf.Model, Speciator: conf.Speciator}
+	if i >= len(conf.Islands) {
+		return island
+	}
+	if conf.Islands[i].PopSize != 0 {
+		island.PopSize = conf.Islands[i].PopSize
+	}
+	if conf.Islands[i].Model != nil {
+		island.Model = conf.Islands[i].Model
+	}
+	if conf.Islands[i].Speciator != nil {
+		island.Speciator = conf.Islands[i].Speciator
+	}
+	return island
+}
+
 // NewGA returns a pointer to a GA instance and checks for configuration
 // errors.
 func (conf GAConfig) NewGA() (*GA, error) {
@@ -35,24 +65,39 @@ func (conf GAConfig) NewGA() (*GA, error) {
 	if conf.RNG == nil {
 		conf.RNG = rand.New(rand.NewSource(time.Now().UnixNano()))
 	}
+	// NPops defaults to the number of islands
+	if len(conf.Islands) > 0 {
+		if conf.NPops != 0 && conf.NPops != uint(len(conf.Islands)) {
+			return nil, errors.New("NPops has to be equal to the number of Islands")
+		}
+		conf.NPops = uint(len(conf.Islands))
+	}
 	// Check the configuration is valid
 	if conf.NPops == 0 {
 		return nil, errors.New("NPops has to be strictly higher than 0")
 	}
-	if conf.PopSize == 0 {
-		return nil, errors.New("PopSize has to be strictly higher than 0")
-	}
 	if conf.NGenerations == 0 {
 		return nil, errors.New("NGenerations has to be strictly higher than 0")
 	}
 	if conf.HofSize == 0 {
 		return nil, errors.New("HofSize has to be strictly higher than 0")
 	}
-	if conf.Model == nil {
-		return nil, errors.New("model has to be provided")
-	}
-	if modelErr := conf.Model.Validate(); modelErr != nil {
-		return nil, modelErr
+	for i := 0; i < int(conf.NPops); i++ {
+		var island = conf.island(i)
+		if island.PopSize == 0 {
+			return nil, errors.New("PopSize has to be strictly higher than 0")
+		}
+		if island.Model == nil {
+			return nil, errors.New("model has to be provided")
+		}
+		if modelErr := island.Model.Validate(); modelErr != nil {
+			return nil, modelErr
+		}
+		if island.Speciator != nil {
+			if specErr := island.Speciator.Validate(); specErr != nil {
+				return nil, specErr
+			}
+		}
 	}
 	if conf.Migrator != nil {
 		if migErr := conf.Migrator.Validate(); migErr != nil {
@@ -62,19 +107,8 @@ func (conf GAConfig) NewGA() (*GA, error) {
 			return nil, errors.New("MigFrequency should be higher than 0")
 		}
 	}
-	if conf.Speciator != nil {
-		if specErr := conf.Speciator.Validate(); specErr != nil {
-			return nil, specErr
-		}
-	}
 	// Initialize the GA
 	ga := &GA{GAConfig: conf}
-	// As a special case (and grotesque hack), point ModSimulatedAnnealing
-	// to the GA
-	if msa, ok := conf.Model.(ModSimulatedAnnealing); ok {
-		msa.GA = ga
-		ga.GAConfig.Model = msa
-	}
 	// Return the GA
 	return ga, nil
 }
@@ -2,6 +2,7 @@ package eaopt
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -30,6 +31,29 @@ func TestNewGAErrors(t *testing.T) {
 		{func() GAConfig { c := NewDefaultGAConfig(); c.Migrator = MigRing{0}; return c }()},
 		{func() GAConfig { c := NewDefaultGAConfig(); c.Migrator = MigRing{1}; c.MigFrequency = 0; return c }()},
 		{func() GAConfig { c := NewDefaultGAConfig(); c.Speciator = SpecValidateError{}; return c }()},
+		{func() GAConfig { c := NewDefaultGAConfig(); c.Islands = make([]IslandConfig, 2); return c }()},
+		{func() GAConfig {
+			c := NewDefaultGAConfig()
+			c.NPops, c.PopSize = 0, 0
+			c.Islands = []IslandConfig{{PopSize: 10}, {}}
+			return c
+		}()},
+		{func() GAConfig {
+			c := NewDefaultGAConfig()
+			c.Model = nil
+			c.Islands = []IslandConfig{{Model: ModIdentity{}}, {}}
+			return c
+		}()},
+		{func() GAConfig {
+			c := NewDefaultGAConfig()
+			c.Islands = []IslandConfig{{Model: ModValidateError{}}}
+			return c
+		}()},
+		{func() GAConfig {
+			c := NewDefaultGAConfig()
+			c.Islands = []IslandConfig{{Speciator: SpecValidateError{}}}
+			return c
+		}()},
 	}
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
@@ -41,6 +65,21 @@ func TestNewGAErrors(t *testing.T) {
 	}
 }
 
+func TestGAConfigIsland(t *testing.T) {
+	var conf = NewDefaultGAConfig()
+	conf.Islands = []IslandConfig{{PopSize: 10, Speciator: SpecFitnessInterval{K: 2}}, {Model: ModIdentity{}}}
+	var testCases = []IslandConfig{
+		{PopSize: 10, Model: conf.Model, Speciator: SpecFitnessInterval{K: 2}},
+		{PopSize: conf.PopSize, Model: ModIdentity{}},
+		{PopSize: conf.PopSize, Model: conf.Model},
+	}
+	for i, tc := range testCases {
+		if island := conf.island(i); !reflect.DeepEqual(island, tc) {
+			t.Errorf("Island %d: expected %v, got %v", i, tc, island)
+		}
+	}
+}
+
 func TestNewDefaultGAConfig(t *testing.T) {
 	var _, err = NewDefaultGAConfig().NewGA()
 	if err != nil {
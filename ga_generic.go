@@ -0,0 +1,162 @@
+package eaopt
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// A GenomeOf is a Genome whose Crossover and Clone methods work with the
+// concrete genome type G instead of the Genome interface, which removes the
+// need for type assertions. It is usually implemented by a pointer type, for
+// instance *Tour would implement GenomeOf[*Tour].
+type GenomeOf[G any] interface {
+	Evaluate() (float64, error)
+	Mutate(rng *rand.Rand)
+	Crossover(mate G, rng *rand.Rand)
+	Clone() G
+}
+
+// genomeOf adapts a GenomeOf to the Genome interface so that it can be handled
+// by a GA. The type assertion in Crossover can't fail because a GAOf only
+// produces genomeOfs of a single type. The optional Sizer, CaseEvaluator and
+// BitGenome interfaces are checked on the wrapped genome through unwrap,
+// functions that assert other concrete genome types, such as NEAT.Distance,
+// have to be wrapped with MetricOf.
+type genomeOf[G GenomeOf[G]] struct {
+	genome G
+}
+
+func (g genomeOf[G]) Evaluate() (float64, error) { return g.genome.Evaluate() }
+func (g genomeOf[G]) Mutate(rng *rand.Rand)      { g.genome.Mutate(rng) }
+func (g genomeOf[G]) Clone() Genome              { return genomeOf[G]{g.genome.Clone()} }
+func (g genomeOf[G]) String() string             { return fmt.Sprint(g.genome) }
+func (g genomeOf[G]) unwrap() interface{}        { return g.genome }
+
+func (g genomeOf[G]) Crossover(mate Genome, rng *rand.Rand) {
+	g.genome.Crossover(mate.(genomeOf[G]).genome, rng)
+}
+
+// unwrapGenome returns the G wrapped in a Genome, or the zero value of G if
+// the Genome is nil.
+func unwrapGenome[G GenomeOf[G]](genome Genome) (g G) {
+	if genome == nil {
+		return
+	}
+	return genome.(genomeOf[G]).genome
+}
+
+// An IndividualOf is the counterpart of Individual for genomes of type G.
+type IndividualOf[G GenomeOf[G]] struct {
+	Genome    G         `json:"genome"`
+	Fitness   float64   `json:"fitness"`
+	Errors    []float64 `json:"errors,omitempty"` // Per-case errors, set if G is a CaseEvaluator
+	Evaluated bool      `json:"-"`
+	ID        string    `json:"id"`
+}
+
+// Individual converts an IndividualOf to an Individual, which can then be
+// given to Selectors, Speciators and the like. The genome is not copied.
+func (indi IndividualOf[G]) Individual() Individual {
+	return Individual{
+		Genome:    genomeOf[G]{indi.Genome},
+		Fitness:   indi.Fitness,
+		Errors:    indi.Errors,
+		Evaluated: indi.Evaluated,
+		ID:        indi.ID,
+	}
+}
+
+// String representation of an IndividualOf.
+func (indi IndividualOf[G]) String() string {
+	return indi.Individual().String()
+}
+
+// IndividualsOf converts Individuals that were produced by a GAOf[G] to
+// IndividualOfs. It panics if the genomes are not of type G. The genomes are
+// not copied.
+func IndividualsOf[G GenomeOf[G]](indis Individuals) []IndividualOf[G] {
+	var typed = make([]IndividualOf[G], len(indis))
+	for i, indi := range indis {
+		typed[i] = IndividualOf[G]{
+			Genome:    unwrapGenome[G](indi.Genome),
+			Fitness:   indi.Fitness,
+			Errors:    indi.Errors,
+			Evaluated: indi.Evaluated,
+			ID:        indi.ID,
+		}
+	}
+	return typed
+}
+
+// SelectOf applies a Selector to IndividualOfs.
+func SelectOf[G GenomeOf[G]](sel Selector, n uint, indis []IndividualOf[G], rng *rand.Rand) ([]IndividualOf[G], []int, error) {
+	var untyped = make(Individuals, len(indis))
+	for i, indi := range indis {
+		untyped[i] = indi.Individual()
+	}
+	var selected, idxs, err = sel.Apply(n, untyped, rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	return IndividualsOf[G](selected), idxs, nil
+}
+
+// MetricOf turns a distance function between genomes of type G into a Metric
+// that can be used by the Speciators and the DistanceMemoizer of a GAOf[G].
+func MetricOf[G GenomeOf[G]](f func(a, b G) float64) Metric {
+	return func(a, b Individual) float64 {
+		return f(unwrapGenome[G](a.Genome), unwrapGenome[G](b.Genome))
+	}
+}
+
+// A GAOf is a GA that evolves genomes of type G. The Callback and EarlyStop
+// fields receive the GAOf itself so that the hall of fame and the populations
+// can be accessed with the concrete genome type through Best and
+// IndividualsOf. The fields of the embedded GA, including the Callback and
+// EarlyStop fields of its configuration, remain usable.
+type GAOf[G GenomeOf[G]] struct {
+	*GA
+	Callback  func(ga *GAOf[G])
+	EarlyStop func(ga *GAOf[G]) bool
+}
+
+// NewGAOf returns a GAOf after having checked for configuration errors.
+func NewGAOf[G GenomeOf[G]](conf GAConfig) (*GAOf[G], error) {
+	var ga, err = conf.NewGA()
+	if err != nil {
+		return nil, err
+	}
+	return &GAOf[G]{GA: ga}, nil
+}
+
+// Best returns the best IndividualOf encountered so far. The zero value is
+// returned if the GAOf hasn't been run yet.
+func (ga *GAOf[G]) Best() IndividualOf[G] {
+	if len(ga.HallOfFame) == 0 {
+		return IndividualOf[G]{}
+	}
+	return IndividualsOf[G](ga.HallOfFame[:1])[0]
+}
+
+// Minimize runs the GA on genomes produced by newGenome.
+func (ga *GAOf[G]) Minimize(newGenome func(rng *rand.Rand) G) error {
+	var callback, earlyStop = ga.GA.Callback, ga.GA.EarlyStop
+	defer func() { ga.GA.Callback, ga.GA.EarlyStop = callback, earlyStop }()
+	ga.GA.Callback = func(*GA) {
+		if callback != nil {
+			callback(ga.GA)
+		}
+		if ga.Callback != nil {
+			ga.Callback(ga)
+		}
+	}
+	ga.GA.EarlyStop = func(*GA) bool {
+		if earlyStop != nil && earlyStop(ga.GA) {
+			return true
+		}
+		return ga.EarlyStop != nil && ga.EarlyStop(ga)
+	}
+	return ga.GA.Minimize(func(rng *rand.Rand) Genome {
+		return genomeOf[G]{newGenome(rng)}
+	})
+}
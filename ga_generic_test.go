@@ -0,0 +1,184 @@
+package eaopt
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+type typedVector []float64
+
+func (v typedVector) Evaluate() (float64, error) { return Vector(v).Evaluate() }
+func (v typedVector) Mutate(rng *rand.Rand)      { MutNormalFloat64(v, 0.5, rng) }
+func (v typedVector) Crossover(w typedVector, rng *rand.Rand) {
+	CrossUniformFloat64(v, w, rng)
+}
+func (v typedVector) Clone() typedVector { return append(typedVector(nil), v...) }
+func (v typedVector) Size() int          { return len(v) }
+
+func newTypedVector(rng *rand.Rand) typedVector {
+	return InitUnifFloat64(4, -10, 10, rng)
+}
+
+func TestGAOfMinimize(t *testing.T) {
+	var conf = NewDefaultGAConfig()
+	conf.NGenerations = 10
+	var untypedCalls int
+	conf.Callback = func(ga *GA) { untypedCalls++ }
+	var ga, err = NewGAOf[typedVector](conf)
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	var (
+		typedCalls int
+		best       = math.Inf(1)
+	)
+	ga.Callback = func(ga *GAOf[typedVector]) {
+		typedCalls++
+		var indi = ga.Best()
+		if len(indi.Genome) != 4 {
+			t.Errorf("Expected 4 genes, got %d", len(indi.Genome))
+		}
+		if indi.Fitness > best {
+			t.Error("The best fitness should never increase")
+		}
+		best = indi.Fitness
+	}
+	if err = ga.Minimize(newTypedVector); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if typedCalls != 11 || untypedCalls != 11 {
+		t.Errorf("Expected 11 calls, got %d and %d", typedCalls, untypedCalls)
+	}
+	var pop = IndividualsOf[typedVector](ga.Populations[0].Individuals)
+	if len(pop) != int(conf.PopSize) || !pop[0].Evaluated {
+		t.Errorf("Unexpected population %v", pop)
+	}
+	// The original callbacks are restored
+	if ga.GA.Callback == nil || ga.GA.EarlyStop != nil {
+		t.Error("The callbacks of the underlying GA should have been restored")
+	}
+}
+
+func TestGAOfEarlyStop(t *testing.T) {
+	var conf = NewDefaultGAConfig()
+	conf.NGenerations = 100
+	var ga, _ = NewGAOf[typedVector](conf)
+	ga.EarlyStop = func(ga *GAOf[typedVector]) bool { return ga.Generations == 5 }
+	if err := ga.Minimize(newTypedVector); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if ga.Generations != 5 {
+		t.Errorf("Expected 5 generations, got %d", ga.Generations)
+	}
+}
+
+func TestNewGAOfError(t *testing.T) {
+	var conf = NewDefaultGAConfig()
+	conf.NPops = 0
+	if _, err := NewGAOf[typedVector](conf); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestGAOfBestBeforeMinimize(t *testing.T) {
+	var ga, err = NewGAOf[typedVector](NewDefaultGAConfig())
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if best := ga.Best(); best.Genome != nil {
+		t.Errorf("Expected the zero value, got %v", best)
+	}
+}
+
+type typedBits struct{ *Bitstring }
+
+func (b typedBits) Evaluate() (float64, error)            { return float64(b.Count()), nil }
+func (b typedBits) Mutate(rng *rand.Rand)                 { b.Flip(rng.Intn(b.Len())) }
+func (b typedBits) Crossover(c typedBits, rng *rand.Rand) {}
+func (b typedBits) Clone() typedBits                      { return typedBits{b.Copy()} }
+
+func TestGenomeOfBits(t *testing.T) {
+	var a, b = typedBits{NewBitstring(8)}, typedBits{NewBitstring(8)}
+	b.Set(1, true)
+	b.Set(5, true)
+	var d = HammingDistance(
+		IndividualOf[typedBits]{Genome: a}.Individual(),
+		IndividualOf[typedBits]{Genome: b}.Individual(),
+	)
+	if d != 2 {
+		t.Errorf("Expected 2, got %f", d)
+	}
+	// The optional interfaces are only satisfied if the wrapped genome
+	// implements them
+	var indi = IndividualOf[typedVector]{Genome: typedVector{1}}.Individual()
+	if _, ok := unwrapped(indi.Genome).(BitGenome); ok {
+		t.Error("typedVector is not a BitGenome")
+	}
+	if _, ok := unwrapped(IndividualOf[typedBits]{Genome: a}.Individual().Genome).(Sizer); ok {
+		t.Error("typedBits is not a Sizer")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic")
+		}
+	}()
+	HammingDistance(indi, indi)
+}
+
+func TestSelectOfLexicase(t *testing.T) {
+	var indis = []IndividualOf[typedCases]{
+		{Genome: typedCases{0, 5}},
+		{Genome: typedCases{5, 0}},
+		{Genome: typedCases{4, 4}},
+	}
+	var selected, idxs, err = SelectOf(SelLexicase{}, 20, indis, newRand())
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	for i, idx := range idxs {
+		if idx == 2 || len(selected[i].Errors) != 2 || !selected[i].Evaluated {
+			t.Errorf("Unexpected selection %v", selected[i])
+		}
+	}
+	// The errors are kept when converting back and forth
+	var back = IndividualsOf[typedCases](Individuals{selected[0].Individual()})
+	if len(back[0].Errors) != 2 {
+		t.Errorf("Expected 2 errors, got %v", back[0].Errors)
+	}
+}
+
+func TestIndividualsOf(t *testing.T) {
+	var (
+		rng   = newRand()
+		indis = []IndividualOf[typedVector]{
+			{Genome: typedVector{1, 2}, Fitness: 3, Evaluated: true, ID: "a"},
+			{Genome: typedVector{4}, Fitness: 1, Evaluated: true, ID: "b"},
+		}
+	)
+	// Selectors work on typed individuals
+	var selected, idxs, err = SelectOf(SelElitism{}, 1, indis, rng)
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if len(selected) != 1 || len(idxs) != 1 || selected[0].Genome[0] != 4 {
+		t.Errorf("Unexpected selection %v", selected)
+	}
+	// Sizer is forwarded
+	if s := genomeSize(indis[0].Individual()); s != 2 {
+		t.Errorf("Expected 2, got %d", s)
+	}
+	// Metrics can be defined on the concrete type
+	var metric = MetricOf(func(a, b typedVector) float64 { return float64(len(a) - len(b)) })
+	if d := metric(indis[0].Individual(), indis[1].Individual()); d != 1 {
+		t.Errorf("Expected 1, got %f", d)
+	}
+	// Individuals without a genome are converted to the zero value
+	var typed = IndividualsOf[typedVector](Individuals{{Fitness: math.Inf(1)}})
+	if typed[0].Genome != nil {
+		t.Errorf("Expected nil, got %v", typed[0].Genome)
+	}
+	if s := indis[0].String(); s != "a - 3.000 - [1 2]" {
+		t.Errorf("Unexpected string %q", s)
+	}
+}
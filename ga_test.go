@@ -226,6 +226,47 @@ func TestEvolveWithSpeciator(t *testing.T) {
 	}
 }
 
+func TestGAIslands(t *testing.T) {
+	var seen []float64
+	for _, mig := range []Migrator{
+		MigRing{NMigrants: 15},
+		MigTopology{Topology: TopoFullyConnected{}, NMigrants: 3, Emigrants: SelElitism{}},
+	} {
+		var conf = NewDefaultGAConfig()
+		conf.NPops = 0
+		conf.NGenerations = 5
+		conf.Migrator = mig
+		conf.MigFrequency = 1
+		conf.Islands = []IslandConfig{
+			{PopSize: 20},
+			{PopSize: 10, Model: modRecord{&seen}},
+			{Model: ModSteadyState{Selector: SelElitism{}, MutRate: 1}, Speciator: SpecFitnessInterval{K: 2}},
+		}
+		var ga, err = conf.NewGA()
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		if err = ga.Minimize(NewVector); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		if ga.NPops != 3 {
+			t.Errorf("Expected 3 populations, got %d", ga.NPops)
+		}
+		for i, size := range []int{20, 10, 30} {
+			if n := len(ga.Populations[i].Individuals); n != size {
+				t.Errorf("%T: expected population %d to contain %d individuals, got %d", mig, i, size, n)
+			}
+			if ga.HallOfFame[0].Fitness > ga.Populations[i].Individuals[0].Fitness {
+				t.Errorf("%T: the hall of fame misses the best individual of population %d", mig, i)
+			}
+		}
+		// The Model of the second island is only applied to it
+		if len(seen) != 10 {
+			t.Errorf("%T: expected 10 fitnesses, got %d", mig, len(seen))
+		}
+	}
+}
+
 func TestGALog(t *testing.T) {
 	var ga, err = NewDefaultGAConfig().NewGA()
 	if err != nil {
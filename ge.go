@@ -0,0 +1,329 @@
+package eaopt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+)
+
+var errIncompleteDerivation = errors.New("derivation could not be completed with the available codons")
+
+// A GrammarSymbol is either a terminal, which is copied as is into the
+// phenotype, or a non-terminal, which has to be expanded with one of the
+// productions of the corresponding rule.
+type GrammarSymbol struct {
+	Value       string
+	NonTerminal bool
+}
+
+// A Grammar is a context-free grammar. Each non-terminal is associated with a
+// list of productions and a production is a sequence of symbols.
+type Grammar struct {
+	Start string
+	Rules map[string][][]GrammarSymbol
+}
+
+// tokenizeProduction splits a production into symbols. Non-terminals are
+// enclosed in angle brackets, terminals can be enclosed in double or single
+// quotes in order to contain spaces or special characters.
+func tokenizeProduction(prod string) ([]GrammarSymbol, error) {
+	var (
+		symbols []GrammarSymbol
+		s       = strings.TrimSpace(prod)
+	)
+	for len(s) > 0 {
+		switch s[0] {
+		case '<':
+			var end = strings.IndexByte(s, '>')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed non-terminal in %q", prod)
+			}
+			symbols = append(symbols, GrammarSymbol{Value: s[1:end], NonTerminal: true})
+			s = s[end+1:]
+		case '"', '\'':
+			var end = strings.IndexByte(s[1:], s[0])
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed quote in %q", prod)
+			}
+			symbols = append(symbols, GrammarSymbol{Value: s[1 : end+1]})
+			s = s[end+2:]
+		default:
+			var end = strings.IndexAny(s, " \t<\"'")
+			if end < 0 {
+				end = len(s)
+			}
+			symbols = append(symbols, GrammarSymbol{Value: s[:end]})
+			s = s[end:]
+		}
+		s = strings.TrimLeft(s, " \t")
+	}
+	return symbols, nil
+}
+
+// splitProductions splits the right-hand side of a rule on the '|' characters
+// that are not inside quotes or angle brackets.
+func splitProductions(rhs string) []string {
+	var (
+		prods   []string
+		closing byte // Character that closes the current quote or non-terminal
+		start   int
+	)
+	for i := 0; i < len(rhs); i++ {
+		switch c := rhs[i]; {
+		case closing != 0:
+			if c == closing {
+				closing = 0
+			}
+		case c == '"' || c == '\'':
+			closing = c
+		case c == '<':
+			closing = '>'
+		case c == '|':
+			prods = append(prods, rhs[start:i])
+			start = i + 1
+		}
+	}
+	return append(prods, rhs[start:])
+}
+
+// ParseGrammar parses a grammar written in BNF. Each rule has the form
+// "<name> ::= production | production | ...", productions may continue on the
+// following lines as long as those start with '|'. Empty lines and lines
+// starting with '#' are ignored. The first rule defines the start symbol.
+func ParseGrammar(r io.Reader) (*Grammar, error) {
+	var (
+		g       = &Grammar{Rules: make(map[string][][]GrammarSymbol)}
+		scanner = bufio.NewScanner(r)
+		current string
+	)
+	for scanner.Scan() {
+		var line = strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var rhs string
+		if strings.HasPrefix(line, "|") {
+			if current == "" {
+				return nil, fmt.Errorf("production %q does not belong to a rule", line)
+			}
+			rhs = line[1:]
+		} else {
+			var parts = strings.SplitN(line, "::=", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("missing ::= in %q", line)
+			}
+			var lhs = strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(lhs, "<") || !strings.HasSuffix(lhs, ">") {
+				return nil, fmt.Errorf("left-hand side %q is not a non-terminal", lhs)
+			}
+			current = lhs[1 : len(lhs)-1]
+			if g.Start == "" {
+				g.Start = current
+			}
+			rhs = parts[1]
+		}
+		for _, prod := range splitProductions(rhs) {
+			var symbols, err = tokenizeProduction(prod)
+			if err != nil {
+				return nil, err
+			}
+			g.Rules[current] = append(g.Rules[current], symbols)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return g, g.Validate()
+}
+
+// LoadGrammar parses the BNF grammar contained in a file.
+func LoadGrammar(path string) (*Grammar, error) {
+	var f, err = os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseGrammar(f)
+}
+
+// Validate checks that a Grammar has a start symbol and that each non-terminal
+// is defined.
+func (g *Grammar) Validate() error {
+	if g.Start == "" {
+		return errors.New("grammar has no rules")
+	}
+	for name, prods := range g.Rules {
+		for _, prod := range prods {
+			for _, sym := range prod {
+				if _, ok := g.Rules[sym.Value]; sym.NonTerminal && !ok {
+					return fmt.Errorf("rule <%s> refers to undefined non-terminal <%s>", name, sym.Value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Map codons to a phenotype by performing a leftmost derivation of the
+// Grammar. Each time a non-terminal with more than one production is
+// expanded, the next codon modulo the number of productions determines which
+// production is chosen. When the codons run out they are reused from the
+// start, up to maxWraps times. Map returns the phenotype along with the number
+// of codons that were consumed, which may be higher than len(codons) if
+// wrapping occurred. An error is returned if the derivation could not be
+// completed.
+func (g *Grammar) Map(codons []int, maxWraps uint) (string, int, error) {
+	var (
+		sb       strings.Builder
+		stack    = []GrammarSymbol{{Value: g.Start, NonTerminal: true}}
+		used     int
+		limit    = len(codons) * int(maxWraps+1)
+		nExpand  int
+		maxSteps = 100 * (limit + 1) // Guards against recursive single-production rules
+	)
+	for len(stack) > 0 {
+		var sym = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !sym.NonTerminal {
+			sb.WriteString(sym.Value)
+			continue
+		}
+		if nExpand++; nExpand > maxSteps {
+			return "", used, errIncompleteDerivation
+		}
+		var (
+			prods  = g.Rules[sym.Value]
+			choice int
+		)
+		if len(prods) > 1 {
+			if used >= limit {
+				return "", used, errIncompleteDerivation
+			}
+			choice = codons[used%len(codons)] % len(prods)
+			if choice < 0 {
+				choice += len(prods)
+			}
+			used++
+		}
+		// Push the symbols in reverse order so that the leftmost one is
+		// expanded first
+		var prod = prods[choice]
+		for i := len(prod) - 1; i >= 0; i-- {
+			stack = append(stack, prod[i])
+		}
+	}
+	return sb.String(), used, nil
+}
+
+// A GEGenome is a Genome for grammatical evolution. It is a fixed-length
+// slice of integer codons that is mapped to a phenotype with a Grammar.
+type GEGenome struct {
+	Codons    []int
+	Phenotype string // Set by Evaluate
+	Valid     bool   // Set by Evaluate, false if the codons didn't map to a complete phenotype
+	GE        *GE
+}
+
+// Evaluate a GEGenome by mapping it to a phenotype which is then given to the
+// GE's Fitness function. Invalid individuals are reported through the Valid
+// field and are assigned the GE's InvalidFitness.
+func (g *GEGenome) Evaluate() (float64, error) {
+	var phenotype, _, err = g.GE.Grammar.Map(g.Codons, g.GE.MaxWraps)
+	if err != nil {
+		g.Phenotype, g.Valid = "", false
+		return g.GE.InvalidFitness, nil
+	}
+	g.Phenotype, g.Valid = phenotype, true
+	return g.GE.Fitness(phenotype)
+}
+
+// Mutate a GEGenome by replacing each codon with a random value with
+// probability MutRate.
+func (g *GEGenome) Mutate(rng *rand.Rand) {
+	for i := range g.Codons {
+		if rng.Float64() < g.GE.MutRate {
+			g.Codons[i] = rng.Intn(int(g.GE.MaxCodon))
+		}
+	}
+}
+
+// Crossover a GEGenome with another GEGenome by using one-point crossover.
+func (g *GEGenome) Crossover(q Genome, rng *rand.Rand) {
+	CrossGNXInt(g.Codons, q.(*GEGenome).Codons, 1, rng)
+}
+
+// Clone returns a deep copy of a GEGenome.
+func (g GEGenome) Clone() Genome {
+	var codons = make([]int, len(g.Codons))
+	copy(codons, g.Codons)
+	return &GEGenome{
+		Codons:    codons,
+		Phenotype: g.Phenotype,
+		Valid:     g.Valid,
+		GE:        g.GE,
+	}
+}
+
+// GE contains the parameters of grammatical evolution. The NewGenome method
+// can be given to GA.Minimize to evolve GEGenomes.
+// Reference: https://doi.org/10.1109/4235.942529
+type GE struct {
+	Grammar        *Grammar
+	NCodons        uint    // Number of codons per genome
+	MaxCodon       uint    // Codons are sampled in [0, MaxCodon)
+	MaxWraps       uint    // Maximum number of times the codons can be reused
+	MutRate        float64 // Probability of mutating each codon
+	InvalidFitness float64 // Fitness assigned to invalid individuals
+	Fitness        func(phenotype string) (float64, error)
+}
+
+// NewGE returns a GE with a MaxCodon of 256, 2 wraps at most, a mutation rate
+// of 1/nCodons and +Inf as the fitness of invalid individuals.
+func NewGE(grammar *Grammar, nCodons uint, fitness func(phenotype string) (float64, error)) *GE {
+	return &GE{
+		Grammar:        grammar,
+		NCodons:        nCodons,
+		MaxCodon:       256,
+		MaxWraps:       2,
+		MutRate:        1 / float64(nCodons),
+		InvalidFitness: math.Inf(1),
+		Fitness:        fitness,
+	}
+}
+
+// NewGenome returns a GEGenome with random codons.
+func (ge *GE) NewGenome(rng *rand.Rand) Genome {
+	var codons = make([]int, ge.NCodons)
+	for i := range codons {
+		codons[i] = rng.Intn(int(ge.MaxCodon))
+	}
+	return &GEGenome{Codons: codons, GE: ge}
+}
+
+// Validate GE fields.
+func (ge *GE) Validate() error {
+	if ge.Grammar == nil {
+		return errors.New("grammar has to be provided")
+	}
+	if err := ge.Grammar.Validate(); err != nil {
+		return err
+	}
+	if ge.NCodons == 0 {
+		return errors.New("NCodons should be higher than 0")
+	}
+	if ge.MaxCodon < 2 {
+		return errors.New("MaxCodon should be higher than 1")
+	}
+	if ge.MutRate < 0 || ge.MutRate > 1 {
+		return errInvalidMutRate
+	}
+	if ge.Fitness == nil {
+		return errors.New("fitness function has to be provided")
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package eaopt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testBNF = `
+# Arithmetic expressions
+<expr> ::= <expr> <op> <expr> | "(" <expr> ")" | <var>
+<op>   ::= "+" | "-"
+       | "*"
+<var>  ::= x | 1
+`
+
+func TestParseGrammar(t *testing.T) {
+	var g, err = ParseGrammar(strings.NewReader(testBNF))
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if g.Start != "expr" {
+		t.Errorf("Expected expr, got %s", g.Start)
+	}
+	for name, n := range map[string]int{"expr": 3, "op": 3, "var": 2} {
+		if len(g.Rules[name]) != n {
+			t.Errorf("Expected %d productions for <%s>, got %d", n, name, len(g.Rules[name]))
+		}
+	}
+	var paren = g.Rules["expr"][1]
+	if len(paren) != 3 || paren[0].Value != "(" || !paren[1].NonTerminal {
+		t.Errorf("Unexpected production %v", paren)
+	}
+}
+
+func TestSplitProductions(t *testing.T) {
+	var prods = splitProductions(` <a|b> "|" | '|' <c> | d`)
+	if len(prods) != 3 || prods[0] != ` <a|b> "|" ` || prods[1] != ` '|' <c> ` {
+		t.Errorf("Unexpected productions %q", prods)
+	}
+}
+
+func TestParseGrammarErrors(t *testing.T) {
+	var testCases = []string{
+		"",
+		"<a> <b>",
+		"a ::= b",
+		"| b",
+		"<a> ::= <b>",
+		"<a> ::= <b",
+		`<a> ::= "b`,
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			if _, err := ParseGrammar(strings.NewReader(tc)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestLoadGrammar(t *testing.T) {
+	var dir, err = ioutil.TempDir("", "eaopt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var path = filepath.Join(dir, "grammar.bnf")
+	if err = ioutil.WriteFile(path, []byte(testBNF), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LoadGrammar(path); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if _, err = LoadGrammar(filepath.Join(dir, "missing.bnf")); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestGrammarMap(t *testing.T) {
+	var g, _ = ParseGrammar(strings.NewReader(testBNF))
+	var testCases = []struct {
+		codons    []int
+		maxWraps  uint
+		phenotype string
+		used      int
+		valid     bool
+	}{
+		// <expr> -> <var> -> x
+		{[]int{2, 0}, 0, "x", 2, true},
+		// <expr> -> (<expr>) -> (<var>) -> (1)
+		{[]int{1, 5, 1}, 0, "(1)", 3, true},
+		// <expr> -> <expr><op><expr> -> x<op><expr> -> x*<expr> -> x*1
+		{[]int{0, 2, 0, 2, 2, 1}, 0, "x*1", 6, true},
+		// Runs out of codons: <expr> -> (<expr>) -> ((<expr>)) -> ...
+		{[]int{1, 1, 1}, 0, "", 3, false},
+		// Needs wrapping: <expr> -> (<expr>) -> (<var>) -> (1)
+		{[]int{1, 2}, 0, "", 2, false},
+		{[]int{1, 2}, 1, "(1)", 3, true},
+		// Always recursing is invalid
+		{[]int{0}, 3, "", 4, false},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var phenotype, used, err = g.Map(tc.codons, tc.maxWraps)
+			if (err == nil) != tc.valid {
+				t.Errorf("Expected valid = %v, got %v", tc.valid, err)
+			}
+			if phenotype != tc.phenotype {
+				t.Errorf("Expected %q, got %q", tc.phenotype, phenotype)
+			}
+			if used != tc.used {
+				t.Errorf("Expected %d codons used, got %d", tc.used, used)
+			}
+		})
+	}
+}
+
+func TestGrammarMapRecursiveSingleProduction(t *testing.T) {
+	var g, _ = ParseGrammar(strings.NewReader("<a> ::= <a> x"))
+	if _, _, err := g.Map([]int{0}, 0); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestGEGenome(t *testing.T) {
+	var (
+		g, _ = ParseGrammar(strings.NewReader(testBNF))
+		ge   = NewGE(g, 10, func(phenotype string) (float64, error) {
+			return float64(len(phenotype)), nil
+		})
+		rng = newRand()
+	)
+	if err := ge.Validate(); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	var (
+		valid   = &GEGenome{Codons: []int{2, 0}, GE: ge}
+		invalid = &GEGenome{Codons: []int{0}, GE: ge}
+	)
+	if f, _ := valid.Evaluate(); f != 1 || !valid.Valid || valid.Phenotype != "x" {
+		t.Errorf("Unexpected evaluation of %v: %f", valid, f)
+	}
+	if f, _ := invalid.Evaluate(); !math.IsInf(f, 1) || invalid.Valid {
+		t.Errorf("Unexpected evaluation of %v: %f", invalid, f)
+	}
+	// Check genetic operators keep the codons within bounds
+	var g1, g2 = ge.NewGenome(rng).(*GEGenome), ge.NewGenome(rng).(*GEGenome)
+	var clone = g1.Clone().(*GEGenome)
+	g1.Crossover(g2, rng)
+	ge.MutRate = 1
+	g1.Mutate(rng)
+	for _, genome := range []*GEGenome{g1, g2, clone} {
+		if len(genome.Codons) != 10 {
+			t.Errorf("Expected 10 codons, got %d", len(genome.Codons))
+		}
+		for _, c := range genome.Codons {
+			if c < 0 || c >= int(ge.MaxCodon) {
+				t.Errorf("Codon %d is out of bounds", c)
+			}
+		}
+	}
+}
+
+func TestGEValidate(t *testing.T) {
+	var testCases = []func(ge *GE){
+		func(ge *GE) { ge.Grammar = nil },
+		func(ge *GE) { ge.Grammar = &Grammar{} },
+		func(ge *GE) { ge.NCodons = 0 },
+		func(ge *GE) { ge.MaxCodon = 1 },
+		func(ge *GE) { ge.MutRate = 2 },
+		func(ge *GE) { ge.Fitness = nil },
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var g, _ = ParseGrammar(strings.NewReader(testBNF))
+			var ge = NewGE(g, 10, func(string) (float64, error) { return 0, nil })
+			tc(ge)
+			if ge.Validate() == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestGEMinimize(t *testing.T) {
+	// Find an expression that contains exactly 5 occurrences of x
+	var (
+		g, _ = ParseGrammar(strings.NewReader(testBNF))
+		ge   = NewGE(g, 30, func(phenotype string) (float64, error) {
+			return math.Abs(float64(strings.Count(phenotype, "x") - 5)), nil
+		})
+		conf = NewDefaultGAConfig()
+	)
+	conf.PopSize = 50
+	conf.RNG = rand.New(rand.NewSource(42))
+	var ga, _ = conf.NewGA()
+	if err := ga.Minimize(ge.NewGenome); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if best := ga.HallOfFame[0]; best.Fitness != 0 || !best.Genome.(*GEGenome).Valid {
+		t.Errorf("Expected a valid individual with a fitness of 0, got %v", best)
+	}
+}
@@ -11,3 +11,19 @@ type Genome interface {
 	Crossover(genome Genome, rng *rand.Rand)
 	Clone() Genome
 }
+
+// An unwrapper is a Genome that wraps another value, such as the genomes
+// evolved by a GAOf. The optional interfaces of a Genome, such as Sizer,
+// CaseEvaluator and BitGenome, are looked up on the wrapped value.
+type unwrapper interface {
+	unwrap() interface{}
+}
+
+// unwrapped returns the value wrapped by a Genome, or the Genome itself if it
+// doesn't wrap anything. It is the value optional interfaces are checked on.
+func unwrapped(genome Genome) interface{} {
+	if u, ok := genome.(unwrapper); ok {
+		return u.unwrap()
+	}
+	return genome
+}
@@ -1,5 +1,5 @@
 module github.com/MaxHalford/eaopt
 
-go 1.15
+go 1.21
 
 require golang.org/x/sync v0.0.0-20201020160332-67f06af15bc9
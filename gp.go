@@ -0,0 +1,519 @@
+package eaopt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// A GPPrimitive is a function that can be used as an internal node of a GPTree.
+// In contains the types of the arguments and Out is the type of the returned
+// value, which makes it possible to evolve strongly typed programs.
+type GPPrimitive struct {
+	Name string
+	In   []string
+	Out  string
+	Fn   func(args []interface{}) interface{}
+}
+
+// A GPTerminal is a leaf of a GPTree. It can either be a constant or an input
+// variable, in which case Fn extracts it from the variables the GPTree is
+// evaluated with.
+type GPTerminal struct {
+	Name string
+	Out  string
+	Fn   func(vars []interface{}) interface{}
+}
+
+// A GPEphemeral is a leaf of a GPTree whose value is generated once when the
+// leaf is created and then stays constant.
+type GPEphemeral struct {
+	Name string
+	Out  string
+	Gen  func(rng *rand.Rand) interface{}
+}
+
+// A GPSet contains the building blocks of GPTrees.
+type GPSet struct {
+	Primitives []GPPrimitive
+	Terminals  []GPTerminal
+	Ephemerals []GPEphemeral
+}
+
+// primitivesOf returns the primitives that return a value of type out.
+func (set *GPSet) primitivesOf(out string) []*GPPrimitive {
+	var prims []*GPPrimitive
+	for i := range set.Primitives {
+		if set.Primitives[i].Out == out {
+			prims = append(prims, &set.Primitives[i])
+		}
+	}
+	return prims
+}
+
+// nLeavesOf returns the number of terminals and ephemerals that return a
+// value of type out.
+func (set *GPSet) nLeavesOf(out string) (n int) {
+	for _, term := range set.Terminals {
+		if term.Out == out {
+			n++
+		}
+	}
+	for _, eph := range set.Ephemerals {
+		if eph.Out == out {
+			n++
+		}
+	}
+	return
+}
+
+// newLeaf returns a random leaf of type out.
+func (set *GPSet) newLeaf(out string, rng *rand.Rand) *GPNode {
+	var k = rng.Intn(set.nLeavesOf(out))
+	for i := range set.Terminals {
+		if set.Terminals[i].Out == out {
+			if k == 0 {
+				return &GPNode{Terminal: &set.Terminals[i]}
+			}
+			k--
+		}
+	}
+	for i := range set.Ephemerals {
+		if set.Ephemerals[i].Out == out {
+			if k == 0 {
+				return &GPNode{Ephemeral: &set.Ephemerals[i], Value: set.Ephemerals[i].Gen(rng)}
+			}
+			k--
+		}
+	}
+	return nil
+}
+
+// Validate checks that a leaf exists for each type a primitive may require.
+func (set *GPSet) Validate() error {
+	if len(set.Terminals)+len(set.Ephemerals) == 0 {
+		return errors.New("at least one terminal or ephemeral has to be provided")
+	}
+	for _, prim := range set.Primitives {
+		if prim.Fn == nil {
+			return fmt.Errorf("primitive %s has no function", prim.Name)
+		}
+		for _, in := range prim.In {
+			if set.nLeavesOf(in) == 0 {
+				return fmt.Errorf("primitive %s requires a leaf of type %s", prim.Name, in)
+			}
+		}
+	}
+	for _, term := range set.Terminals {
+		if term.Fn == nil {
+			return fmt.Errorf("terminal %s has no function", term.Name)
+		}
+	}
+	for _, eph := range set.Ephemerals {
+		if eph.Gen == nil {
+			return fmt.Errorf("ephemeral %s has no generator", eph.Name)
+		}
+	}
+	return nil
+}
+
+// grow generates a random subtree of type out whose depth is at most maxDepth.
+// If full is true then every leaf is at depth maxDepth, as long as the set of
+// primitives allows it.
+func (set *GPSet) grow(out string, depth, maxDepth uint, full bool, rng *rand.Rand) *GPNode {
+	var (
+		prims   = set.primitivesOf(out)
+		nLeaves = set.nLeavesOf(out)
+	)
+	// Decide whether to place a leaf or not
+	var leaf = depth >= maxDepth || len(prims) == 0
+	if !leaf && !full && nLeaves > 0 {
+		leaf = rng.Intn(len(prims)+nLeaves) < nLeaves
+	}
+	if leaf && nLeaves > 0 {
+		return set.newLeaf(out, rng)
+	}
+	var (
+		prim = prims[rng.Intn(len(prims))]
+		node = &GPNode{Primitive: prim, Children: make([]*GPNode, len(prim.In))}
+	)
+	for i, in := range prim.In {
+		node.Children[i] = set.grow(in, depth+1, maxDepth, full, rng)
+	}
+	return node
+}
+
+// A GPNode is a node of a GPTree. Exactly one of the Primitive, Terminal and
+// Ephemeral fields is set.
+type GPNode struct {
+	Primitive *GPPrimitive
+	Terminal  *GPTerminal
+	Ephemeral *GPEphemeral
+	Value     interface{} // Value of an ephemeral constant
+	Children  []*GPNode
+}
+
+// Type returns the type of the value returned by a GPNode.
+func (node *GPNode) Type() string {
+	switch {
+	case node.Primitive != nil:
+		return node.Primitive.Out
+	case node.Terminal != nil:
+		return node.Terminal.Out
+	}
+	return node.Ephemeral.Out
+}
+
+// Eval evaluates the subtree rooted at a GPNode.
+func (node *GPNode) Eval(vars []interface{}) interface{} {
+	switch {
+	case node.Primitive != nil:
+		var args = make([]interface{}, len(node.Children))
+		for i, child := range node.Children {
+			args[i] = child.Eval(vars)
+		}
+		return node.Primitive.Fn(args)
+	case node.Terminal != nil:
+		return node.Terminal.Fn(vars)
+	}
+	return node.Value
+}
+
+// Size returns the number of nodes of the subtree rooted at a GPNode.
+func (node *GPNode) Size() int {
+	var size = 1
+	for _, child := range node.Children {
+		size += child.Size()
+	}
+	return size
+}
+
+// Depth returns the depth of the subtree rooted at a GPNode. A leaf has a
+// depth of 0.
+func (node *GPNode) Depth() int {
+	var depth int
+	for _, child := range node.Children {
+		if d := child.Depth() + 1; d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// Copy returns a deep copy of the subtree rooted at a GPNode.
+func (node *GPNode) Copy() *GPNode {
+	var clone = *node
+	if node.Children != nil {
+		clone.Children = make([]*GPNode, len(node.Children))
+		for i, child := range node.Children {
+			clone.Children[i] = child.Copy()
+		}
+	}
+	return &clone
+}
+
+// String returns a prefix representation of the subtree rooted at a GPNode.
+func (node *GPNode) String() string {
+	switch {
+	case node.Primitive != nil:
+		var args = make([]string, len(node.Children))
+		for i, child := range node.Children {
+			args[i] = child.String()
+		}
+		return fmt.Sprintf("%s(%s)", node.Primitive.Name, strings.Join(args, ", "))
+	case node.Terminal != nil:
+		return node.Terminal.Name
+	}
+	return fmt.Sprintf("%v", node.Value)
+}
+
+// A gpLocation points to a node of a GPTree through it's parent, which makes it
+// possible to replace the node.
+type gpLocation struct {
+	parent *GPNode // nil for the root
+	i      int     // Index of the node in the children of the parent
+	node   *GPNode
+}
+
+// locations lists the nodes of a GPTree in prefix order.
+func (t *GPTree) locations() []gpLocation {
+	var (
+		locs []gpLocation
+		walk func(parent *GPNode, i int, node *GPNode)
+	)
+	walk = func(parent *GPNode, i int, node *GPNode) {
+		locs = append(locs, gpLocation{parent, i, node})
+		for j, child := range node.Children {
+			walk(node, j, child)
+		}
+	}
+	walk(nil, 0, t.Root)
+	return locs
+}
+
+// replace the node at a location with another node.
+func (t *GPTree) replace(loc gpLocation, node *GPNode) {
+	if loc.parent == nil {
+		t.Root = node
+		return
+	}
+	loc.parent.Children[loc.i] = node
+}
+
+// A GPTree is a Genome that represents a program in the shape of a tree.
+type GPTree struct {
+	Root *GPNode
+	GP   *GP
+}
+
+// Eval evaluates a GPTree with the given input variables.
+func (t *GPTree) Eval(vars ...interface{}) interface{} {
+	return t.Root.Eval(vars)
+}
+
+// Size returns the number of nodes of a GPTree.
+func (t *GPTree) Size() int {
+	return t.Root.Size()
+}
+
+// Depth returns the depth of a GPTree.
+func (t *GPTree) Depth() int {
+	return t.Root.Depth()
+}
+
+// String returns a prefix representation of a GPTree.
+func (t *GPTree) String() string {
+	return t.Root.String()
+}
+
+// Evaluate a GPTree with the GP's Fitness function.
+func (t *GPTree) Evaluate() (float64, error) {
+	return t.GP.Fitness(t)
+}
+
+// withinLimits checks if a GPTree respects the GP's depth and size limits.
+func (t *GPTree) withinLimits() bool {
+	return (t.GP.MaxDepth == 0 || t.Depth() <= int(t.GP.MaxDepth)) &&
+		(t.GP.MaxSize == 0 || t.Size() <= int(t.GP.MaxSize))
+}
+
+// Mutate a GPTree by applying one of subtree, point and hoist mutation. The
+// mutation is chosen with probabilities proportional to the GP's
+// SubtreeMutWeight, PointMutWeight and HoistMutWeight fields. The mutation is
+// undone if the mutated GPTree exceeds the GP's depth or size limits.
+func (t *GPTree) Mutate(rng *rand.Rand) {
+	var (
+		root    = t.Root.Copy()
+		weights = []float64{t.GP.SubtreeMutWeight, t.GP.PointMutWeight, t.GP.HoistMutWeight}
+		total   = sumFloat64s(weights)
+	)
+	if total == 0 {
+		t.MutSubtree(rng)
+	} else {
+		var p = rng.Float64() * total
+		switch {
+		case p < weights[0]:
+			t.MutSubtree(rng)
+		case p < weights[0]+weights[1]:
+			t.MutPoint(rng)
+		default:
+			t.MutHoist(rng)
+		}
+	}
+	if !t.withinLimits() {
+		t.Root = root
+	}
+}
+
+// MutSubtree replaces a random subtree with a new subtree of the same type
+// that is grown up to the GP's MutMaxDepth.
+func (t *GPTree) MutSubtree(rng *rand.Rand) {
+	var (
+		locs = t.locations()
+		loc  = locs[rng.Intn(len(locs))]
+	)
+	t.replace(loc, t.GP.Set.grow(loc.node.Type(), 0, t.GP.MutMaxDepth, false, rng))
+}
+
+// MutPoint replaces a random node with a node of the same signature. An
+// ephemeral constant is replaced with a new leaf of the same type, which may
+// be a freshly generated constant.
+func (t *GPTree) MutPoint(rng *rand.Rand) {
+	var (
+		locs = t.locations()
+		loc  = locs[rng.Intn(len(locs))]
+		set  = &t.GP.Set
+	)
+	if loc.node.Primitive == nil {
+		t.replace(loc, set.newLeaf(loc.node.Type(), rng))
+		return
+	}
+	// Find the primitives with the same signature
+	var candidates []*GPPrimitive
+	for _, prim := range set.primitivesOf(loc.node.Type()) {
+		if fmt.Sprint(prim.In) == fmt.Sprint(loc.node.Primitive.In) {
+			candidates = append(candidates, prim)
+		}
+	}
+	loc.node.Primitive = candidates[rng.Intn(len(candidates))]
+}
+
+// MutHoist replaces a GPTree with one of it's subtrees that has the same type
+// as the root.
+func (t *GPTree) MutHoist(rng *rand.Rand) {
+	var candidates []*GPNode
+	for _, loc := range t.locations() {
+		if loc.node.Type() == t.Root.Type() {
+			candidates = append(candidates, loc.node)
+		}
+	}
+	t.Root = candidates[rng.Intn(len(candidates))]
+}
+
+// Crossover a GPTree with another GPTree by using subtree crossover. An
+// offspring that exceeds the GP's depth or size limits is replaced by it's
+// parent.
+func (t *GPTree) Crossover(q Genome, rng *rand.Rand) {
+	var (
+		u            = q.(*GPTree)
+		rootT, rootU = t.Root.Copy(), u.Root.Copy()
+	)
+	t.CrossSubtree(u, rng)
+	if !t.withinLimits() {
+		t.Root = rootT
+	}
+	if !u.withinLimits() {
+		u.Root = rootU
+	}
+}
+
+// CrossSubtree swaps a random subtree of a GPTree with a random subtree of the
+// same type from another GPTree. Nothing happens if no subtree of the same
+// type exists.
+func (t *GPTree) CrossSubtree(u *GPTree, rng *rand.Rand) {
+	var (
+		locsT = t.locations()
+		locT  = locsT[rng.Intn(len(locsT))]
+		locsU []gpLocation
+	)
+	for _, loc := range u.locations() {
+		if loc.node.Type() == locT.node.Type() {
+			locsU = append(locsU, loc)
+		}
+	}
+	if len(locsU) == 0 {
+		return
+	}
+	var locU = locsU[rng.Intn(len(locsU))]
+	t.replace(locT, locU.node)
+	u.replace(locU, locT.node)
+}
+
+// Clone returns a deep copy of a GPTree.
+func (t GPTree) Clone() Genome {
+	return &GPTree{Root: t.Root.Copy(), GP: t.GP}
+}
+
+// GP contains the parameters of tree-based genetic programming. The NewTree
+// method can be given to GA.Minimize to evolve GPTrees.
+type GP struct {
+	Set              GPSet
+	Out              string // Type returned by the programs
+	InitMinDepth     uint   // Minimum depth for ramped half-and-half initialization
+	InitMaxDepth     uint   // Maximum depth for ramped half-and-half initialization
+	MutMaxDepth      uint   // Maximum depth of the subtrees generated by MutSubtree
+	MaxDepth         uint   // Maximum depth of a GPTree, 0 means no limit
+	MaxSize          uint   // Maximum number of nodes of a GPTree, 0 means no limit
+	SubtreeMutWeight float64
+	PointMutWeight   float64
+	HoistMutWeight   float64
+	Fitness          func(t *GPTree) (float64, error)
+}
+
+// NewTree generates a GPTree with ramped half-and-half initialization. The
+// depth is sampled uniformly between InitMinDepth and InitMaxDepth and the
+// tree is generated either with the full method or the grow method with equal
+// probability. NewTree panics if the GP is invalid, Validate can be called
+// beforehand to get the error instead.
+func (gp *GP) NewTree(rng *rand.Rand) Genome {
+	if err := gp.Validate(); err != nil {
+		panic(fmt.Sprintf("eaopt: invalid GP: %v", err))
+	}
+	var depth = gp.InitMinDepth + uint(rng.Intn(int(gp.InitMaxDepth-gp.InitMinDepth)+1))
+	return &GPTree{
+		Root: gp.Set.grow(gp.Out, 0, depth, rng.Float64() < 0.5, rng),
+		GP:   gp,
+	}
+}
+
+// Validate GP fields.
+func (gp *GP) Validate() error {
+	if err := gp.Set.Validate(); err != nil {
+		return err
+	}
+	if gp.Set.nLeavesOf(gp.Out) == 0 && len(gp.Set.primitivesOf(gp.Out)) == 0 {
+		return fmt.Errorf("no primitive or leaf returns type %s", gp.Out)
+	}
+	if gp.InitMinDepth > gp.InitMaxDepth {
+		return errors.New("InitMinDepth should be lower than InitMaxDepth")
+	}
+	if gp.MaxDepth > 0 && gp.InitMaxDepth > gp.MaxDepth {
+		return errors.New("InitMaxDepth should be lower than MaxDepth")
+	}
+	if gp.SubtreeMutWeight < 0 || gp.PointMutWeight < 0 || gp.HoistMutWeight < 0 {
+		return errors.New("mutation weights should be positive")
+	}
+	if gp.Fitness == nil {
+		return errors.New("fitness function has to be provided")
+	}
+	return nil
+}
+
+// NewGPArithmeticSet returns a GPSet of type "float64" for symbolic
+// regression. It contains addition, subtraction, multiplication, protected
+// division (which returns 1 when dividing by 0), nVars input variables named
+// x0, x1, ... and an ephemeral constant sampled uniformly in [-1, 1]. The
+// input variables are expected to be float64s.
+func NewGPArithmeticSet(nVars int) GPSet {
+	var (
+		in  = []string{"float64", "float64"}
+		set = GPSet{}
+		bin = func(name string, f func(a, b float64) float64) GPPrimitive {
+			return GPPrimitive{
+				Name: name,
+				In:   in,
+				Out:  "float64",
+				Fn: func(args []interface{}) interface{} {
+					return f(args[0].(float64), args[1].(float64))
+				},
+			}
+		}
+	)
+	set.Primitives = []GPPrimitive{
+		bin("add", func(a, b float64) float64 { return a + b }),
+		bin("sub", func(a, b float64) float64 { return a - b }),
+		bin("mul", func(a, b float64) float64 { return a * b }),
+		bin("div", func(a, b float64) float64 {
+			if b == 0 || math.IsNaN(b) {
+				return 1
+			}
+			return a / b
+		}),
+	}
+	for i := 0; i < nVars; i++ {
+		var i = i // https://golang.org/doc/faq#closures_and_goroutines
+		set.Terminals = append(set.Terminals, GPTerminal{
+			Name: fmt.Sprintf("x%d", i),
+			Out:  "float64",
+			Fn:   func(vars []interface{}) interface{} { return vars[i] },
+		})
+	}
+	set.Ephemerals = []GPEphemeral{{
+		Name: "const",
+		Out:  "float64",
+		Gen:  func(rng *rand.Rand) interface{} { return 2*rng.Float64() - 1 },
+	}}
+	return set
+}
@@ -0,0 +1,260 @@
+package eaopt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func newTestGP() *GP {
+	var (
+		xs = []float64{-2, -1, -0.5, 0, 0.5, 1, 2}
+		gp = &GP{
+			Set:              NewGPArithmeticSet(1),
+			Out:              "float64",
+			InitMinDepth:     1,
+			InitMaxDepth:     3,
+			MutMaxDepth:      2,
+			MaxDepth:         6,
+			MaxSize:          40,
+			SubtreeMutWeight: 1,
+			PointMutWeight:   1,
+			HoistMutWeight:   1,
+		}
+	)
+	// Symbolic regression of x² + x
+	gp.Fitness = func(t *GPTree) (float64, error) {
+		var mse float64
+		for _, x := range xs {
+			var y = t.Eval(x).(float64)
+			mse += math.Pow(y-(x*x+x), 2)
+		}
+		return mse / float64(len(xs)), nil
+	}
+	return gp
+}
+
+func TestGPTreeEval(t *testing.T) {
+	var (
+		gp  = newTestGP()
+		set = &gp.Set
+		// add(x0, mul(x0, 0.5))
+		tree = &GPTree{
+			Root: &GPNode{
+				Primitive: &set.Primitives[0],
+				Children: []*GPNode{
+					{Terminal: &set.Terminals[0]},
+					{
+						Primitive: &set.Primitives[2],
+						Children: []*GPNode{
+							{Terminal: &set.Terminals[0]},
+							{Ephemeral: &set.Ephemerals[0], Value: 0.5},
+						},
+					},
+				},
+			},
+			GP: gp,
+		}
+	)
+	if y := tree.Eval(2.0).(float64); y != 3 {
+		t.Errorf("Expected 3, got %f", y)
+	}
+	if s := tree.String(); s != "add(x0, mul(x0, 0.5))" {
+		t.Errorf("Expected add(x0, mul(x0, 0.5)), got %s", s)
+	}
+	if tree.Size() != 5 {
+		t.Errorf("Expected 5, got %d", tree.Size())
+	}
+	if tree.Depth() != 2 {
+		t.Errorf("Expected 2, got %d", tree.Depth())
+	}
+}
+
+func TestGPProtectedDivision(t *testing.T) {
+	var div = NewGPArithmeticSet(0).Primitives[3]
+	if y := div.Fn([]interface{}{3.0, 0.0}).(float64); y != 1 {
+		t.Errorf("Expected 1, got %f", y)
+	}
+}
+
+func TestGPNewTreeDepth(t *testing.T) {
+	var (
+		gp  = newTestGP()
+		rng = newRand()
+	)
+	for i := 0; i < 100; i++ {
+		var tree = gp.NewTree(rng).(*GPTree)
+		if tree.Depth() > int(gp.InitMaxDepth) {
+			t.Errorf("Expected depth at most %d, got %d", gp.InitMaxDepth, tree.Depth())
+		}
+	}
+}
+
+func TestGPOperatorsRespectLimits(t *testing.T) {
+	var (
+		gp  = newTestGP()
+		rng = newRand()
+	)
+	for i := 0; i < 200; i++ {
+		var (
+			t1 = gp.NewTree(rng).(*GPTree)
+			t2 = gp.NewTree(rng).(*GPTree)
+		)
+		t1.Mutate(rng)
+		t1.Crossover(t2, rng)
+		for _, tree := range []*GPTree{t1, t2} {
+			if tree.Depth() > int(gp.MaxDepth) || tree.Size() > int(gp.MaxSize) {
+				t.Errorf("Tree exceeds limits: depth %d, size %d", tree.Depth(), tree.Size())
+			}
+			if tree.Root.Type() != gp.Out {
+				t.Errorf("Expected type %s, got %s", gp.Out, tree.Root.Type())
+			}
+		}
+	}
+}
+
+func TestGPMutations(t *testing.T) {
+	var (
+		gp        = newTestGP()
+		rng       = newRand()
+		mutations = []func(t *GPTree, rng *rand.Rand){
+			(*GPTree).MutSubtree,
+			(*GPTree).MutPoint,
+			(*GPTree).MutHoist,
+		}
+	)
+	for i, mutate := range mutations {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			for j := 0; j < 50; j++ {
+				var (
+					tree  = gp.NewTree(rng).(*GPTree)
+					size  = tree.Size()
+					clone = tree.Clone().(*GPTree)
+				)
+				mutate(tree, rng)
+				if clone.Size() != size {
+					t.Error("Mutating a tree should not modify it's clone")
+				}
+				if i == 1 && tree.Size() != size {
+					t.Errorf("Point mutation should not change the size, got %d and %d", size, tree.Size())
+				}
+				if i == 2 && tree.Size() > size {
+					t.Errorf("Hoist mutation should not increase the size, got %d and %d", size, tree.Size())
+				}
+			}
+		})
+	}
+}
+
+func TestGPCrossSubtreeTyped(t *testing.T) {
+	var (
+		gp = &GP{
+			Set: GPSet{
+				Primitives: []GPPrimitive{{
+					Name: "if",
+					In:   []string{"bool", "float64", "float64"},
+					Out:  "float64",
+					Fn: func(args []interface{}) interface{} {
+						if args[0].(bool) {
+							return args[1]
+						}
+						return args[2]
+					},
+				}},
+				Terminals: []GPTerminal{
+					{Name: "x", Out: "float64", Fn: func(vars []interface{}) interface{} { return vars[0] }},
+					{Name: "true", Out: "bool", Fn: func(vars []interface{}) interface{} { return true }},
+				},
+			},
+			Out:          "float64",
+			InitMinDepth: 1,
+			InitMaxDepth: 3,
+			MutMaxDepth:  2,
+			Fitness:      func(t *GPTree) (float64, error) { return 0, nil },
+		}
+		rng = newRand()
+	)
+	for i := 0; i < 100; i++ {
+		var t1, t2 = gp.NewTree(rng).(*GPTree), gp.NewTree(rng).(*GPTree)
+		t1.CrossSubtree(t2, rng)
+		t1.MutSubtree(rng)
+		t2.MutPoint(rng)
+		for _, tree := range []*GPTree{t1, t2} {
+			if _, ok := tree.Eval(1.0).(float64); !ok {
+				t.Errorf("Tree %s does not return a float64", tree)
+			}
+		}
+	}
+}
+
+func TestGPValidate(t *testing.T) {
+	var valid = newTestGP()
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	var testCases = []func(gp *GP){
+		func(gp *GP) { gp.Set = GPSet{} },
+		func(gp *GP) { gp.Out = "bool" },
+		func(gp *GP) { gp.InitMinDepth = 4 },
+		func(gp *GP) { gp.MaxDepth = 2 },
+		func(gp *GP) { gp.PointMutWeight = -1 },
+		func(gp *GP) { gp.Fitness = nil },
+		func(gp *GP) { gp.Set.Primitives[0].In = []string{"bool"} },
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var gp = newTestGP()
+			tc(gp)
+			if gp.Validate() == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestGPMinimize(t *testing.T) {
+	var (
+		gp   = newTestGP()
+		conf = NewDefaultGAConfig()
+	)
+	conf.PopSize = 100
+	conf.NGenerations = 30
+	conf.RNG = rand.New(rand.NewSource(42))
+	conf.Model = ModGenerational{
+		Selector:  SelDoubleTournament{NContestants: 5, ParsimonyPressure: 1.4},
+		MutRate:   0.3,
+		CrossRate: 0.8,
+	}
+	var ga, err = conf.NewGA()
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if err = ga.Minimize(gp.NewTree); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if f := ga.HallOfFame[0].Fitness; f > 0.1 {
+		t.Errorf("Expected a fitness below 0.1, got %f", f)
+	}
+}
+
+func TestGPNewTreeInvalid(t *testing.T) {
+	var gp = newTestGP()
+	gp.InitMinDepth, gp.InitMaxDepth = 4, 2
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic")
+		}
+	}()
+	gp.NewTree(newRand())
+}
+
+func TestGPFitnessError(t *testing.T) {
+	var gp = newTestGP()
+	gp.Fitness = func(t *GPTree) (float64, error) { return 0, errors.New("") }
+	var ga, _ = NewDefaultGAConfig().NewGA()
+	if err := ga.Minimize(gp.NewTree); err == nil {
+		t.Error("Expected error")
+	}
+}
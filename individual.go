@@ -8,10 +8,12 @@ import (
 
 // An Individual wraps a Genome and contains the fitness assigned to the Genome.
 type Individual struct {
-	Genome    Genome  `json:"genome"`
-	Fitness   float64 `json:"fitness"`
-	Evaluated bool    `json:"-"`
-	ID        string  `json:"id"`
+	Genome    Genome    `json:"genome"`
+	Fitness   float64   `json:"fitness"`
+	Errors    []float64 `json:"errors,omitempty"` // Per-case errors, set if the Genome is a CaseEvaluator
+	Evaluated bool      `json:"-"`
+	ID        string    `json:"id"`
+	raw       float64   // Fitness before niching, see applyNiched
 }
 
 // NewIndividual returns a fresh individual.
@@ -40,6 +42,10 @@ func (indi Individual) Clone(rng *rand.Rand) Individual {
 		Fitness:   indi.Fitness,
 		Evaluated: indi.Evaluated,
 		ID:        randString(6, rng),
+		raw:       indi.raw,
+	}
+	if indi.Errors != nil {
+		clone.Errors = append([]float64(nil), indi.Errors...)
 	}
 	if indi.Genome == nil {
 		clone.Genome = nil
@@ -50,11 +56,25 @@ func (indi Individual) Clone(rng *rand.Rand) Individual {
 }
 
 // Evaluate the fitness of an individual. Don't evaluate individuals that have
-// already been evaluated.
+// already been evaluated. If the Genome is a CaseEvaluator then the per-case
+// errors are stored in the Errors field and the fitness is their sum.
 func (indi *Individual) Evaluate() error {
 	if indi.Evaluated {
 		return nil
 	}
+	if ce, ok := unwrapped(indi.Genome).(CaseEvaluator); ok {
+		var errs, err = ce.EvaluateCases()
+		if err != nil {
+			return err
+		}
+		if errs != nil {
+			indi.Errors = errs
+			indi.Fitness = sumFloat64s(errs)
+			indi.Evaluated = true
+			return nil
+		}
+	}
+	indi.Errors = nil
 	var fitness, err = indi.Genome.Evaluate()
 	if err != nil {
 		return err
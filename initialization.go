@@ -20,6 +20,15 @@ func InitJaggFloat64(n uint, lower, upper []float64, rng *rand.Rand) (floats []f
 	return
 }
 
+// InitJaggInt generates random ints x such that lower[i] <= x <= upper[i].
+func InitJaggInt(n uint, lower, upper []int, rng *rand.Rand) (ints []int) {
+	ints = make([]int, n)
+	for i := range ints {
+		ints[i] = lower[i] + rng.Intn(upper[i]-lower[i]+1)
+	}
+	return
+}
+
 // InitNormFloat64 generates random float64s sampled from a normal distribution.
 func InitNormFloat64(n uint, mean, std float64, rng *rand.Rand) (floats []float64) {
 	floats = make([]float64, n)
@@ -86,6 +86,31 @@ func TestInitJaggFloat64(t *testing.T) {
 	}
 }
 
+func TestInitJaggInt(t *testing.T) {
+	var (
+		rng   = newRand()
+		lower = []int{0, -3, 5}
+		upper = []int{1, 3, 5}
+		seen  = make(map[int]bool)
+	)
+	for i := 0; i < 100; i++ {
+		var ints = InitJaggInt(3, lower, upper, rng)
+		if len(ints) != 3 {
+			t.Fatal("InitJaggInt didn't produce the right number of values")
+		}
+		for j, v := range ints {
+			if v < lower[j] || v > upper[j] {
+				t.Error("InitJaggInt produced out of bound values")
+			}
+		}
+		seen[ints[1]] = true
+	}
+	// Both bounds are inclusive
+	if !seen[-3] || !seen[3] {
+		t.Error("InitJaggInt should be able to reach the bounds")
+	}
+}
+
 func TestInitNormFloat64(t *testing.T) {
 	var rng = newRand()
 	for _, n := range []uint{0, 1, 2, 42} {
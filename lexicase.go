@@ -0,0 +1,194 @@
+package eaopt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// A CaseEvaluator is a Genome that is evaluated on a set of test cases and
+// that can return the error it makes on each case. When a Genome implements
+// CaseEvaluator, Individual.Evaluate calls EvaluateCases instead of Evaluate,
+// stores the errors in the Individual's Errors field and uses their sum as the
+// fitness. The Evaluate method should thus return the same sum, it is still
+// used by the parts of the library that work on Genomes directly. Returning a
+// nil slice means the per-case errors are not available, in which case
+// Evaluate is called instead. The errors of every Genome have to be given in
+// the same order and lower errors are better.
+type CaseEvaluator interface {
+	EvaluateCases() ([]float64, error)
+}
+
+// caseErrors evaluates individuals and returns their per-case errors.
+func caseErrors(indis Individuals) ([][]float64, error) {
+	if len(indis) == 0 {
+		return nil, errors.New("cannot select from an empty group of individuals")
+	}
+	var errs = make([][]float64, len(indis))
+	for i := range indis {
+		if err := indis[i].Evaluate(); err != nil {
+			return nil, err
+		}
+		if indis[i].Errors == nil {
+			return nil, errors.New("lexicase selection requires per-case errors, " +
+				"the Genomes have to implement CaseEvaluator")
+		}
+		if len(indis[i].Errors) != len(indis[0].Errors) {
+			return nil, fmt.Errorf("individuals have different numbers of cases: %d and %d",
+				len(indis[0].Errors), len(indis[i].Errors))
+		}
+		errs[i] = indis[i].Errors
+	}
+	return errs, nil
+}
+
+// madEpsilons returns the median absolute deviation of the errors made on each
+// case.
+func madEpsilons(errs [][]float64, cases []int) []float64 {
+	var (
+		eps    = make([]float64, len(errs[0]))
+		column = make([]float64, len(errs))
+	)
+	for _, c := range cases {
+		for i := range errs {
+			column[i] = errs[i][c]
+		}
+		eps[c] = madFloat64s(column)
+	}
+	return eps
+}
+
+// lexicase selects n individuals through lexicase selection on the given
+// cases. Each selection event goes through the cases in a random order and
+// only keeps the candidates whose error is within eps of the best candidate's
+// error. eps can be nil, in which case only the best candidates are kept.
+func lexicase(n uint, indis Individuals, errs [][]float64, cases []int, eps []float64,
+	rng *rand.Rand) (Individuals, []int) {
+	var (
+		selected   = make(Individuals, n)
+		indexes    = make([]int, n)
+		candidates = make([]int, 0, len(indis))
+		order      = make([]int, len(cases))
+	)
+	for i := range selected {
+		candidates = candidates[:0]
+		for j := range indis {
+			candidates = append(candidates, j)
+		}
+		copy(order, cases)
+		rng.Shuffle(len(order), func(a, b int) { order[a], order[b] = order[b], order[a] })
+		for _, c := range order {
+			if len(candidates) == 1 {
+				break
+			}
+			var best = math.Inf(1)
+			for _, j := range candidates {
+				best = math.Min(best, errs[j][c])
+			}
+			var threshold = best
+			if eps != nil {
+				threshold += eps[c]
+			}
+			var kept = candidates[:0]
+			for _, j := range candidates {
+				if errs[j][c] <= threshold {
+					kept = append(kept, j)
+				}
+			}
+			candidates = kept
+		}
+		indexes[i] = candidates[rng.Intn(len(candidates))]
+		selected[i] = indis[indexes[i]]
+	}
+	return selected.Clone(rng), indexes
+}
+
+// SelLexicase samples individuals through lexicase selection. Each individual
+// is selected by going through the test cases in a random order and only
+// keeping the individuals that have the lowest error on each case, until a
+// single individual remains or all the cases have been used. Contrary to
+// fitness-based selectors, this favors individuals that solve cases that the
+// rest of the population fails on. The Genomes have to implement
+// CaseEvaluator. Individuals may be selected more than once.
+// Reference: https://doi.org/10.1109/TEVC.2014.2362729
+type SelLexicase struct{}
+
+// Apply SelLexicase.
+func (sel SelLexicase) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	var errs, err = caseErrors(indis)
+	if err != nil {
+		return nil, nil, err
+	}
+	var selected, indexes = lexicase(n, indis, errs, newInts(uint(len(errs[0]))), nil, rng)
+	return selected, indexes, nil
+}
+
+// Validate SelLexicase fields.
+func (sel SelLexicase) Validate() error {
+	return nil
+}
+
+// SelEpsilonLexicase is a variant of SelLexicase for continuous errors, such
+// as the ones of symbolic regression, where exact ties are rare. The
+// individuals whose error on a case is within epsilon of the lowest error are
+// kept, epsilon being the median absolute deviation of the errors of the
+// population on that case.
+// Reference: https://doi.org/10.1145/2908812.2908898
+type SelEpsilonLexicase struct{}
+
+// Apply SelEpsilonLexicase.
+func (sel SelEpsilonLexicase) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	var errs, err = caseErrors(indis)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		cases             = newInts(uint(len(errs[0])))
+		selected, indexes = lexicase(n, indis, errs, cases, madEpsilons(errs, cases), rng)
+	)
+	return selected, indexes, nil
+}
+
+// Validate SelEpsilonLexicase fields.
+func (sel SelEpsilonLexicase) Validate() error {
+	return nil
+}
+
+// SelDownsampledLexicase applies lexicase selection on a random subset of the
+// test cases, the subset being drawn each time the selector is applied and
+// containing a proportion Rate of the cases. Using fewer cases makes
+// selection cheaper and tends to improve generalization. If Epsilon is true
+// then epsilon-lexicase selection is used on the subset.
+// Reference: https://doi.org/10.1162/artl_a_00341
+type SelDownsampledLexicase struct {
+	Rate    float64
+	Epsilon bool
+}
+
+// Apply SelDownsampledLexicase.
+func (sel SelDownsampledLexicase) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	var errs, err = caseErrors(indis)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		nCases = len(errs[0])
+		k      = maxInt(int(math.Round(sel.Rate*float64(nCases))), 1)
+		cases  = randomInts(uint(minInt(k, nCases)), 0, nCases, rng)
+		eps    []float64
+	)
+	if sel.Epsilon {
+		eps = madEpsilons(errs, cases)
+	}
+	var selected, indexes = lexicase(n, indis, errs, cases, eps, rng)
+	return selected, indexes, nil
+}
+
+// Validate SelDownsampledLexicase fields.
+func (sel SelDownsampledLexicase) Validate() error {
+	if sel.Rate <= 0 || sel.Rate > 1 {
+		return errors.New("Rate should be in (0, 1]")
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package eaopt
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// caseGenome is a Genome whose per-case errors are fixed.
+type caseGenome []float64
+
+func (g caseGenome) Evaluate() (float64, error)         { return sumFloat64s(g), nil }
+func (g caseGenome) EvaluateCases() ([]float64, error)  { return g, nil }
+func (g caseGenome) Mutate(rng *rand.Rand)              {}
+func (g caseGenome) Crossover(h Genome, rng *rand.Rand) {}
+func (g caseGenome) Clone() Genome                      { return append(caseGenome(nil), g...) }
+
+type errorCaseGenome struct{ caseGenome }
+
+func (g errorCaseGenome) EvaluateCases() ([]float64, error) { return nil, errors.New("") }
+
+func newCaseIndividuals(errs ...[]float64) Individuals {
+	var indis = make(Individuals, len(errs))
+	for i, e := range errs {
+		indis[i] = NewIndividual(caseGenome(e), newRand())
+	}
+	return indis
+}
+
+func TestIndividualEvaluateCases(t *testing.T) {
+	var indi = NewIndividual(caseGenome{1, 2, 3}, newRand())
+	if err := indi.Evaluate(); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if indi.Fitness != 6 || len(indi.Errors) != 3 {
+		t.Errorf("Unexpected individual %v with errors %v", indi, indi.Errors)
+	}
+	var clone = indi.Clone(newRand())
+	if len(clone.Errors) != 3 || &clone.Errors[0] == &indi.Errors[0] {
+		t.Error("The errors should be copied")
+	}
+	indi = NewIndividual(errorCaseGenome{}, newRand())
+	if err := indi.Evaluate(); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestSelLexicase(t *testing.T) {
+	// The first individual has the best fitness but each of the other two
+	// individuals is the only one to solve a case, whereas the last
+	// individual isn't the best on any case
+	var indis = newCaseIndividuals(
+		[]float64{1, 1, 1},
+		[]float64{0, 5, 5},
+		[]float64{5, 0, 5},
+		[]float64{5, 5, 2},
+	)
+	var counts = selectionCounts(t, SelLexicase{}, 300, indis)
+	if counts[1] < 70 || counts[2] < 70 || counts[3] != 0 {
+		t.Errorf("Specialists should be selected, got %v", counts)
+	}
+}
+
+func TestSelEpsilonLexicase(t *testing.T) {
+	var indis = newCaseIndividuals(
+		[]float64{0.01, 5, 0},
+		[]float64{0, 5, 0.01},
+		[]float64{3, 0, 3},
+		[]float64{4, 1, 4},
+	)
+	// The first two individuals are within epsilon of each other on the first
+	// and last cases, hence both of them are selected, and so is the third
+	// individual because it is the only one to solve the second case
+	var counts = selectionCounts(t, SelEpsilonLexicase{}, 400, indis)
+	if counts[0] < 50 || counts[1] < 50 || counts[2] < 50 {
+		t.Errorf("Unexpected counts %v", counts)
+	}
+	var eps = madEpsilons([][]float64{{0}, {1}, {3}, {10}}, []int{0})
+	if eps[0] != 1.5 {
+		t.Errorf("Expected 1.5, got %f", eps[0])
+	}
+}
+
+func TestSelDownsampledLexicase(t *testing.T) {
+	var indis = newCaseIndividuals(
+		[]float64{0, 1, 1, 1},
+		[]float64{1, 0, 1, 1},
+		[]float64{1, 1, 0, 1},
+		[]float64{1, 1, 1, 0},
+	)
+	// With a single case per application, every selected individual is the
+	// one that solves the sampled case
+	var (
+		sel = SelDownsampledLexicase{Rate: 0.25}
+		rng = rand.New(rand.NewSource(42))
+	)
+	for i := 0; i < 20; i++ {
+		var _, indexes, err = sel.Apply(10, indis, rng)
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		for _, idx := range indexes {
+			if idx != indexes[0] {
+				t.Errorf("Expected a single individual to be selected, got %v", indexes)
+				break
+			}
+		}
+	}
+	sel = SelDownsampledLexicase{Rate: 1, Epsilon: true}
+	if counts := selectionCounts(t, sel, 400, indis); counts[0] == 0 || counts[3] == 0 {
+		t.Errorf("Unexpected counts %v", counts)
+	}
+}
+
+func TestLexicaseErrors(t *testing.T) {
+	var selectors = []Selector{SelLexicase{}, SelEpsilonLexicase{}, SelDownsampledLexicase{Rate: 0.5}}
+	var groups = []Individuals{
+		{},
+		newIndividuals(3, false, NewVector, newRand()),
+		newCaseIndividuals([]float64{1, 2}, []float64{1}),
+		{NewIndividual(errorCaseGenome{}, newRand())},
+	}
+	for _, sel := range selectors {
+		for i, indis := range groups {
+			if _, _, err := sel.Apply(1, indis, newRand()); err == nil {
+				t.Errorf("Test %d: expected error", i)
+			}
+		}
+	}
+	if (SelDownsampledLexicase{}).Validate() == nil || (SelDownsampledLexicase{Rate: 2}).Validate() == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestGALexicase(t *testing.T) {
+	var ga, err = NewGAOf[typedCases](GAConfig{
+		NPops:        1,
+		PopSize:      20,
+		NGenerations: 20,
+		HofSize:      1,
+		Model:        ModGenerational{Selector: SelEpsilonLexicase{}, MutRate: 0.5},
+		RNG:          newRand(),
+	})
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if err = ga.Minimize(func(rng *rand.Rand) typedCases { return InitUnifFloat64(4, -10, 10, rng) }); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if indi := ga.Best(); len(indi.Errors) != 4 || indi.Fitness > 10 {
+		t.Errorf("Unexpected best individual %v", indi)
+	}
+}
+
+// typedCases is a GenomeOf whose case errors are the squares of the genes.
+type typedCases []float64
+
+func (v typedCases) Evaluate() (float64, error) {
+	var errs, _ = v.EvaluateCases()
+	return sumFloat64s(errs), nil
+}
+func (v typedCases) EvaluateCases() ([]float64, error) {
+	var errs = make([]float64, len(v))
+	for i, x := range v {
+		errs[i] = x * x
+	}
+	return errs, nil
+}
+func (v typedCases) Mutate(rng *rand.Rand)                  { MutNormalFloat64(v, 0.5, rng) }
+func (v typedCases) Crossover(w typedCases, rng *rand.Rand) { CrossUniformFloat64(v, w, rng) }
+func (v typedCases) Clone() typedCases                      { return append(typedCases(nil), v...) }
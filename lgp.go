@@ -0,0 +1,434 @@
+package eaopt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// An LGPOp is an operation of the register machine used for linear genetic
+// programming.
+type LGPOp int
+
+// Operations of the register machine. Arithmetic operations store the result
+// of Src1 op Src2 in Dst; division is protected and returns Src1 when Src2 is
+// 0. Conditional operations skip the next instruction if the comparison
+// between Src1 and Src2 is false. LGPLoad copies the memory cell addressed by
+// Src1 into Dst and LGPStore copies Src2 into the memory cell addressed by
+// Src1.
+const (
+	LGPAdd LGPOp = iota
+	LGPSub
+	LGPMul
+	LGPDiv
+	LGPIfGreater
+	LGPIfLessEq
+	LGPLoad
+	LGPStore
+)
+
+var lgpOpNames = []string{"add", "sub", "mul", "div", "ifgt", "ifle", "load", "store"}
+
+func (op LGPOp) String() string {
+	if op < 0 || int(op) >= len(lgpOpNames) {
+		return fmt.Sprintf("op%d", int(op))
+	}
+	return lgpOpNames[op]
+}
+
+// isBranch indicates if an operation is a conditional.
+func (op LGPOp) isBranch() bool {
+	return op == LGPIfGreater || op == LGPIfLessEq
+}
+
+// writes indicates if an operation writes to it's destination register.
+func (op LGPOp) writes() bool {
+	return !op.isBranch() && op != LGPStore
+}
+
+// An LGPInstruction is an instruction of the register machine. Dst is the index
+// of a calculation register. Src1 and Src2 index the concatenation of the
+// calculation registers, the input registers and the constants.
+type LGPInstruction struct {
+	Op   LGPOp
+	Dst  int
+	Src1 int
+	Src2 int
+}
+
+// An LGPProgram is a Genome that represents a variable-length sequence of
+// instructions for a register machine.
+type LGPProgram struct {
+	Instructions []LGPInstruction
+	LGP          *LGP
+
+	effective []LGPInstruction // Cached result of Effective, nil if it has to be recomputed
+}
+
+// String representation of an LGPProgram, with one instruction per line.
+func (p *LGPProgram) String() string {
+	var lines = make([]string, len(p.Instructions))
+	for i, ins := range p.Instructions {
+		lines[i] = fmt.Sprintf("%s r%d r%d r%d", ins.Op, ins.Dst, ins.Src1, ins.Src2)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// reads returns the calculation registers that are read by an instruction.
+func (lgp *LGP) reads(ins LGPInstruction) []int {
+	var regs []int
+	for _, src := range []int{ins.Src1, ins.Src2} {
+		if src < int(lgp.NRegisters) {
+			regs = append(regs, src)
+		}
+	}
+	// LGPLoad only reads the address in Src1
+	if ins.Op == LGPLoad {
+		if ins.Src1 < int(lgp.NRegisters) {
+			return []int{ins.Src1}
+		}
+		return nil
+	}
+	return regs
+}
+
+// EffectiveIdxs returns the indexes of the instructions that can influence the
+// output register, in increasing order. The other instructions are structural
+// introns and can be removed without changing the output of the program.
+// Memory is handled conservatively: a store is deemed effective as soon as an
+// effective load follows it.
+func (p *LGPProgram) EffectiveIdxs() []int {
+	var (
+		lgp          = p.LGP
+		regs         = map[int]bool{int(lgp.Output): true}
+		memEffective bool
+		nextEff      bool // Whether the next instruction is effective
+		idxs         []int
+	)
+	for i := len(p.Instructions) - 1; i >= 0; i-- {
+		var (
+			ins       = p.Instructions[i]
+			effective bool
+		)
+		switch {
+		case ins.Op.isBranch():
+			effective = nextEff
+		case ins.Op == LGPStore:
+			effective = memEffective
+		default:
+			effective = regs[ins.Dst]
+		}
+		if effective {
+			// A conditional write might not happen, hence the destination
+			// register stays effective if the previous instruction is a
+			// branch
+			if ins.Op.writes() && (i == 0 || !p.Instructions[i-1].Op.isBranch()) {
+				delete(regs, ins.Dst)
+			}
+			for _, r := range lgp.reads(ins) {
+				regs[r] = true
+			}
+			if ins.Op == LGPLoad {
+				memEffective = true
+			}
+			idxs = append(idxs, i)
+		}
+		nextEff = effective
+	}
+	// Reverse the indexes
+	for i, j := 0, len(idxs)-1; i < j; i, j = i+1, j-1 {
+		idxs[i], idxs[j] = idxs[j], idxs[i]
+	}
+	return idxs
+}
+
+// Effective returns the instructions of an LGPProgram without it's structural
+// introns. The result is cached until the program is modified.
+func (p *LGPProgram) Effective() []LGPInstruction {
+	if p.effective == nil {
+		var idxs = p.EffectiveIdxs()
+		p.effective = make([]LGPInstruction, len(idxs))
+		for i, idx := range idxs {
+			p.effective[i] = p.Instructions[idx]
+		}
+	}
+	return p.effective
+}
+
+// RemoveIntrons removes the structural introns of an LGPProgram.
+func (p *LGPProgram) RemoveIntrons() {
+	p.Instructions = append([]LGPInstruction(nil), p.Effective()...)
+}
+
+// Run an LGPProgram on a set of inputs and return the value of the output
+// register. Only the effective instructions are executed. The calculation
+// registers are initialized with the inputs, cycling through them if there
+// are less inputs than registers. An error is returned if there are less than
+// NInputs inputs.
+func (p *LGPProgram) Run(inputs []float64) (float64, error) {
+	if len(inputs) < int(p.LGP.NInputs) {
+		return 0, fmt.Errorf("expected %d inputs, got %d", p.LGP.NInputs, len(inputs))
+	}
+	return p.LGP.exec(p.Effective(), inputs), nil
+}
+
+// exec runs a sequence of instructions and returns the output register.
+func (lgp *LGP) exec(instructions []LGPInstruction, inputs []float64) float64 {
+	var (
+		nReg = int(lgp.NRegisters)
+		nIn  = int(lgp.NInputs)
+		regs = make([]float64, nReg)
+		mem  = make([]float64, lgp.NMemory)
+		read = func(src int) float64 {
+			switch {
+			case src < nReg:
+				return regs[src]
+			case src < nReg+nIn:
+				return inputs[src-nReg]
+			}
+			return lgp.Constants[src-nReg-nIn]
+		}
+		addr = func(v float64) int {
+			if len(mem) == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+				return -1
+			}
+			return int(math.Mod(math.Abs(v), float64(len(mem))))
+		}
+	)
+	if len(inputs) > 0 {
+		for i := range regs {
+			regs[i] = inputs[i%len(inputs)]
+		}
+	}
+	for i := 0; i < len(instructions); i++ {
+		var (
+			ins  = instructions[i]
+			a, b = read(ins.Src1), read(ins.Src2)
+		)
+		switch ins.Op {
+		case LGPAdd:
+			regs[ins.Dst] = a + b
+		case LGPSub:
+			regs[ins.Dst] = a - b
+		case LGPMul:
+			regs[ins.Dst] = a * b
+		case LGPDiv:
+			if b == 0 {
+				regs[ins.Dst] = a
+			} else {
+				regs[ins.Dst] = a / b
+			}
+		case LGPIfGreater:
+			if !(a > b) {
+				i++
+			}
+		case LGPIfLessEq:
+			if !(a <= b) {
+				i++
+			}
+		case LGPLoad:
+			if k := addr(a); k >= 0 {
+				regs[ins.Dst] = mem[k]
+			}
+		case LGPStore:
+			if k := addr(a); k >= 0 {
+				mem[k] = b
+			}
+		}
+	}
+	return regs[lgp.Output]
+}
+
+// Evaluate an LGPProgram with the LGP's Fitness function.
+func (p *LGPProgram) Evaluate() (float64, error) {
+	return p.LGP.Fitness(p)
+}
+
+// Mutate an LGPProgram by applying macro mutation with probability
+// LGP.MacroMutRate and micro mutation otherwise.
+func (p *LGPProgram) Mutate(rng *rand.Rand) {
+	if rng.Float64() < p.LGP.MacroMutRate {
+		p.MutMacro(rng)
+	} else {
+		p.MutMicro(rng)
+	}
+}
+
+// MutMacro either inserts a random instruction at a random position or
+// deletes a random instruction, with equal probability. The program length is
+// kept between LGP.MinLen and LGP.MaxLen.
+func (p *LGPProgram) MutMacro(rng *rand.Rand) {
+	var (
+		n      = len(p.Instructions)
+		insert = rng.Float64() < 0.5
+	)
+	if n <= int(p.LGP.MinLen) {
+		insert = true
+	}
+	if n >= int(p.LGP.MaxLen) {
+		insert = false
+	}
+	if insert {
+		var i = rng.Intn(n + 1)
+		p.Instructions = append(p.Instructions, LGPInstruction{})
+		copy(p.Instructions[i+1:], p.Instructions[i:])
+		p.Instructions[i] = p.LGP.newInstruction(rng)
+	} else if n > int(p.LGP.MinLen) {
+		var i = rng.Intn(n)
+		p.Instructions = append(p.Instructions[:i], p.Instructions[i+1:]...)
+	}
+	p.effective = nil
+}
+
+// MutMicro modifies either the operation, the destination register or one of
+// the operands of a random instruction.
+func (p *LGPProgram) MutMicro(rng *rand.Rand) {
+	if len(p.Instructions) == 0 {
+		return
+	}
+	var (
+		lgp = p.LGP
+		ins = &p.Instructions[rng.Intn(len(p.Instructions))]
+	)
+	switch rng.Intn(4) {
+	case 0:
+		ins.Op = lgp.Ops[rng.Intn(len(lgp.Ops))]
+	case 1:
+		ins.Dst = rng.Intn(int(lgp.NRegisters))
+	case 2:
+		ins.Src1 = rng.Intn(lgp.nOperands())
+	default:
+		ins.Src2 = rng.Intn(lgp.nOperands())
+	}
+	p.effective = nil
+}
+
+// Crossover an LGPProgram with another LGPProgram by using two-point crossover
+// for variable-length genomes. A segment is chosen in each parent and the
+// segments are swapped. The crossover is not applied if either offspring
+// would violate the LGP's length bounds.
+func (p *LGPProgram) Crossover(q Genome, rng *rand.Rand) {
+	var (
+		p1 = p.Instructions
+		p2 = q.(*LGPProgram).Instructions
+	)
+	if len(p1) == 0 || len(p2) == 0 {
+		return
+	}
+	var (
+		a1 = rng.Intn(len(p1))
+		b1 = a1 + 1 + rng.Intn(len(p1)-a1)
+		a2 = rng.Intn(len(p2))
+		b2 = a2 + 1 + rng.Intn(len(p2)-a2)
+		n1 = len(p1) - (b1 - a1) + (b2 - a2)
+		n2 = len(p2) - (b2 - a2) + (b1 - a1)
+	)
+	for _, n := range []int{n1, n2} {
+		if n < int(p.LGP.MinLen) || n > int(p.LGP.MaxLen) {
+			return
+		}
+	}
+	var o1 = make([]LGPInstruction, 0, n1)
+	o1 = append(o1, p1[:a1]...)
+	o1 = append(o1, p2[a2:b2]...)
+	o1 = append(o1, p1[b1:]...)
+	var o2 = make([]LGPInstruction, 0, n2)
+	o2 = append(o2, p2[:a2]...)
+	o2 = append(o2, p1[a1:b1]...)
+	o2 = append(o2, p2[b2:]...)
+	p.Instructions, p.effective = o1, nil
+	q.(*LGPProgram).Instructions, q.(*LGPProgram).effective = o2, nil
+}
+
+// Clone returns a deep copy of an LGPProgram.
+func (p LGPProgram) Clone() Genome {
+	return &LGPProgram{
+		Instructions: append([]LGPInstruction(nil), p.Instructions...),
+		LGP:          p.LGP,
+	}
+}
+
+// LGP contains the parameters of linear genetic programming. The NewProgram
+// method can be given to GA.Minimize to evolve LGPPrograms.
+// Reference: https://doi.org/10.1007/978-0-387-31030-5
+type LGP struct {
+	NRegisters   uint      // Number of calculation registers
+	NInputs      uint      // Number of input registers
+	Constants    []float64 // Read-only constant registers
+	NMemory      uint      // Number of memory cells for LGPLoad and LGPStore
+	Output       uint      // Index of the calculation register that holds the output
+	Ops          []LGPOp   // Operations that can be used
+	MinLen       uint      // Minimum number of instructions
+	MaxLen       uint      // Maximum number of instructions
+	InitMinLen   uint      // Minimum number of instructions of a new program
+	InitMaxLen   uint      // Maximum number of instructions of a new program
+	MacroMutRate float64   // Probability of applying MutMacro instead of MutMicro
+	Fitness      func(p *LGPProgram) (float64, error)
+}
+
+// nOperands returns the number of registers an operand can refer to.
+func (lgp *LGP) nOperands() int {
+	return int(lgp.NRegisters+lgp.NInputs) + len(lgp.Constants)
+}
+
+func (lgp *LGP) newInstruction(rng *rand.Rand) LGPInstruction {
+	return LGPInstruction{
+		Op:   lgp.Ops[rng.Intn(len(lgp.Ops))],
+		Dst:  rng.Intn(int(lgp.NRegisters)),
+		Src1: rng.Intn(lgp.nOperands()),
+		Src2: rng.Intn(lgp.nOperands()),
+	}
+}
+
+// NewProgram returns an LGPProgram with a random number of random
+// instructions between InitMinLen and InitMaxLen. NewProgram panics if the LGP
+// is invalid, Validate can be called beforehand to get the error instead.
+func (lgp *LGP) NewProgram(rng *rand.Rand) Genome {
+	if err := lgp.Validate(); err != nil {
+		panic(fmt.Sprintf("eaopt: invalid LGP: %v", err))
+	}
+	var (
+		n            = int(lgp.InitMinLen) + rng.Intn(int(lgp.InitMaxLen-lgp.InitMinLen)+1)
+		instructions = make([]LGPInstruction, n)
+	)
+	for i := range instructions {
+		instructions[i] = lgp.newInstruction(rng)
+	}
+	return &LGPProgram{Instructions: instructions, LGP: lgp}
+}
+
+// Validate LGP fields.
+func (lgp *LGP) Validate() error {
+	if lgp.NRegisters == 0 {
+		return errors.New("NRegisters should be higher than 0")
+	}
+	if lgp.Output >= lgp.NRegisters {
+		return errors.New("Output should be lower than NRegisters")
+	}
+	if len(lgp.Ops) == 0 {
+		return errors.New("at least one operation has to be provided")
+	}
+	for _, op := range lgp.Ops {
+		if op < LGPAdd || op > LGPStore {
+			return fmt.Errorf("unknown operation %d", op)
+		}
+		if (op == LGPLoad || op == LGPStore) && lgp.NMemory == 0 {
+			return errors.New("NMemory should be higher than 0 to use load and store operations")
+		}
+	}
+	if lgp.MinLen > lgp.MaxLen || lgp.MaxLen == 0 {
+		return errors.New("MaxLen should be positive and higher than MinLen")
+	}
+	if lgp.InitMinLen > lgp.InitMaxLen || lgp.InitMinLen < lgp.MinLen || lgp.InitMaxLen > lgp.MaxLen {
+		return errors.New("InitMinLen and InitMaxLen should be ordered and within MinLen and MaxLen")
+	}
+	if lgp.MacroMutRate < 0 || lgp.MacroMutRate > 1 {
+		return errors.New("MacroMutRate should be between 0 and 1")
+	}
+	if lgp.Fitness == nil {
+		return errors.New("fitness function has to be provided")
+	}
+	return nil
+}
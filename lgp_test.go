@@ -0,0 +1,249 @@
+package eaopt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func newTestLGP() *LGP {
+	var xs = []float64{-2, -1, 0, 1, 2, 3}
+	var lgp = &LGP{
+		NRegisters:   4,
+		NInputs:      1,
+		Constants:    []float64{1, 2},
+		NMemory:      2,
+		Ops:          []LGPOp{LGPAdd, LGPSub, LGPMul, LGPDiv, LGPIfGreater, LGPIfLessEq, LGPLoad, LGPStore},
+		MinLen:       1,
+		MaxLen:       30,
+		InitMinLen:   2,
+		InitMaxLen:   10,
+		MacroMutRate: 0.5,
+	}
+	// Symbolic regression of x² + 2
+	lgp.Fitness = func(p *LGPProgram) (float64, error) {
+		var mse float64
+		for _, x := range xs {
+			var y, err = p.Run([]float64{x})
+			if err != nil {
+				return 0, err
+			}
+			mse += math.Pow(y-(x*x+2), 2)
+		}
+		return mse / float64(len(xs)), nil
+	}
+	return lgp
+}
+
+func TestLGPRun(t *testing.T) {
+	var (
+		lgp = newTestLGP()
+		// Registers: r0-r3 calculation, r4 input, r5 = 1, r6 = 2
+		p = &LGPProgram{
+			Instructions: []LGPInstruction{
+				{LGPMul, 0, 4, 4},       // r0 = x * x
+				{LGPIfGreater, 0, 4, 5}, // if x > 1
+				{LGPAdd, 0, 0, 6},       // r0 = r0 + 2
+				{LGPStore, 0, 5, 0},     // mem[1] = r0
+				{LGPSub, 1, 1, 1},       // r1 = 0
+				{LGPLoad, 2, 5, 0},      // r2 = mem[1]
+				{LGPDiv, 0, 2, 1},       // r0 = r2 / 0 = r2
+			},
+			LGP: lgp,
+		}
+	)
+	var testCases = []struct{ x, y float64 }{{3, 11}, {1, 1}, {-2, 4}}
+	for _, tc := range testCases {
+		if y, err := p.Run([]float64{tc.x}); err != nil || y != tc.y {
+			t.Errorf("Expected %f, got %f and %v", tc.y, y, err)
+		}
+	}
+	if _, err := p.Run(nil); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestLGPEffectiveIdxs(t *testing.T) {
+	var (
+		lgp = newTestLGP()
+		p   = &LGPProgram{
+			Instructions: []LGPInstruction{
+				{LGPMul, 1, 4, 4},       // 0: intron, r1 is overwritten before being read
+				{LGPAdd, 2, 4, 5},       // 1: r2 = x + 1
+				{LGPMul, 1, 4, 6},       // 2: r1 = 2x
+				{LGPIfGreater, 0, 4, 5}, // 3: branch guarding an effective instruction
+				{LGPAdd, 0, 1, 2},       // 4: r0 = r1 + r2
+				{LGPSub, 3, 0, 0},       // 5: intron, r3 is never read
+				{LGPIfLessEq, 0, 4, 5},  // 6: intron, guards an intron
+				{LGPMul, 3, 4, 4},       // 7: intron
+			},
+			LGP: lgp,
+		}
+		expected = []int{1, 2, 3, 4}
+	)
+	if idxs := p.EffectiveIdxs(); fmt.Sprint(idxs) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, idxs)
+	}
+	var y, _ = p.Run([]float64{3})
+	p.RemoveIntrons()
+	if len(p.Instructions) != 4 {
+		t.Errorf("Expected 4 instructions, got %d", len(p.Instructions))
+	}
+	if p.LGP.exec(p.Instructions, []float64{3}) != y {
+		t.Error("Removing introns changed the output")
+	}
+}
+
+func TestLGPLoadReads(t *testing.T) {
+	// The address of the load is a constant, hence the register in Src2 isn't
+	// read and the first instruction is an intron
+	var p = &LGPProgram{
+		Instructions: []LGPInstruction{
+			{LGPAdd, 1, 4, 4},
+			{LGPLoad, 0, 5, 1},
+		},
+		LGP: newTestLGP(),
+	}
+	if idxs := p.EffectiveIdxs(); len(idxs) != 1 || idxs[0] != 1 {
+		t.Errorf("Expected [1], got %v", idxs)
+	}
+}
+
+func TestLGPNewProgramInvalid(t *testing.T) {
+	var lgp = newTestLGP()
+	lgp.InitMinLen, lgp.InitMaxLen = 10, 5
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic")
+		}
+	}()
+	lgp.NewProgram(newRand())
+}
+
+func TestLGPIntronRemovalPreservesOutput(t *testing.T) {
+	var (
+		lgp = newTestLGP()
+		rng = newRand()
+	)
+	lgp.InitMaxLen = 30
+	for i := 0; i < 500; i++ {
+		var p = lgp.NewProgram(rng).(*LGPProgram)
+		for _, x := range []float64{-3, -0.5, 0, 0.7, 4} {
+			var (
+				full         = lgp.exec(p.Instructions, []float64{x})
+				effective, _ = p.Run([]float64{x})
+			)
+			if full != effective && !(math.IsNaN(full) && math.IsNaN(effective)) {
+				t.Fatalf("Expected %f, got %f for program\n%s", full, effective, p)
+			}
+		}
+	}
+}
+
+func TestLGPOperatorsRespectLength(t *testing.T) {
+	var (
+		lgp = newTestLGP()
+		rng = newRand()
+	)
+	for i := 0; i < 500; i++ {
+		var (
+			p1    = lgp.NewProgram(rng).(*LGPProgram)
+			p2    = lgp.NewProgram(rng).(*LGPProgram)
+			n1    = len(p1.Instructions)
+			clone = p1.Clone().(*LGPProgram)
+		)
+		p1.Crossover(p2, rng)
+		p1.Mutate(rng)
+		p2.MutMacro(rng)
+		p2.MutMicro(rng)
+		if len(clone.Instructions) != n1 {
+			t.Error("Modifying a program should not modify it's clone")
+		}
+		for _, p := range []*LGPProgram{p1, p2} {
+			if n := len(p.Instructions); n < int(lgp.MinLen) || n > int(lgp.MaxLen) {
+				t.Errorf("Program length %d is out of bounds", n)
+			}
+			for _, ins := range p.Instructions {
+				if ins.Dst >= int(lgp.NRegisters) || ins.Src1 >= lgp.nOperands() || ins.Src2 >= lgp.nOperands() {
+					t.Errorf("Invalid instruction %v", ins)
+				}
+			}
+		}
+	}
+}
+
+func TestLGPCrossoverVariableLength(t *testing.T) {
+	var (
+		lgp     = newTestLGP()
+		rng     = rand.New(rand.NewSource(42))
+		changed bool
+	)
+	for i := 0; i < 50; i++ {
+		var p1, p2 = lgp.NewProgram(rng).(*LGPProgram), lgp.NewProgram(rng).(*LGPProgram)
+		var total = len(p1.Instructions) + len(p2.Instructions)
+		var n1 = len(p1.Instructions)
+		p1.Crossover(p2, rng)
+		if len(p1.Instructions)+len(p2.Instructions) != total {
+			t.Error("Crossover should preserve the total number of instructions")
+		}
+		changed = changed || len(p1.Instructions) != n1
+	}
+	if !changed {
+		t.Error("Crossover never changed the length of a program")
+	}
+}
+
+func TestLGPValidate(t *testing.T) {
+	var valid = newTestLGP()
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	var testCases = []func(lgp *LGP){
+		func(lgp *LGP) { lgp.NRegisters = 0 },
+		func(lgp *LGP) { lgp.Output = 4 },
+		func(lgp *LGP) { lgp.Ops = nil },
+		func(lgp *LGP) { lgp.Ops = []LGPOp{42} },
+		func(lgp *LGP) { lgp.NMemory = 0 },
+		func(lgp *LGP) { lgp.MinLen = 40 },
+		func(lgp *LGP) { lgp.InitMaxLen = 40 },
+		func(lgp *LGP) { lgp.MacroMutRate = 2 },
+		func(lgp *LGP) { lgp.Fitness = nil },
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var lgp = newTestLGP()
+			tc(lgp)
+			if lgp.Validate() == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestLGPMinimize(t *testing.T) {
+	var (
+		lgp  = newTestLGP()
+		conf = NewDefaultGAConfig()
+	)
+	conf.PopSize = 100
+	conf.NGenerations = 100
+	conf.RNG = rand.New(rand.NewSource(1))
+	var ga, _ = conf.NewGA()
+	if err := ga.Minimize(lgp.NewProgram); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if f := ga.HallOfFame[0].Fitness; f > 1e-9 {
+		t.Errorf("Expected a fitness of 0, got %f", f)
+	}
+}
+
+func TestLGPFitnessError(t *testing.T) {
+	var lgp = newTestLGP()
+	lgp.Fitness = func(p *LGPProgram) (float64, error) { return 0, errors.New("") }
+	var ga, _ = NewDefaultGAConfig().NewGA()
+	if err := ga.Minimize(lgp.NewProgram); err == nil {
+		t.Error("Expected error")
+	}
+}
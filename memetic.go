@@ -0,0 +1,414 @@
+package eaopt
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+var (
+	errNilVector     = errors.New("Vector function has to be provided")
+	errInvalidShrink = errors.New("Shrink should be between 0 and 1 (excluded)")
+)
+
+// A LocalSearch improves a Genome by exploring its neighbourhood. Apply is
+// given a Genome along with its fitness and may evaluate at most budget
+// Genomes. It returns the best Genome it found, its fitness and the number of
+// evaluations it performed. The provided Genome must not be modified, Apply
+// has to work on clones.
+type LocalSearch interface {
+	Apply(genome Genome, fitness float64, budget uint, rng *rand.Rand) (Genome, float64, uint, error)
+	Validate() error
+}
+
+// lsBudget keeps track of the number of evaluations performed by a
+// LocalSearch.
+type lsBudget struct {
+	budget, used uint
+}
+
+// eval evaluates a Genome if the budget allows it.
+func (b *lsBudget) eval(genome Genome) (float64, bool, error) {
+	if b.used >= b.budget {
+		return 0, false, nil
+	}
+	b.used++
+	var fitness, err = genome.Evaluate()
+	return fitness, true, err
+}
+
+// ModMemetic applies a LocalSearch to the offsprings produced by another
+// Model. Each offspring, meaning each Individual that hasn't been evaluated
+// after Model has been applied, is improved with probability Rate. The
+// LocalSearch is allowed to evaluate at most Budget Genomes per offspring. In
+// Lamarckian mode the improved Genome replaces the offspring's Genome, whereas
+// in Baldwinian mode the offspring keeps its Genome and only receives the
+// improved fitness.
+type ModMemetic struct {
+	Model      Model
+	Search     LocalSearch
+	Rate       float64
+	Budget     uint
+	Lamarckian bool
+}
+
+// Apply ModMemetic.
+func (mod ModMemetic) Apply(pop *Population) error {
+	if err := mod.Model.Apply(pop); err != nil {
+		return err
+	}
+	for i := range pop.Individuals {
+		var indi = &pop.Individuals[i]
+		if indi.Evaluated || pop.RNG.Float64() >= mod.Rate {
+			continue
+		}
+		if err := pop.evaluate(indi); err != nil {
+			return err
+		}
+		var genome, fitness, used, err = mod.Search.Apply(indi.Genome, indi.Fitness, mod.Budget, pop.RNG)
+		pop.Run.CountEvaluations(int(used))
+		if err != nil {
+			return err
+		}
+		if fitness >= indi.Fitness {
+			continue
+		}
+		if mod.Lamarckian {
+			indi.Genome = genome
+		}
+		indi.Fitness = fitness
+	}
+	return nil
+}
+
+// Validate ModMemetic fields.
+func (mod ModMemetic) Validate() error {
+	if mod.Model == nil {
+		return errors.New("Model cannot be nil")
+	}
+	if err := mod.Model.Validate(); err != nil {
+		return err
+	}
+	if mod.Search == nil {
+		return errors.New("Search cannot be nil")
+	}
+	if err := mod.Search.Validate(); err != nil {
+		return err
+	}
+	if mod.Rate < 0 || mod.Rate > 1 {
+		return errors.New("Rate should be between 0 and 1")
+	}
+	if mod.Budget == 0 {
+		return errors.New("Budget should be higher than 0")
+	}
+	return nil
+}
+
+// LSHillClimbing is a stochastic hill climber. At each step Neighbour is
+// called on a clone of the current Genome in order to produce a neighbour,
+// which replaces the current Genome if it is strictly better. The search stops
+// when the budget is exhausted or when MaxStale consecutive neighbours didn't
+// bring any improvement, MaxStale = 0 meaning no limit.
+type LSHillClimbing struct {
+	Neighbour func(genome Genome, rng *rand.Rand)
+	MaxStale  uint
+}
+
+// Apply LSHillClimbing.
+func (ls LSHillClimbing) Apply(genome Genome, fitness float64, budget uint, rng *rand.Rand) (Genome, float64, uint, error) {
+	var (
+		b     = lsBudget{budget: budget}
+		best  = genome
+		stale uint
+	)
+	for ls.MaxStale == 0 || stale < ls.MaxStale {
+		var neighbour = best.Clone()
+		ls.Neighbour(neighbour, rng)
+		var f, ok, err = b.eval(neighbour)
+		if err != nil {
+			return nil, 0, b.used, err
+		}
+		if !ok {
+			break
+		}
+		if f < fitness {
+			best, fitness, stale = neighbour, f, 0
+		} else {
+			stale++
+		}
+	}
+	return best, fitness, b.used, nil
+}
+
+// Validate LSHillClimbing fields.
+func (ls LSHillClimbing) Validate() error {
+	if ls.Neighbour == nil {
+		return errors.New("Neighbour function has to be provided")
+	}
+	return nil
+}
+
+// LSTwoOpt applies first-improvement 2-opt to Genomes that represent tours.
+// Slice returns the Slice view of a Genome, which is modified in place. Each
+// move reverses a segment of the tour and is kept if it improves the fitness.
+// The search stops when no move improves the fitness or when the budget is
+// exhausted.
+type LSTwoOpt struct {
+	Slice func(genome Genome) Slice
+}
+
+// Apply LSTwoOpt.
+func (ls LSTwoOpt) Apply(genome Genome, fitness float64, budget uint, rng *rand.Rand) (Genome, float64, uint, error) {
+	var (
+		b        = lsBudget{budget: budget}
+		clone    = genome.Clone()
+		s        = ls.Slice(clone)
+		n        = s.Len()
+		improved = true
+	)
+	for improved {
+		improved = false
+		for i := 0; i < n-1; i++ {
+			for j := i + 1; j < n; j++ {
+				// Reversing the whole tour doesn't change it
+				if i == 0 && j == n-1 {
+					continue
+				}
+				reverseSegment(s, i, j)
+				var f, ok, err = b.eval(clone)
+				if err != nil {
+					return nil, 0, b.used, err
+				}
+				if ok && f < fitness {
+					fitness, improved = f, true
+					continue
+				}
+				reverseSegment(s, i, j)
+				if !ok {
+					return clone, fitness, b.used, nil
+				}
+			}
+		}
+	}
+	return clone, fitness, b.used, nil
+}
+
+// Validate LSTwoOpt fields.
+func (ls LSTwoOpt) Validate() error {
+	if ls.Slice == nil {
+		return errors.New("Slice function has to be provided")
+	}
+	return nil
+}
+
+// reverseSegment reverses the genes of a Slice between indexes a and b
+// (inclusive).
+func reverseSegment(s Slice, a, b int) {
+	for ; a < b; a, b = a+1, b-1 {
+		s.Swap(a, b)
+	}
+}
+
+// vectorSearch contains the fields that are shared by LSCoordinate and
+// LSPattern.
+type vectorSearch struct {
+	Vector  func(genome Genome) []float64 // Returns the genes of a Genome, which are modified in place
+	Steps   []float64                     // Initial step size for each gene, a single step applies to every gene
+	Shrink  float64                       // Factor applied to the steps when no improvement is found
+	MinStep float64                       // The search stops once every step is below MinStep
+	Lower   []float64                     // Optional lower bounds
+	Upper   []float64                     // Optional upper bounds
+}
+
+// initSteps returns a copy of the initial steps for a vector of n genes and
+// checks that the steps and the bounds match the number of genes.
+func (vs vectorSearch) initSteps(n int) ([]float64, error) {
+	if vs.Lower != nil && len(vs.Lower) != n {
+		return nil, fmt.Errorf("Lower and Upper should contain %d values, got %d", n, len(vs.Lower))
+	}
+	if len(vs.Steps) == 1 {
+		var steps = make([]float64, n)
+		for i := range steps {
+			steps[i] = vs.Steps[0]
+		}
+		return steps, nil
+	}
+	if len(vs.Steps) != n {
+		return nil, fmt.Errorf("Steps should contain 1 or %d values, got %d", n, len(vs.Steps))
+	}
+	return copyFloat64s(vs.Steps), nil
+}
+
+func (vs vectorSearch) clamp(x []float64, i int) {
+	if vs.Lower != nil {
+		x[i] = clampFloat64(x[i], vs.Lower[i], vs.Upper[i])
+	}
+}
+
+// explore tries to move each gene by plus or minus its step and keeps the
+// moves that improve the fitness. It returns false once the budget is
+// exhausted.
+func (vs vectorSearch) explore(genome Genome, x []float64, fitness float64, steps []float64,
+	b *lsBudget) (float64, bool, error) {
+	for i := range x {
+		var orig = x[i]
+		for _, dir := range []float64{1, -1} {
+			x[i] = orig + dir*steps[i]
+			vs.clamp(x, i)
+			if x[i] == orig {
+				continue
+			}
+			var f, ok, err = b.eval(genome)
+			if err != nil || !ok {
+				x[i] = orig
+				return fitness, false, err
+			}
+			if f < fitness {
+				fitness = f
+				break
+			}
+			x[i] = orig
+		}
+	}
+	return fitness, true, nil
+}
+
+// shrink reduces the steps and returns false if they are all below MinStep.
+func (vs vectorSearch) shrink(steps []float64) bool {
+	var active bool
+	for i := range steps {
+		steps[i] *= vs.Shrink
+		active = active || steps[i] >= vs.MinStep
+	}
+	return active
+}
+
+func (vs vectorSearch) validate() error {
+	if vs.Vector == nil {
+		return errNilVector
+	}
+	if len(vs.Steps) == 0 {
+		return errors.New("Steps should contain at least one value")
+	}
+	if vs.Shrink <= 0 || vs.Shrink >= 1 {
+		return errInvalidShrink
+	}
+	if vs.MinStep <= 0 {
+		return errors.New("MinStep should be higher than 0")
+	}
+	if (vs.Lower == nil) != (vs.Upper == nil) || len(vs.Lower) != len(vs.Upper) {
+		return errors.New("Lower and Upper should have the same length")
+	}
+	return nil
+}
+
+// LSCoordinate implements coordinate search, also known as compass search.
+// Each gene is moved in turn by plus or minus its step and the move is kept if
+// it improves the fitness. When a full sweep brings no improvement the steps
+// are multiplied by Shrink.
+type LSCoordinate vectorSearch
+
+// Apply LSCoordinate.
+func (ls LSCoordinate) Apply(genome Genome, fitness float64, budget uint, rng *rand.Rand) (Genome, float64, uint, error) {
+	var (
+		vs    = vectorSearch(ls)
+		b     = lsBudget{budget: budget}
+		clone = genome.Clone()
+		x     = vs.Vector(clone)
+	)
+	var steps, err = vs.initSteps(len(x))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	for {
+		var f, ok, err = vs.explore(clone, x, fitness, steps, &b)
+		if err != nil {
+			return nil, 0, b.used, err
+		}
+		var improved = f < fitness
+		fitness = f
+		if !ok || (!improved && !vs.shrink(steps)) {
+			break
+		}
+	}
+	return clone, fitness, b.used, nil
+}
+
+// Validate LSCoordinate fields.
+func (ls LSCoordinate) Validate() error {
+	return vectorSearch(ls).validate()
+}
+
+// LSPattern implements Hooke and Jeeves' pattern search. An exploratory
+// coordinate sweep is performed around the current base point. If it improves
+// the fitness, the search jumps further in the direction of the improvement
+// (the pattern move) and explores around the new point. The steps are
+// multiplied by Shrink when the exploration around the base point fails.
+// Reference: https://doi.org/10.1145/321062.321069
+type LSPattern vectorSearch
+
+// Apply LSPattern.
+func (ls LSPattern) Apply(genome Genome, fitness float64, budget uint, rng *rand.Rand) (Genome, float64, uint, error) {
+	var (
+		vs   = vectorSearch(ls)
+		b    = lsBudget{budget: budget}
+		base = genome.Clone()
+	)
+	var steps, err = vs.initSteps(len(vs.Vector(base)))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	for {
+		var (
+			trial      = base.Clone()
+			x          = vs.Vector(trial)
+			f, ok, err = vs.explore(trial, x, fitness, steps, &b)
+		)
+		if err != nil {
+			return nil, 0, b.used, err
+		}
+		if f >= fitness {
+			if !ok || !vs.shrink(steps) {
+				break
+			}
+			continue
+		}
+		// Keep making pattern moves as long as they improve the fitness
+		for ok && f < fitness {
+			var (
+				b0      = vs.Vector(base)
+				pattern = trial.Clone()
+				p       = vs.Vector(pattern)
+			)
+			for i := range p {
+				p[i] += x[i] - b0[i]
+				vs.clamp(p, i)
+			}
+			base, fitness = trial, f
+			var fp float64
+			if fp, ok, err = b.eval(pattern); err != nil {
+				return nil, 0, b.used, err
+			}
+			if !ok {
+				break
+			}
+			trial, x = pattern, p
+			if f, ok, err = vs.explore(trial, x, fp, steps, &b); err != nil {
+				return nil, 0, b.used, err
+			}
+		}
+		// The budget may run out while exploring around an improving point
+		if f < fitness {
+			base, fitness = trial, f
+		}
+		if !ok {
+			break
+		}
+	}
+	return base, fitness, b.used, nil
+}
+
+// Validate LSPattern fields.
+func (ls LSPattern) Validate() error {
+	return vectorSearch(ls).validate()
+}
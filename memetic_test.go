@@ -0,0 +1,291 @@
+package eaopt
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// shiftedSphere is a Genome whose fitness is the squared distance to (1, 1, ...).
+type shiftedSphere []float64
+
+func (s shiftedSphere) Evaluate() (float64, error) {
+	var sum float64
+	for _, x := range s {
+		sum += (x - 1) * (x - 1)
+	}
+	return sum, nil
+}
+func (s shiftedSphere) Mutate(rng *rand.Rand) { MutNormalFloat64(s, 0.5, rng) }
+func (s shiftedSphere) Crossover(t Genome, rng *rand.Rand) {
+	CrossUniformFloat64(s, t.(shiftedSphere), rng)
+}
+func (s shiftedSphere) Clone() Genome { return append(shiftedSphere(nil), s...) }
+
+// lineTour is a tour of cities placed on a line, city i being at position i.
+type lineTour []int
+
+func (t lineTour) Evaluate() (float64, error) {
+	var length float64
+	for i := range t {
+		length += math.Abs(float64(t[i] - t[(i+1)%len(t)]))
+	}
+	return length, nil
+}
+func (t lineTour) Mutate(rng *rand.Rand)              { MutPermuteInt(t, 3, rng) }
+func (t lineTour) Crossover(u Genome, rng *rand.Rand) { CrossPMXInt(t, u.(lineTour), rng) }
+func (t lineTour) Clone() Genome                      { return append(lineTour(nil), t...) }
+
+func sphereVector(genome Genome) []float64 { return genome.(shiftedSphere) }
+
+func TestLocalSearches(t *testing.T) {
+	var searches = []LocalSearch{
+		LSHillClimbing{
+			Neighbour: func(genome Genome, rng *rand.Rand) { genome.Mutate(rng) },
+		},
+		LSCoordinate{
+			Vector:  sphereVector,
+			Steps:   []float64{1}, // Used for every gene
+			Shrink:  0.5,
+			MinStep: 1e-6,
+		},
+		LSPattern{
+			Vector:  sphereVector,
+			Steps:   []float64{1, 1, 1},
+			Shrink:  0.5,
+			MinStep: 1e-6,
+		},
+		LSPattern{
+			Vector:  sphereVector,
+			Steps:   []float64{1, 1, 1},
+			Shrink:  0.5,
+			MinStep: 1e-6,
+			Lower:   []float64{-5, -5, -5},
+			Upper:   []float64{0, 0, 0},
+		},
+	}
+	for i, ls := range searches {
+		if err := ls.Validate(); err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+		var (
+			rng        = newRand()
+			genome     = shiftedSphere{-4, 3, 7}
+			fitness, _ = genome.Evaluate()
+		)
+		var improved, f, used, err = ls.Apply(genome, fitness, 200, rng)
+		if err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+		if used == 0 || used > 200 {
+			t.Errorf("Test %d: %d evaluations were used", i, used)
+		}
+		if f >= fitness {
+			t.Errorf("Test %d: expected an improvement over %f, got %f", i, fitness, f)
+		}
+		if g, _ := improved.Evaluate(); g != f {
+			t.Errorf("Test %d: expected the returned fitness %f to match %f", i, f, g)
+		}
+		// The original Genome is left untouched
+		if genome[0] != -4 || genome[1] != 3 || genome[2] != 7 {
+			t.Errorf("Test %d: the Genome was modified: %v", i, genome)
+		}
+	}
+}
+
+func TestVectorSearchConverges(t *testing.T) {
+	var ls = LSCoordinate{
+		Vector:  sphereVector,
+		Steps:   []float64{1, 1},
+		Shrink:  0.5,
+		MinStep: 1e-3,
+	}
+	var genome, f, used, _ = ls.Apply(shiftedSphere{-2.3, 4.1}, 28.9, 10000, newRand())
+	if used == 10000 {
+		t.Error("The search should have stopped because of MinStep")
+	}
+	if f > 1e-5 {
+		t.Errorf("Expected a near-optimal fitness, got %f (%v)", f, genome)
+	}
+	// Bounds are respected
+	ls.Lower, ls.Upper = []float64{-3, -3}, []float64{0.5, 3}
+	genome, _, _, _ = ls.Apply(shiftedSphere{-2.3, -1}, 14.89, 10000, newRand())
+	if x := genome.(shiftedSphere); x[0] != 0.5 || math.Abs(x[1]-1) > 1e-3 {
+		t.Errorf("Expected (0.5, 1), got %v", x)
+	}
+}
+
+func TestVectorSearchLengths(t *testing.T) {
+	var searches = []LocalSearch{
+		LSCoordinate{Vector: sphereVector, Steps: []float64{1, 1}, Shrink: 0.5, MinStep: 1},
+		LSPattern{Vector: sphereVector, Steps: []float64{1, 1}, Shrink: 0.5, MinStep: 1},
+		LSCoordinate{Vector: sphereVector, Steps: []float64{1}, Shrink: 0.5, MinStep: 1, Lower: []float64{0}, Upper: []float64{1}},
+		LSPattern{Vector: sphereVector, Steps: []float64{1}, Shrink: 0.5, MinStep: 1, Lower: []float64{0}, Upper: []float64{1}},
+	}
+	for i, ls := range searches {
+		if err := ls.Validate(); err != nil {
+			t.Errorf("Test %d: expected nil, got %v", i, err)
+		}
+		if _, _, _, err := ls.Apply(shiftedSphere{1, 2, 3}, 14, 10, newRand()); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+}
+
+func TestLSTwoOpt(t *testing.T) {
+	var (
+		ls         = LSTwoOpt{Slice: func(genome Genome) Slice { return IntSlice(genome.(lineTour)) }}
+		tour       = lineTour{0, 5, 2, 7, 1, 4, 6, 3}
+		fitness, _ = tour.Evaluate()
+	)
+	var improved, f, used, err = ls.Apply(tour, fitness, 1000, newRand())
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	// Two times the distance between the two furthest cities is optimal
+	if f != 14 {
+		t.Errorf("Expected 14, got %f (%v)", f, improved)
+	}
+	if !isPermutation(improved.(lineTour), []int(tour)) {
+		t.Errorf("Expected a permutation, got %v", improved)
+	}
+	if used >= 1000 {
+		t.Errorf("Expected the search to stop before the budget, got %d", used)
+	}
+	// A small budget is respected
+	if _, _, used, _ = ls.Apply(tour, fitness, 3, newRand()); used != 3 {
+		t.Errorf("Expected 3 evaluations, got %d", used)
+	}
+}
+
+func TestModMemetic(t *testing.T) {
+	var search = LSCoordinate{
+		Vector:  sphereVector,
+		Steps:   []float64{1, 1},
+		Shrink:  0.5,
+		MinStep: 1e-3,
+	}
+	for _, lamarckian := range []bool{true, false} {
+		var (
+			mod = ModMemetic{
+				Model:      ModIdentity{},
+				Search:     search,
+				Rate:       1,
+				Budget:     50,
+				Lamarckian: lamarckian,
+			}
+			pop = Population{
+				Individuals: Individuals{
+					NewIndividual(shiftedSphere{3, -2}, newRand()),
+					NewIndividual(shiftedSphere{0, 0}, newRand()),
+				},
+				RNG: newRand(),
+			}
+		)
+		pop.Individuals[1].Fitness, pop.Individuals[1].Evaluated = 2, true
+		if err := mod.Validate(); err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+		if err := mod.Apply(&pop); err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+		var (
+			indi    = pop.Individuals[0]
+			real, _ = indi.Genome.Evaluate()
+		)
+		if !indi.Evaluated || indi.Fitness >= 13 {
+			t.Errorf("Expected an improved fitness, got %f", indi.Fitness)
+		}
+		if lamarckian && real != indi.Fitness {
+			t.Errorf("Lamarckian: expected the genome to be written back, got %v", indi.Genome)
+		}
+		if !lamarckian && real != 13 {
+			t.Errorf("Baldwinian: expected the genome to be kept, got %v", indi.Genome)
+		}
+		// Evaluated individuals are left alone
+		if pop.Individuals[1].Fitness != 2 {
+			t.Error("Evaluated individuals should not be improved")
+		}
+	}
+}
+
+func TestModMemeticRate(t *testing.T) {
+	var (
+		mod = ModMemetic{
+			Model: ModIdentity{},
+			Search: LSHillClimbing{
+				Neighbour: func(genome Genome, rng *rand.Rand) { genome.Mutate(rng) },
+			},
+			Rate:   0,
+			Budget: 10,
+		}
+		pop = Population{Individuals: Individuals{NewIndividual(shiftedSphere{3}, newRand())}, RNG: newRand()}
+	)
+	if err := mod.Apply(&pop); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if pop.Individuals[0].Evaluated {
+		t.Error("No local search should have been applied")
+	}
+}
+
+func TestModMemeticErrors(t *testing.T) {
+	var (
+		search  = LSHillClimbing{Neighbour: func(genome Genome, rng *rand.Rand) {}}
+		invalid = []Model{
+			ModMemetic{Search: search, Rate: 0.5, Budget: 10},
+			ModMemetic{Model: ModValidateError{}, Search: search, Rate: 0.5, Budget: 10},
+			ModMemetic{Model: ModIdentity{}, Rate: 0.5, Budget: 10},
+			ModMemetic{Model: ModIdentity{}, Search: LSHillClimbing{}, Rate: 0.5, Budget: 10},
+			ModMemetic{Model: ModIdentity{}, Search: search, Rate: 1.5, Budget: 10},
+			ModMemetic{Model: ModIdentity{}, Search: search, Rate: 0.5},
+			ModMemetic{Model: ModIdentity{}, Search: LSTwoOpt{}, Rate: 0.5, Budget: 10},
+			ModMemetic{Model: ModIdentity{}, Search: LSCoordinate{Vector: sphereVector, Steps: []float64{1}, Shrink: 1, MinStep: 1}, Rate: 0.5, Budget: 10},
+			ModMemetic{Model: ModIdentity{}, Search: LSPattern{Steps: []float64{1}, Shrink: 0.5, MinStep: 1}, Rate: 0.5, Budget: 10},
+			ModMemetic{Model: ModIdentity{}, Search: LSPattern{Vector: sphereVector, Steps: []float64{1}, Shrink: 0.5, MinStep: 1, Lower: []float64{0}}, Rate: 0.5, Budget: 10},
+		}
+	)
+	for i, mod := range invalid {
+		if err := mod.Validate(); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+	// Evaluation and model errors are propagated
+	var mod = ModMemetic{Model: ModIdentity{}, Search: search, Rate: 1, Budget: 10}
+	var pop = Population{Individuals: Individuals{NewIndividual(ErrorGenome{}, newRand())}, RNG: newRand()}
+	if err := mod.Apply(&pop); err == nil {
+		t.Error("Expected error")
+	}
+	mod.Model = ModRuntimeError{}
+	if err := mod.Apply(&pop); err == nil {
+		t.Error("Expected error")
+	}
+	if _, _, _, err := search.Apply(ErrorGenome{}, 0, 10, newRand()); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestGAMemetic(t *testing.T) {
+	var ga, err = GAConfig{
+		NPops:        1,
+		PopSize:      20,
+		NGenerations: 10,
+		HofSize:      1,
+		Model: ModMemetic{
+			Model:  ModGenerational{Selector: SelTournament{NContestants: 3}, MutRate: 0.5},
+			Search: LSPattern{Vector: sphereVector, Steps: []float64{1, 1, 1}, Shrink: 0.5, MinStep: 1e-3},
+			Rate:   0.2,
+			Budget: 30,
+		},
+		RNG: newRand(),
+	}.NewGA()
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if err = ga.Minimize(func(rng *rand.Rand) Genome { return shiftedSphere(InitUnifFloat64(3, -10, 10, rng)) }); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if ga.HallOfFame[0].Fitness > 1 {
+		t.Errorf("Expected a good solution, got %f", ga.HallOfFame[0].Fitness)
+	}
+}
@@ -2,6 +2,7 @@ package eaopt
 
 import (
 	"errors"
+	"fmt"
 	"math/rand"
 )
 
@@ -12,11 +13,22 @@ type Migrator interface {
 	Validate() error
 }
 
+// A FallibleMigrator is a Migrator whose migrations can fail, for instance
+// because they rely on Selectors. The GA calls TryApply instead of Apply on
+// the Migrators that implement it and returns the errors it encounters.
+type FallibleMigrator interface {
+	Migrator
+	TryApply(pops Populations, rng *rand.Rand) error
+}
+
 // MigRing migration exchanges individuals between consecutive Populations in a
 // random fashion. One by one, each population exchanges NMigrants individuals
 // at random with the next population. NMigrants should be not higher than the
 // number of individuals in each population, else all the individuals will
-// migrate and it will be as if nothing happened.
+// migrate and it will be as if nothing happened. The last population doesn't
+// exchange individuals with the first one, use MigTopology with a TopoRing for
+// a closed ring. If two Populations have different sizes then the individuals
+// are exchanged at positions that exist in both of them.
 type MigRing struct {
 	NMigrants uint // Number of migrants per exchange between Populations
 }
@@ -24,7 +36,8 @@ type MigRing struct {
 // Apply MigRing.
 func (mig MigRing) Apply(pops Populations, rng *rand.Rand) {
 	for i := 0; i < len(pops)-1; i++ {
-		for _, k := range randomInts(mig.NMigrants, 0, len(pops[i].Individuals), rng) {
+		var size = minInt(len(pops[i].Individuals), len(pops[i+1].Individuals))
+		for _, k := range randomInts(minUint(mig.NMigrants, uint(size)), 0, size, rng) {
 			pops[i].Individuals[k], pops[i+1].Individuals[k] = pops[i+1].Individuals[k], pops[i].Individuals[k]
 		}
 	}
@@ -37,3 +50,355 @@ func (mig MigRing) Validate() error {
 	}
 	return nil
 }
+
+// A Topology determines which Populations send emigrants to which
+// Populations. Adjacency is called at each migration with the number of
+// Populations, it returns for each Population the indexes of the Populations
+// it sends emigrants to.
+type Topology interface {
+	Adjacency(n int, rng *rand.Rand) ([][]int, error)
+	Validate() error
+}
+
+// TopoRing connects each Population to the next one, the last Population
+// being connected to the first one. If Bidirectional is true then each
+// Population is also connected to the previous one.
+type TopoRing struct {
+	Bidirectional bool
+}
+
+// Adjacency method from Topology.
+func (topo TopoRing) Adjacency(n int, rng *rand.Rand) ([][]int, error) {
+	var adj = make([][]int, n)
+	for i := range adj {
+		adj[i] = appendNeighbour(adj[i], i, (i+1)%n)
+		if topo.Bidirectional {
+			adj[i] = appendNeighbour(adj[i], i, (i+n-1)%n)
+		}
+	}
+	return adj, nil
+}
+
+// Validate TopoRing fields.
+func (topo TopoRing) Validate() error {
+	return nil
+}
+
+// TopoFullyConnected connects each Population to every other Population.
+type TopoFullyConnected struct{}
+
+// Adjacency method from Topology.
+func (topo TopoFullyConnected) Adjacency(n int, rng *rand.Rand) ([][]int, error) {
+	var adj = make([][]int, n)
+	for i := range adj {
+		for j := 0; j < n; j++ {
+			adj[i] = appendNeighbour(adj[i], i, j)
+		}
+	}
+	return adj, nil
+}
+
+// Validate TopoFullyConnected fields.
+func (topo TopoFullyConnected) Validate() error {
+	return nil
+}
+
+// TopoStar connects the Hub Population to every other Population and every
+// other Population to the Hub.
+type TopoStar struct {
+	Hub uint
+}
+
+// Adjacency method from Topology.
+func (topo TopoStar) Adjacency(n int, rng *rand.Rand) ([][]int, error) {
+	var hub = int(topo.Hub)
+	if hub >= n {
+		return nil, fmt.Errorf("hub %d is out of range with %d populations", hub, n)
+	}
+	var adj = make([][]int, n)
+	for i := range adj {
+		if i != hub {
+			adj[i] = []int{hub}
+			adj[hub] = append(adj[hub], i)
+		}
+	}
+	return adj, nil
+}
+
+// Validate TopoStar fields.
+func (topo TopoStar) Validate() error {
+	return nil
+}
+
+// TopoGrid arranges the Populations on a grid with Rows rows and Cols
+// columns, Population i being located at row i / Cols and column i % Cols.
+// Each Population is connected to the Populations above, below, to the left
+// and to the right of it. If Torus is true then the edges of the grid wrap
+// around. The number of Populations has to be equal to Rows * Cols.
+type TopoGrid struct {
+	Rows, Cols uint
+	Torus      bool
+}
+
+// Adjacency method from Topology.
+func (topo TopoGrid) Adjacency(n int, rng *rand.Rand) ([][]int, error) {
+	var rows, cols = int(topo.Rows), int(topo.Cols)
+	if rows*cols != n {
+		return nil, fmt.Errorf("a %dx%d grid needs %d populations, have %d", rows, cols, rows*cols, n)
+	}
+	var adj = make([][]int, n)
+	for i := range adj {
+		var r, c = i / cols, i % cols
+		for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+			var rr, cc = r + d[0], c + d[1]
+			if topo.Torus {
+				rr, cc = (rr+rows)%rows, (cc+cols)%cols
+			} else if rr < 0 || rr >= rows || cc < 0 || cc >= cols {
+				continue
+			}
+			adj[i] = appendNeighbour(adj[i], i, rr*cols+cc)
+		}
+	}
+	return adj, nil
+}
+
+// Validate TopoGrid fields.
+func (topo TopoGrid) Validate() error {
+	if topo.Rows == 0 || topo.Cols == 0 {
+		return errors.New("Rows and Cols should be higher than 0")
+	}
+	return nil
+}
+
+// TopoRandom connects each Population to Degree other Populations drawn at
+// random. A new random topology is drawn at each migration.
+type TopoRandom struct {
+	Degree uint
+}
+
+// Adjacency method from Topology.
+func (topo TopoRandom) Adjacency(n int, rng *rand.Rand) ([][]int, error) {
+	var (
+		adj    = make([][]int, n)
+		degree = minUint(topo.Degree, uint(maxInt(n-1, 0)))
+	)
+	for i := range adj {
+		// Draw among the n - 1 other Populations and skip i
+		for _, j := range randomInts(degree, 0, n-1, rng) {
+			if j >= i {
+				j++
+			}
+			adj[i] = append(adj[i], j)
+		}
+	}
+	return adj, nil
+}
+
+// Validate TopoRandom fields.
+func (topo TopoRandom) Validate() error {
+	if topo.Degree == 0 {
+		return errors.New("Degree should be higher than 0")
+	}
+	return nil
+}
+
+// TopoAdjacency is a user defined Topology, Population i sends emigrants to
+// the Populations whose indexes are in Neighbours[i]. Neighbours should have
+// one entry per Population.
+type TopoAdjacency struct {
+	Neighbours [][]int
+}
+
+// Adjacency method from Topology.
+func (topo TopoAdjacency) Adjacency(n int, rng *rand.Rand) ([][]int, error) {
+	if len(topo.Neighbours) != n {
+		return nil, fmt.Errorf("neighbours has %d entries but there are %d populations", len(topo.Neighbours), n)
+	}
+	for i, dests := range topo.Neighbours {
+		for _, j := range dests {
+			if j < 0 || j >= n {
+				return nil, fmt.Errorf("population %d is connected to %d, which is out of range", i, j)
+			}
+		}
+	}
+	return topo.Neighbours, nil
+}
+
+// Validate TopoAdjacency fields.
+func (topo TopoAdjacency) Validate() error {
+	for i, dests := range topo.Neighbours {
+		var seen = make(map[int]bool, len(dests))
+		for _, j := range dests {
+			if j == i {
+				return fmt.Errorf("population %d can't be connected to itself", i)
+			}
+			if seen[j] {
+				return fmt.Errorf("population %d is connected to %d more than once", i, j)
+			}
+			seen[j] = true
+		}
+	}
+	return nil
+}
+
+// appendNeighbour adds j to the neighbours of i, unless j is i or is already
+// a neighbour, which can happen with small rings and grids.
+func appendNeighbour(neighbours []int, i, j int) []int {
+	if j == i || containsInt(neighbours, j) {
+		return neighbours
+	}
+	return append(neighbours, j)
+}
+
+// MigTopology migrates individuals between the Populations that are connected
+// by a Topology. For each connection NMigrants emigrants are chosen in the
+// source Population with the Emigrants Selector and they replace individuals
+// of the destination Population chosen with the Replacement Selector. The
+// Replacement Selector is applied as if fitnesses were maximized, hence
+// SelElitism replaces the worst individuals. If a Selector is nil then the
+// individuals are chosen at random. Individuals that are selected more than
+// once only count once.
+//
+// By default the emigrants are cloned, which means that they also stay in
+// their source Population. In this case the emigrants are chosen before any
+// individual is replaced, so that an individual can't travel through several
+// Populations in a single migration. If Swap is true then the emigrants and
+// the individuals they replace are exchanged, the connections being processed
+// one after the other.
+type MigTopology struct {
+	Topology    Topology
+	NMigrants   uint
+	Emigrants   Selector
+	Replacement Selector
+	Swap        bool
+}
+
+// selectMigrants returns the indexes of at most n individuals of a Population
+// chosen with a Selector. If worst is true then the Selector is applied as if
+// fitnesses were maximized.
+func selectMigrants(sel Selector, n uint, pop *Population, worst bool, rng *rand.Rand) ([]int, error) {
+	var indis = pop.Individuals
+	n = minUint(n, uint(len(indis)))
+	if sel == nil {
+		return randomInts(n, 0, len(indis), rng), nil
+	}
+	if err := pop.evaluateAll(pop.Run.parallel()); err != nil {
+		return nil, err
+	}
+	// Some Selectors sort the individuals they are given, hence the fitnesses
+	// are negated in place so that the indexes refer to indis
+	if worst {
+		var negate = func() {
+			for i := range indis {
+				indis[i].Fitness = -indis[i].Fitness
+			}
+		}
+		negate()
+		defer negate()
+	}
+	var _, idxs, err = selectorFor(sel, pop.Run).Apply(n, indis, rng)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		unique []int
+		seen   = make(map[int]bool, len(idxs))
+	)
+	for _, idx := range idxs {
+		if !seen[idx] {
+			unique = append(unique, idx)
+			seen[idx] = true
+		}
+	}
+	return unique, nil
+}
+
+// Apply MigTopology. The migration stops at the first error, which is ignored,
+// use TryApply to get it.
+func (mig MigTopology) Apply(pops Populations, rng *rand.Rand) {
+	mig.TryApply(pops, rng)
+}
+
+// TryApply applies MigTopology and returns the errors raised by the Topology,
+// the Selectors or the evaluations of the individuals.
+func (mig MigTopology) TryApply(pops Populations, rng *rand.Rand) error {
+	var adj, err = mig.Topology.Adjacency(len(pops), rng)
+	if err != nil {
+		return err
+	}
+	if mig.Swap {
+		for i, dests := range adj {
+			for _, j := range dests {
+				if err := mig.exchange(&pops[i], &pops[j], rng); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	// Choose all the emigrants before replacing any individual
+	var emigrants = make([][]Individuals, len(adj))
+	for i, dests := range adj {
+		emigrants[i] = make([]Individuals, len(dests))
+		for k := range dests {
+			var idxs, err = selectMigrants(mig.Emigrants, mig.NMigrants, &pops[i], false, rng)
+			if err != nil {
+				return err
+			}
+			for _, idx := range idxs {
+				emigrants[i][k] = append(emigrants[i][k], pops[i].Individuals[idx].Clone(rng))
+			}
+		}
+	}
+	for i, dests := range adj {
+		for k, j := range dests {
+			var idxs, err = selectMigrants(mig.Replacement, uint(len(emigrants[i][k])), &pops[j], true, rng)
+			if err != nil {
+				return err
+			}
+			for m, idx := range idxs {
+				pops[j].Individuals[idx] = emigrants[i][k][m]
+			}
+		}
+	}
+	return nil
+}
+
+// exchange swaps emigrants of a Population with individuals of another one.
+func (mig MigTopology) exchange(src, dst *Population, rng *rand.Rand) error {
+	var emigrants, err = selectMigrants(mig.Emigrants, mig.NMigrants, src, false, rng)
+	if err != nil {
+		return err
+	}
+	replaced, err := selectMigrants(mig.Replacement, uint(len(emigrants)), dst, true, rng)
+	if err != nil {
+		return err
+	}
+	for m, idx := range replaced {
+		var e = emigrants[m]
+		src.Individuals[e], dst.Individuals[idx] = dst.Individuals[idx], src.Individuals[e]
+	}
+	return nil
+}
+
+// Validate MigTopology fields.
+func (mig MigTopology) Validate() error {
+	if mig.Topology == nil {
+		return errors.New("Topology cannot be nil")
+	}
+	if err := mig.Topology.Validate(); err != nil {
+		return err
+	}
+	if mig.NMigrants == 0 {
+		return errors.New("NMigrants should be higher than 0")
+	}
+	for _, sel := range []Selector{mig.Emigrants, mig.Replacement} {
+		if sel == nil {
+			continue
+		}
+		if err := sel.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,14 +1,29 @@
 package eaopt
 
-import "testing"
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
 
 func TestMigSizes(t *testing.T) {
 	var (
-		rng       = newRand()
+		// Swapping individuals back and forth can leave a population
+		// unchanged, hence the RNG is seeded
+		rng       = rand.New(rand.NewSource(42))
 		migrators = []Migrator{
 			MigRing{
 				NMigrants: 5,
 			},
+			MigTopology{Topology: TopoRing{}, NMigrants: 5},
+			MigTopology{Topology: TopoFullyConnected{}, NMigrants: 2, Swap: true},
+			MigTopology{
+				Topology:    TopoStar{},
+				NMigrants:   3,
+				Emigrants:   SelElitism{},
+				Replacement: SelTournament{NContestants: 2},
+			},
 		}
 	)
 	for _, migrator := range migrators {
@@ -22,7 +37,13 @@ func TestMigSizes(t *testing.T) {
 					pops[i].Individuals.Evaluate(false)
 					fitnessMeans[i] = pops[i].Individuals.FitAvg()
 				}
-				migrator.Apply(pops, rng)
+				if mig, ok := migrator.(FallibleMigrator); ok {
+					if err := mig.TryApply(pops, rng); err != nil {
+						t.Fatalf("Expected nil, got %v", err)
+					}
+				} else {
+					migrator.Apply(pops, rng)
+				}
 				// Check the Population sizes haven't changed
 				for _, pop := range pops {
 					if len(pop.Individuals) != int(popSize) {
@@ -32,7 +53,7 @@ func TestMigSizes(t *testing.T) {
 				// Check the average fitnesses have changed
 				for i, pop := range pops {
 					if pop.Individuals.FitAvg() == fitnessMeans[i] {
-						t.Error("Average fitnesses didn't change")
+						t.Errorf("%+v: average fitnesses didn't change", migrator)
 					}
 				}
 			}
@@ -51,3 +72,190 @@ func TestMigRingValidate(t *testing.T) {
 		t.Error("Validation should raised error")
 	}
 }
+
+func TestTopologies(t *testing.T) {
+	var testCases = []struct {
+		topo Topology
+		n    int
+		adj  [][]int
+	}{
+		{TopoRing{}, 3, [][]int{{1}, {2}, {0}}},
+		{TopoRing{Bidirectional: true}, 3, [][]int{{1, 2}, {2, 0}, {0, 1}}},
+		{TopoRing{Bidirectional: true}, 2, [][]int{{1}, {0}}},
+		{TopoFullyConnected{}, 3, [][]int{{1, 2}, {0, 2}, {0, 1}}},
+		{TopoStar{Hub: 1}, 3, [][]int{{1}, {0, 2}, {1}}},
+		{TopoGrid{Rows: 2, Cols: 2}, 4, [][]int{{2, 1}, {3, 0}, {0, 3}, {1, 2}}},
+		{TopoGrid{Rows: 2, Cols: 3, Torus: true}, 6, [][]int{{3, 2, 1}, {4, 0, 2}, {5, 1, 0}, {0, 5, 4}, {1, 3, 5}, {2, 4, 3}}},
+		{TopoAdjacency{Neighbours: [][]int{{2}, {}, {0, 1}}}, 3, [][]int{{2}, {}, {0, 1}}},
+	}
+	for i, tc := range testCases {
+		if err := tc.topo.Validate(); err != nil {
+			t.Errorf("Test %d: expected nil, got %v", i, err)
+		}
+		var adj, err = tc.topo.Adjacency(tc.n, newRand())
+		if err != nil {
+			t.Errorf("Test %d: expected nil, got %v", i, err)
+		}
+		if !reflect.DeepEqual(adj, tc.adj) {
+			t.Errorf("Test %d: expected %v, got %v", i, tc.adj, adj)
+		}
+	}
+	// Random topologies
+	var adj, _ = TopoRandom{Degree: 2}.Adjacency(4, newRand())
+	for i, dests := range adj {
+		if len(dests) != 2 || dests[0] == dests[1] || dests[0] == i || dests[1] == i {
+			t.Errorf("Unexpected destinations %v for population %d", dests, i)
+		}
+	}
+	if adj, _ = (TopoRandom{Degree: 5}).Adjacency(3, newRand()); len(adj[0]) != 2 {
+		t.Errorf("Expected 2 destinations, got %v", adj[0])
+	}
+}
+
+func TestTopologiesErrors(t *testing.T) {
+	var runtime = []Topology{
+		TopoStar{Hub: 3},
+		TopoGrid{Rows: 2, Cols: 2},
+		TopoAdjacency{Neighbours: [][]int{{1}, {0}}},
+		TopoAdjacency{Neighbours: [][]int{{1}, {0}, {3}}},
+	}
+	for i, topo := range runtime {
+		if _, err := topo.Adjacency(3, newRand()); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+	var invalid = []Topology{
+		TopoGrid{Rows: 2},
+		TopoRandom{},
+		TopoAdjacency{Neighbours: [][]int{{0}}},
+		TopoAdjacency{Neighbours: [][]int{{1, 1}, {}}},
+	}
+	for i, topo := range invalid {
+		if err := topo.Validate(); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+}
+
+// newLinePopulations returns Populations whose individuals have the given
+// fitnesses.
+func newLinePopulations(xs ...[]float64) Populations {
+	var pops = make(Populations, len(xs))
+	for i, x := range xs {
+		pops[i] = Population{Individuals: newLineIndividuals(x...), RNG: newRand()}
+		pops[i].Individuals.Evaluate(false)
+	}
+	return pops
+}
+
+func TestMigTopologyCopy(t *testing.T) {
+	var (
+		pops = newLinePopulations([]float64{0, 1, 2}, []float64{10, 11, 12}, []float64{20, 21, 22})
+		mig  = MigTopology{Topology: TopoRing{}, NMigrants: 1, Emigrants: SelElitism{}, Replacement: SelElitism{}}
+	)
+	if err := mig.TryApply(pops, newRand()); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	// The best individual of each population replaces the worst individual of
+	// the next population, the emigrants being chosen before any replacement
+	var expected = [][]float64{{0, 1, 20}, {0, 10, 11}, {10, 20, 21}}
+	for i, pop := range pops {
+		var fits = pop.Individuals.getFitnesses()
+		sort.Float64s(fits)
+		if !reflect.DeepEqual(fits, expected[i]) {
+			t.Errorf("Expected %v, got %v", expected[i], fits)
+		}
+	}
+	var ids = make(map[string]bool)
+	for _, pop := range pops {
+		for _, indi := range pop.Individuals {
+			if ids[indi.ID] {
+				t.Error("The emigrants should be cloned")
+			}
+			ids[indi.ID] = true
+		}
+	}
+}
+
+func TestMigTopologySwap(t *testing.T) {
+	var (
+		pops = newLinePopulations([]float64{0, 1, 2, 3}, []float64{10, 11, 12, 13}, []float64{20, 21, 22, 23})
+		mig  = MigTopology{Topology: TopoFullyConnected{}, NMigrants: 2, Swap: true}
+		ids  = make(map[string]bool)
+	)
+	for _, pop := range pops {
+		for _, indi := range pop.Individuals {
+			ids[indi.ID] = true
+		}
+	}
+	if err := mig.TryApply(pops, newRand()); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	// The individuals are exchanged, none is created nor lost
+	var after = make(map[string]bool)
+	for _, pop := range pops {
+		if len(pop.Individuals) != 4 {
+			t.Errorf("Expected 4 individuals, got %d", len(pop.Individuals))
+		}
+		for _, indi := range pop.Individuals {
+			after[indi.ID] = true
+		}
+	}
+	if !reflect.DeepEqual(ids, after) {
+		t.Error("Swapping should conserve the individuals")
+	}
+	// A single exchange between two populations
+	pops = newLinePopulations([]float64{0, 1, 2}, []float64{10, 11, 12})
+	mig = MigTopology{Topology: TopoAdjacency{Neighbours: [][]int{{1}, {}}}, NMigrants: 1, Emigrants: SelElitism{}, Replacement: SelElitism{}, Swap: true}
+	if err := mig.TryApply(pops, newRand()); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	var a, b = pops[0].Individuals.getFitnesses(), pops[1].Individuals.getFitnesses()
+	sort.Float64s(a)
+	sort.Float64s(b)
+	if !reflect.DeepEqual(a, []float64{1, 2, 12}) || !reflect.DeepEqual(b, []float64{0, 10, 11}) {
+		t.Errorf("Unexpected populations %v and %v", a, b)
+	}
+}
+
+func TestMigTopologyErrors(t *testing.T) {
+	var invalid = []MigTopology{
+		{NMigrants: 1},
+		{Topology: TopoRandom{}, NMigrants: 1},
+		{Topology: TopoRing{}},
+		{Topology: TopoRing{}, NMigrants: 1, Emigrants: SelTournament{}},
+		{Topology: TopoRing{}, NMigrants: 1, Replacement: SelTournament{}},
+	}
+	for i, mig := range invalid {
+		if err := mig.Validate(); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+	var runtime = []MigTopology{
+		{Topology: TopoStar{Hub: 5}, NMigrants: 1},
+		{Topology: TopoRing{}, NMigrants: 1, Emigrants: SelTournament{NContestants: 10}},
+		{Topology: TopoRing{}, NMigrants: 1, Replacement: SelTournament{NContestants: 10}},
+		{Topology: TopoRing{}, NMigrants: 1, Emigrants: SelTournament{NContestants: 10}, Swap: true},
+		{Topology: TopoRing{}, NMigrants: 1, Replacement: SelTournament{NContestants: 10}, Swap: true},
+	}
+	for i, mig := range runtime {
+		var pops = newLinePopulations([]float64{0, 1, 2}, []float64{10, 11, 12})
+		if err := mig.TryApply(pops, newRand()); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+		// Apply ignores the error
+		mig.Apply(pops, newRand())
+	}
+	// The GA returns migration errors
+	var conf = NewDefaultGAConfig()
+	conf.NPops = 3
+	conf.Migrator = MigTopology{Topology: TopoGrid{Rows: 2, Cols: 2}, NMigrants: 1}
+	conf.MigFrequency = 1
+	var ga, err = conf.NewGA()
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if err = ga.Minimize(NewVector); err == nil {
+		t.Error("Expected error")
+	}
+}
@@ -64,7 +64,7 @@ func (mod ModGenerational) Apply(pop *Population) error {
 	var offsprings, err = generateOffsprings(
 		uint(len(pop.Individuals)),
 		pop.Individuals,
-		mod.Selector,
+		selectorFor(mod.Selector, pop.Run),
 		mod.CrossRate,
 		pop.RNG,
 	)
@@ -112,7 +112,7 @@ type ModSteadyState struct {
 
 // Apply ModSteadyState.
 func (mod ModSteadyState) Apply(pop *Population) error {
-	var selected, indexes, err = mod.Selector.Apply(2, pop.Individuals, pop.RNG)
+	var selected, indexes, err = selectorFor(mod.Selector, pop.Run).Apply(2, pop.Individuals, pop.RNG)
 	if err != nil {
 		return err
 	}
@@ -131,11 +131,7 @@ func (mod ModSteadyState) Apply(pop *Population) error {
 	}
 	if mod.KeepBest {
 		// Replace the chosen individuals with the best individuals
-		err = offsprings[0].Evaluate()
-		if err != nil {
-			return err
-		}
-		err = offsprings[1].Evaluate()
+		err = pop.evaluateIndividuals(offsprings, false)
 		if err != nil {
 			return err
 		}
@@ -187,7 +183,7 @@ func (mod ModDownToSize) Apply(pop *Population) error {
 	var offsprings, err = generateOffsprings(
 		mod.NOffsprings,
 		pop.Individuals,
-		mod.SelectorA,
+		selectorFor(mod.SelectorA, pop.Run),
 		mod.CrossRate,
 		pop.RNG,
 	)
@@ -198,14 +194,14 @@ func (mod ModDownToSize) Apply(pop *Population) error {
 	if mod.MutRate > 0 {
 		offsprings.Mutate(mod.MutRate, pop.RNG)
 	}
-	err = offsprings.Evaluate(false)
+	err = pop.evaluateIndividuals(offsprings, false)
 	if err != nil {
 		return err
 	}
 	// Merge the current population with the offsprings
 	offsprings = append(offsprings, pop.Individuals...)
 	// Select down to size
-	var selected, _, _ = mod.SelectorB.Apply(uint(len(pop.Individuals)), offsprings, pop.RNG)
+	var selected, _, _ = selectorFor(mod.SelectorB, pop.Run).Apply(uint(len(pop.Individuals)), offsprings, pop.RNG)
 	// Replace the current population of individuals
 	copy(pop.Individuals, selected)
 	return nil
@@ -265,18 +261,14 @@ func (mod ModRing) Apply(pop *Population) error {
 				neighbour.Mutate(pop.RNG)
 			}
 		}
-		err := indi.Evaluate()
-		if err != nil {
-			return err
-		}
-		err = neighbour.Evaluate()
-		if err != nil {
-			return err
-		}
 		// Select an individual out of the original individual and the
 		// offsprings
 		indis := Individuals{pop.Individuals[i], indi, neighbour}
-		selected, _, err := mod.Selector.Apply(1, indis, pop.RNG)
+		err := pop.evaluateIndividuals(indis, false)
+		if err != nil {
+			return err
+		}
+		selected, _, err := selectorFor(mod.Selector, pop.Run).Apply(1, indis, pop.RNG)
 		if err != nil {
 			return err
 		}
@@ -315,7 +307,7 @@ func (mod ModMutationOnly) Apply(pop *Population) error {
 	for i, indi := range pop.Individuals {
 		var mutant = indi.Clone(pop.RNG)
 		mutant.Mutate(pop.RNG)
-		err := mutant.Evaluate()
+		err := pop.evaluate(&mutant)
 		if err != nil {
 			return err
 		}
@@ -340,9 +332,12 @@ type SAAcceptance func(gen, nGen uint, e0, e1 float64) float64
 // annealing.  All individuals are mutated but only conditionally replace their
 // parent.  If the mutation is favorable it always replaces its parent.  If the
 // mutation is unfavorable, it is more likely to replace its parent earlier
-// than later in the evolution.
+// than later in the evolution.  The progress of the evolution is read from the
+// RunContext of the Population.
 type ModSimulatedAnnealing struct {
-	GA     *GA          // Pointer to the encompassing GA, set by NewGA and needed to gauge progress toward completion
+	// Deprecated: the progress is now obtained from the Population's Run
+	// field. GA is only used for Populations that don't have a RunContext.
+	GA     *GA
 	Accept SAAcceptance // Badness acceptance function
 }
 
@@ -352,18 +347,20 @@ func (mod ModSimulatedAnnealing) Apply(pop *Population) error {
 		// Mutate the individual.
 		var mutant = indi.Clone(pop.RNG)
 		mutant.Mutate(pop.RNG)
-		err := mutant.Evaluate()
+		err := pop.evaluate(&mutant)
 		if err != nil {
 			return err
 		}
 
 		// Decide whether to keep the original or its mutation
 		prob := 1.0
-		if mutant.Fitness > indi.Fitness && mod.GA != nil {
-			prob = mod.Accept(mod.GA.Generations,
-				mod.GA.GAConfig.NGenerations,
-				indi.Fitness,
-				mutant.Fitness)
+		if mutant.Fitness > indi.Fitness {
+			switch {
+			case pop.Run != nil:
+				prob = mod.Accept(pop.Run.Generation, pop.Run.NGenerations, indi.Fitness, mutant.Fitness)
+			case mod.GA != nil:
+				prob = mod.Accept(mod.GA.Generations, mod.GA.GAConfig.NGenerations, indi.Fitness, mutant.Fitness)
+			}
 		}
 		if prob > pop.RNG.Float64() {
 			pop.Individuals[i] = mutant
@@ -374,9 +371,6 @@ func (mod ModSimulatedAnnealing) Apply(pop *Population) error {
 
 // Validate ModSimulatedAnnealing fields.
 func (mod ModSimulatedAnnealing) Validate() error {
-	// Ideally, we would check that GA is not nil.  Unfortunately, Validate
-	// may be called before NewGA has a chance to initialize that field so
-	// all we can check is the Accept field.
 	if mod.Accept == nil {
 		return errors.New("an Accept function must be provided to ModSimulatedAnnealing")
 	}
@@ -1,6 +1,8 @@
 package eaopt
 
 import (
+	"fmt"
+	"math"
 	"math/rand"
 )
 
@@ -19,6 +21,137 @@ func MutNormalFloat64(genome []float64, rate float64, rng *rand.Rand) {
 	}
 }
 
+// MutGaussianFloat64 adds normal noise with standard deviation sigma[i] to gene
+// i with probability rate. Contrary to MutNormalFloat64 the noise doesn't
+// depend on the gene's current value. Gene i is clipped to [lower[i],
+// upper[i]].
+func MutGaussianFloat64(genome []float64, rate float64, sigma, lower, upper []float64, rng *rand.Rand) {
+	for i := range genome {
+		if rng.Float64() < rate {
+			genome[i] = clampFloat64(genome[i]+rng.NormFloat64()*sigma[i], lower[i], upper[i])
+		}
+	}
+}
+
+// MutPolynomialFloat64 applies polynomial mutation to each gene with
+// probability rate. The perturbation follows a polynomial distribution which is
+// truncated so that gene i stays in [lower[i], upper[i]]. The distribution
+// index eta controls the spread of the perturbation, large values produce
+// small perturbations.
+// Reference: https://doi.org/10.1007/978-3-540-31880-4_1
+func MutPolynomialFloat64(genome []float64, rate, eta float64, lower, upper []float64, rng *rand.Rand) {
+	for i := range genome {
+		if rng.Float64() >= rate || upper[i] <= lower[i] {
+			continue
+		}
+		var (
+			span   = upper[i] - lower[i]
+			delta1 = (genome[i] - lower[i]) / span
+			delta2 = (upper[i] - genome[i]) / span
+			u      = rng.Float64()
+			deltaq float64
+		)
+		if u < 0.5 {
+			var val = 2*u + (1-2*u)*math.Pow(1-delta1, eta+1)
+			deltaq = math.Pow(val, 1/(eta+1)) - 1
+		} else {
+			var val = 2*(1-u) + 2*(u-0.5)*math.Pow(1-delta2, eta+1)
+			deltaq = 1 - math.Pow(val, 1/(eta+1))
+		}
+		genome[i] = clampFloat64(genome[i]+deltaq*span, lower[i], upper[i])
+	}
+}
+
+// MutNonUniformFloat64 applies Michalewicz's non-uniform mutation to each gene
+// with probability rate. Gene i is moved towards lower[i] or upper[i] by a
+// random fraction of the distance separating it from the bound. The expected
+// step size shrinks as progress, which is the elapsed fraction of the search
+// (typically generation/nGenerations), goes from 0 to 1. b controls how fast
+// it shrinks, 5 is a common choice.
+func MutNonUniformFloat64(genome []float64, rate, progress, b float64, lower, upper []float64, rng *rand.Rand) {
+	var delta = func(y float64) float64 {
+		return y * (1 - math.Pow(rng.Float64(), math.Pow(1-progress, b)))
+	}
+	for i := range genome {
+		if rng.Float64() >= rate {
+			continue
+		}
+		if rng.Float64() < 0.5 {
+			genome[i] += delta(upper[i] - genome[i])
+		} else {
+			genome[i] -= delta(genome[i] - lower[i])
+		}
+		genome[i] = clampFloat64(genome[i], lower[i], upper[i])
+	}
+}
+
+// MutResetInt replaces gene i with a random int in [lower[i], upper[i]] with
+// probability rate.
+func MutResetInt(genome []int, rate float64, lower, upper []int, rng *rand.Rand) {
+	for i := range genome {
+		if rng.Float64() < rate {
+			genome[i] = lower[i] + rng.Intn(upper[i]-lower[i]+1)
+		}
+	}
+}
+
+// An IntStep samples the step used by MutCreepInt.
+type IntStep func(rng *rand.Rand) int
+
+// UniformIntStep returns an IntStep that samples a non-zero step uniformly in
+// [-max, max]. It panics if max is not positive.
+func UniformIntStep(max int) IntStep {
+	if max <= 0 {
+		panic(fmt.Sprintf("eaopt: UniformIntStep needs a positive max, got %d", max))
+	}
+	return func(rng *rand.Rand) int {
+		var step = rng.Intn(max) + 1
+		if rng.Float64() < 0.5 {
+			return -step
+		}
+		return step
+	}
+}
+
+// GeometricIntStep returns an IntStep that samples a non-zero step whose
+// absolute value follows a geometric distribution with success probability p,
+// hence small steps are more likely than large ones. The mean absolute step is
+// 1/p. It panics if p is not in (0, 1].
+func GeometricIntStep(p float64) IntStep {
+	if !(p > 0 && p <= 1) {
+		panic(fmt.Sprintf("eaopt: GeometricIntStep needs p in (0, 1], got %f", p))
+	}
+	return func(rng *rand.Rand) int {
+		var step = 1
+		if p < 1 {
+			step += int(math.Floor(math.Log(1-rng.Float64()) / math.Log1p(-p)))
+		}
+		if rng.Float64() < 0.5 {
+			return -step
+		}
+		return step
+	}
+}
+
+// NormalIntStep returns an IntStep that samples a step from a normal
+// distribution with standard deviation std and rounds it to the nearest
+// integer. The step can be 0.
+func NormalIntStep(std float64) IntStep {
+	return func(rng *rand.Rand) int {
+		return int(math.Round(rng.NormFloat64() * std))
+	}
+}
+
+// MutCreepInt adds a step sampled from step to gene i with probability rate.
+// Gene i is clipped to [lower[i], upper[i]].
+func MutCreepInt(genome []int, rate float64, step IntStep, lower, upper []int, rng *rand.Rand) {
+	for i := range genome {
+		if rng.Float64() < rate {
+			genome[i] = clampInt(genome[i]+step(rng), lower[i], upper[i])
+		}
+	}
+}
+
 // MutUniformString picks a gene at random and replaces it with a random from a
 // provided corpus. It repeats this n times.
 func MutUniformString(genome []string, corpus []string, n int, rng *rand.Rand) {
@@ -33,55 +166,323 @@ func MutUniformString(genome []string, corpus []string, n int, rng *rand.Rand) {
 
 // Generic mutations for slices
 
-// MutPermute permutes two genes at random n times.
-func MutPermute(genome Slice, n int, rng *rand.Rand) {
+// mutateBoxed applies a generic mutation to a Slice by boxing its genes. It is
+// only used for the mutations that can't be written with the Swap method.
+func mutateBoxed(genome Slice, mutate func(s []interface{})) {
+	var s = boxSlice(genome)
+	mutate(s)
+	unboxSlice(genome, s)
+}
+
+// MutPermuteOf permutes two genes at random n times.
+func MutPermuteOf[T any](genome []T, n int, rng *rand.Rand) {
 	// Nothing to permute
-	if genome.Len() <= 1 {
+	if len(genome) <= 1 {
 		return
 	}
 	for i := 0; i < n; i++ {
 		// Choose two points on the genome
+		var points = randomInts(2, 0, len(genome), rng)
+		genome[points[0]], genome[points[1]] = genome[points[1]], genome[points[0]]
+	}
+}
+
+// MutPermute permutes two genes of a Slice at random n times. It is the same
+// as MutPermuteOf but works in place through the Swap method.
+func MutPermute(genome Slice, n int, rng *rand.Rand) {
+	if genome.Len() <= 1 {
+		return
+	}
+	for i := 0; i < n; i++ {
 		var points = randomInts(2, 0, genome.Len(), rng)
 		genome.Swap(points[0], points[1])
 	}
 }
 
-// MutPermuteInt calls MutPermute on an int slice.
+// MutPermuteInt calls MutPermuteOf on an int slice.
 func MutPermuteInt(s []int, n int, rng *rand.Rand) {
-	MutPermute(IntSlice(s), n, rng)
+	MutPermuteOf(s, n, rng)
 }
 
-// MutPermuteFloat64 calls MutPermute on a float64 slice.
+// MutPermuteFloat64 calls MutPermuteOf on a float64 slice.
 func MutPermuteFloat64(s []float64, n int, rng *rand.Rand) {
-	MutPermute(Float64Slice(s), n, rng)
+	MutPermuteOf(s, n, rng)
 }
 
-// MutPermuteString callsMutPermute on a string slice.
+// MutPermuteString calls MutPermuteOf on a string slice.
 func MutPermuteString(s []string, n int, rng *rand.Rand) {
-	MutPermute(StringSlice(s), n, rng)
+	MutPermuteOf(s, n, rng)
 }
 
-// MutSplice splits a genome in 2 and glues the pieces back together in reverse
-// order.
-func MutSplice(genome Slice, rng *rand.Rand) {
+// MutSpliceOf splits a genome in 2 and glues the pieces back together in
+// reverse order.
+func MutSpliceOf[T any](genome []T, rng *rand.Rand) {
 	var (
-		k    = rng.Intn(genome.Len()-1) + 1
-		a, b = genome.Split(k)
+		k    = rng.Intn(len(genome)-1) + 1
+		head = append([]T(nil), genome[:k]...)
 	)
-	genome.Replace(b.Append(a))
+	copy(genome, genome[k:])
+	copy(genome[len(genome)-k:], head)
 }
 
-// MutSpliceInt calls MutSplice on an int slice.
+// MutSplice calls MutSpliceOf on a Slice.
+func MutSplice(genome Slice, rng *rand.Rand) {
+	mutateBoxed(genome, func(s []interface{}) { MutSpliceOf(s, rng) })
+}
+
+// MutSpliceInt calls MutSpliceOf on an int slice.
 func MutSpliceInt(s []int, rng *rand.Rand) {
-	MutSplice(IntSlice(s), rng)
+	MutSpliceOf(s, rng)
 }
 
-// MutSpliceFloat64 calls MutSplice on a float64 slice.
+// MutSpliceFloat64 calls MutSpliceOf on a float64 slice.
 func MutSpliceFloat64(s []float64, rng *rand.Rand) {
-	MutSplice(Float64Slice(s), rng)
+	MutSpliceOf(s, rng)
 }
 
-// MutSpliceString calls MutSplice on a string slice.
+// MutSpliceString calls MutSpliceOf on a string slice.
 func MutSpliceString(s []string, rng *rand.Rand) {
-	MutSplice(StringSlice(s), rng)
+	MutSpliceOf(s, rng)
+}
+
+// reverseSlice reverses the genes of a slice between indexes a and b
+// (inclusive).
+func reverseSlice[T any](genome []T, a, b int) {
+	for ; a < b; a, b = a+1, b-1 {
+		genome[a], genome[b] = genome[b], genome[a]
+	}
+}
+
+// reverseSwap reverses the genes of a Slice between indexes a and b
+// (inclusive).
+func reverseSwap(genome Slice, a, b int) {
+	for ; a < b; a, b = a+1, b-1 {
+		genome.Swap(a, b)
+	}
+}
+
+// randomSegment returns two indexes a < b.
+func randomSegment(n int, rng *rand.Rand) (int, int) {
+	var points = randomInts(2, 0, n, rng)
+	if points[0] > points[1] {
+		return points[1], points[0]
+	}
+	return points[0], points[1]
+}
+
+// MutInversionOf reverses the order of the genes contained in a random
+// segment.
+func MutInversionOf[T any](genome []T, rng *rand.Rand) {
+	if len(genome) <= 1 {
+		return
+	}
+	var a, b = randomSegment(len(genome), rng)
+	reverseSlice(genome, a, b)
+}
+
+// MutInversion reverses the order of the genes of a Slice contained in a
+// random segment. It is the same as MutInversionOf but works in place through
+// the Swap method.
+func MutInversion(genome Slice, rng *rand.Rand) {
+	if genome.Len() <= 1 {
+		return
+	}
+	var a, b = randomSegment(genome.Len(), rng)
+	reverseSwap(genome, a, b)
+}
+
+// MutInversionInt calls MutInversionOf on an int slice.
+func MutInversionInt(s []int, rng *rand.Rand) {
+	MutInversionOf(s, rng)
+}
+
+// MutInversionFloat64 calls MutInversionOf on a float64 slice.
+func MutInversionFloat64(s []float64, rng *rand.Rand) {
+	MutInversionOf(s, rng)
+}
+
+// MutInversionString calls MutInversionOf on a string slice.
+func MutInversionString(s []string, rng *rand.Rand) {
+	MutInversionOf(s, rng)
+}
+
+// MutScrambleOf shuffles the genes contained in a random segment.
+func MutScrambleOf[T any](genome []T, rng *rand.Rand) {
+	if len(genome) <= 1 {
+		return
+	}
+	var a, b = randomSegment(len(genome), rng)
+	for i := b; i > a; i-- {
+		var j = a + rng.Intn(i-a+1)
+		genome[i], genome[j] = genome[j], genome[i]
+	}
+}
+
+// MutScramble shuffles the genes of a Slice contained in a random segment. It
+// is the same as MutScrambleOf but works in place through the Swap method.
+func MutScramble(genome Slice, rng *rand.Rand) {
+	if genome.Len() <= 1 {
+		return
+	}
+	var a, b = randomSegment(genome.Len(), rng)
+	for i := b; i > a; i-- {
+		genome.Swap(i, a+rng.Intn(i-a+1))
+	}
+}
+
+// MutScrambleInt calls MutScrambleOf on an int slice.
+func MutScrambleInt(s []int, rng *rand.Rand) {
+	MutScrambleOf(s, rng)
+}
+
+// MutScrambleFloat64 calls MutScrambleOf on a float64 slice.
+func MutScrambleFloat64(s []float64, rng *rand.Rand) {
+	MutScrambleOf(s, rng)
+}
+
+// MutScrambleString calls MutScrambleOf on a string slice.
+func MutScrambleString(s []string, rng *rand.Rand) {
+	MutScrambleOf(s, rng)
+}
+
+// MutInsertionOf removes a random gene and inserts it back at another random
+// position, the genes in between are shifted by one position.
+func MutInsertionOf[T any](genome []T, rng *rand.Rand) {
+	if len(genome) <= 1 {
+		return
+	}
+	var (
+		points = randomInts(2, 0, len(genome), rng)
+		from   = points[0]
+		to     = points[1]
+		gene   = genome[from]
+	)
+	if from < to {
+		copy(genome[from:to], genome[from+1:to+1])
+	} else {
+		copy(genome[to+1:from+1], genome[to:from])
+	}
+	genome[to] = gene
+}
+
+// MutInsertion removes a random gene of a Slice and inserts it back at
+// another random position. It is the same as MutInsertionOf but shifts the
+// genes in between through the Swap method.
+func MutInsertion(genome Slice, rng *rand.Rand) {
+	if genome.Len() <= 1 {
+		return
+	}
+	var points = randomInts(2, 0, genome.Len(), rng)
+	if points[0] < points[1] {
+		for i := points[0]; i < points[1]; i++ {
+			genome.Swap(i, i+1)
+		}
+	} else {
+		for i := points[0]; i > points[1]; i-- {
+			genome.Swap(i, i-1)
+		}
+	}
+}
+
+// MutInsertionInt calls MutInsertionOf on an int slice.
+func MutInsertionInt(s []int, rng *rand.Rand) {
+	MutInsertionOf(s, rng)
+}
+
+// MutInsertionFloat64 calls MutInsertionOf on a float64 slice.
+func MutInsertionFloat64(s []float64, rng *rand.Rand) {
+	MutInsertionOf(s, rng)
+}
+
+// MutInsertionString calls MutInsertionOf on a string slice.
+func MutInsertionString(s []string, rng *rand.Rand) {
+	MutInsertionOf(s, rng)
+}
+
+// MutDisplacementOf removes a random segment and inserts it back at a random
+// position.
+func MutDisplacementOf[T any](genome []T, rng *rand.Rand) {
+	var n = len(genome)
+	if n <= 1 {
+		return
+	}
+	var (
+		a, b    = randomSegment(n, rng)
+		segment = append([]T(nil), genome[a:b+1]...)
+		rest    = append(append([]T(nil), genome[:a]...), genome[b+1:]...)
+		k       = rng.Intn(len(rest) + 1)
+	)
+	copy(genome, rest[:k])
+	copy(genome[k:], segment)
+	copy(genome[k+len(segment):], rest[k:])
+}
+
+// MutDisplacement calls MutDisplacementOf on a Slice.
+func MutDisplacement(genome Slice, rng *rand.Rand) {
+	mutateBoxed(genome, func(s []interface{}) { MutDisplacementOf(s, rng) })
+}
+
+// MutDisplacementInt calls MutDisplacementOf on an int slice.
+func MutDisplacementInt(s []int, rng *rand.Rand) {
+	MutDisplacementOf(s, rng)
+}
+
+// MutDisplacementFloat64 calls MutDisplacementOf on a float64 slice.
+func MutDisplacementFloat64(s []float64, rng *rand.Rand) {
+	MutDisplacementOf(s, rng)
+}
+
+// MutDisplacementString calls MutDisplacementOf on a string slice.
+func MutDisplacementString(s []string, rng *rand.Rand) {
+	MutDisplacementOf(s, rng)
+}
+
+// MutTwoOptOf applies a random 2-opt move to a genome that represents a tour,
+// meaning that the last gene is considered to be connected to the first one.
+// Two edges of the tour are removed and the path between them is reversed,
+// which may wrap around the end of the genome.
+func MutTwoOptOf[T any](genome []T, rng *rand.Rand) {
+	var n = len(genome)
+	if n <= 3 {
+		return
+	}
+	var (
+		start  = rng.Intn(n)
+		length = 2 + rng.Intn(n-3) // Reversing n-1 or n genes yields the same tour
+	)
+	for i, j := 0, length-1; i < j; i, j = i+1, j-1 {
+		var a, b = (start + i) % n, (start + j) % n
+		genome[a], genome[b] = genome[b], genome[a]
+	}
+}
+
+// MutTwoOpt applies a random 2-opt move to a Slice that represents a tour. It
+// is the same as MutTwoOptOf but works in place through the Swap method.
+func MutTwoOpt(genome Slice, rng *rand.Rand) {
+	var n = genome.Len()
+	if n <= 3 {
+		return
+	}
+	var (
+		start  = rng.Intn(n)
+		length = 2 + rng.Intn(n-3)
+	)
+	for i, j := 0, length-1; i < j; i, j = i+1, j-1 {
+		genome.Swap((start+i)%n, (start+j)%n)
+	}
+}
+
+// MutTwoOptInt calls MutTwoOptOf on an int slice.
+func MutTwoOptInt(s []int, rng *rand.Rand) {
+	MutTwoOptOf(s, rng)
+}
+
+// MutTwoOptFloat64 calls MutTwoOptOf on a float64 slice.
+func MutTwoOptFloat64(s []float64, rng *rand.Rand) {
+	MutTwoOptOf(s, rng)
+}
+
+// MutTwoOptString calls MutTwoOptOf on a string slice.
+func MutTwoOptString(s []string, rng *rand.Rand) {
+	MutTwoOptOf(s, rng)
 }
@@ -1,6 +1,9 @@
 package eaopt
 
 import (
+	"fmt"
+	"math"
+	"math/rand"
 	"testing"
 )
 
@@ -229,3 +232,255 @@ func TestMutSpliceString(t *testing.T) {
 		}
 	}
 }
+
+func TestRealCodedMutations(t *testing.T) {
+	var (
+		rng   = newRand()
+		lower = []float64{-1, 0, -5}
+		upper = []float64{1, 1, 5}
+	)
+	var testCases = []func(x []float64, rate float64){
+		func(x []float64, rate float64) {
+			MutGaussianFloat64(x, rate, []float64{1, 1, 1}, lower, upper, rng)
+		},
+		func(x []float64, rate float64) { MutPolynomialFloat64(x, rate, 20, lower, upper, rng) },
+		func(x []float64, rate float64) { MutNonUniformFloat64(x, rate, 0.5, 5, lower, upper, rng) },
+	}
+	for i, mutate := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			// Genes at 0 can move
+			var x = []float64{0, 0, 0}
+			mutate(x, 1)
+			if x[0] == 0 && x[2] == 0 {
+				t.Errorf("Genes should have been modified, got %v", x)
+			}
+			for j := 0; j < 100; j++ {
+				mutate(x, 1)
+				checkBoundsFloat64(t, x, lower, upper)
+			}
+			var y = []float64{0.5, 0.5, 0.5}
+			mutate(y, 0)
+			if y[0] != 0.5 || y[1] != 0.5 || y[2] != 0.5 {
+				t.Errorf("Genes shouldn't have been modified, got %v", y)
+			}
+		})
+	}
+}
+
+func TestMutNonUniformFloat64Progress(t *testing.T) {
+	var (
+		rng          = newRand()
+		lower, upper = []float64{-10}, []float64{10}
+		step         = func(progress float64) (s float64) {
+			for i := 0; i < 1000; i++ {
+				var x = []float64{0}
+				MutNonUniformFloat64(x, 1, progress, 5, lower, upper, rng)
+				s += math.Abs(x[0]) / 1000
+			}
+			return
+		}
+	)
+	if step(0) <= step(0.9) {
+		t.Error("Expected steps to shrink as the search progresses")
+	}
+	var x = []float64{3}
+	MutNonUniformFloat64(x, 1, 1, 5, lower, upper, rng)
+	if x[0] != 3 {
+		t.Errorf("Expected no step at the end of the search, got %f", x[0])
+	}
+}
+
+func TestMutResetInt(t *testing.T) {
+	var (
+		rng          = newRand()
+		lower, upper = []int{0, 10}, []int{2, 20}
+		genome       = []int{1, 15}
+	)
+	MutResetInt(genome, 0, lower, upper, rng)
+	if genome[0] != 1 || genome[1] != 15 {
+		t.Errorf("Genes shouldn't have been modified, got %v", genome)
+	}
+	for i := 0; i < 100; i++ {
+		MutResetInt(genome, 1, lower, upper, rng)
+		if genome[0] < 0 || genome[0] > 2 || genome[1] < 10 || genome[1] > 20 {
+			t.Errorf("Genes are out of bounds: %v", genome)
+		}
+	}
+}
+
+func TestMutCreepInt(t *testing.T) {
+	var (
+		rng          = newRand()
+		lower, upper = []int{-100}, []int{100}
+	)
+	var testCases = []struct {
+		step    IntStep
+		maxStep int
+		nonZero bool
+	}{
+		{UniformIntStep(2), 2, true},
+		{GeometricIntStep(1), 1, true},
+		{GeometricIntStep(0.5), 100, true},
+		{NormalIntStep(1), 100, false},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			for j := 0; j < 100; j++ {
+				var genome = []int{0}
+				MutCreepInt(genome, 1, tc.step, lower, upper, rng)
+				if tc.nonZero && genome[0] == 0 {
+					t.Error("Expected a non-zero step")
+				}
+				if genome[0] > tc.maxStep || genome[0] < -tc.maxStep {
+					t.Errorf("Step %d is too large", genome[0])
+				}
+			}
+		})
+	}
+	// Genes are clipped
+	var genome = []int{99}
+	MutCreepInt(genome, 1, UniformIntStep(1000), lower, upper, rng)
+	if genome[0] < -100 || genome[0] > 100 {
+		t.Errorf("Gene %d is out of bounds", genome[0])
+	}
+}
+
+func TestIntStepPanics(t *testing.T) {
+	for i, newStep := range []func(){
+		func() { UniformIntStep(0) },
+		func() { UniformIntStep(-1) },
+		func() { GeometricIntStep(0) },
+		func() { GeometricIntStep(1.5) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Test %d: expected a panic", i)
+				}
+			}()
+			newStep()
+		}()
+	}
+}
+
+func TestPermutationMutations(t *testing.T) {
+	var rng = newRand()
+	var testCases = []func(s []int){
+		func(s []int) { MutInversionInt(s, rng) },
+		func(s []int) { MutScrambleInt(s, rng) },
+		func(s []int) { MutInsertionInt(s, rng) },
+		func(s []int) { MutDisplacementInt(s, rng) },
+		func(s []int) { MutTwoOptInt(s, rng) },
+	}
+	for i, mutate := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var changed bool
+			for j := 0; j < 20; j++ {
+				var (
+					genome  = []int{0, 1, 2, 3, 4, 5, 6, 7}
+					mutated = []int{0, 1, 2, 3, 4, 5, 6, 7}
+				)
+				mutate(mutated)
+				if !isPermutation(mutated, genome) {
+					t.Fatalf("%v is not a permutation of %v", mutated, genome)
+				}
+				changed = changed || fmt.Sprint(mutated) != fmt.Sprint(genome)
+			}
+			if !changed {
+				t.Error("Mutation never modified the genome")
+			}
+			// Short genomes are left untouched
+			var short = []int{1}
+			mutate(short)
+			if short[0] != 1 {
+				t.Error("Single gene genome should not be modified")
+			}
+		})
+	}
+	// Check the typed wrappers
+	var (
+		floats  = []float64{1, 2, 3, 4, 5}
+		strings = []string{"a", "b", "c", "d", "e"}
+	)
+	MutInversionFloat64(floats, rng)
+	MutScrambleFloat64(floats, rng)
+	MutInsertionFloat64(floats, rng)
+	MutDisplacementFloat64(floats, rng)
+	MutTwoOptFloat64(floats, rng)
+	MutInversionString(strings, rng)
+	MutScrambleString(strings, rng)
+	MutInsertionString(strings, rng)
+	MutDisplacementString(strings, rng)
+	MutTwoOptString(strings, rng)
+	if len(floats) != 5 || len(strings) != 5 {
+		t.Error("Mutations should not change the length of the genome")
+	}
+}
+
+func TestSliceMutationsMatchGeneric(t *testing.T) {
+	// The Slice versions work through the Swap method but should produce the
+	// same genomes as the generic versions
+	var testCases = []struct {
+		slice   func(s Slice, rng *rand.Rand)
+		generic func(s []int, rng *rand.Rand)
+	}{
+		{func(s Slice, rng *rand.Rand) { MutPermute(s, 3, rng) }, func(s []int, rng *rand.Rand) { MutPermuteOf(s, 3, rng) }},
+		{MutInversion, MutInversionOf[int]},
+		{MutScramble, MutScrambleOf[int]},
+		{MutInsertion, MutInsertionOf[int]},
+		{MutTwoOpt, MutTwoOptOf[int]},
+	}
+	for i, tc := range testCases {
+		for seed := int64(0); seed < 20; seed++ {
+			var (
+				s1 = []int{0, 1, 2, 3, 4, 5, 6, 7}
+				s2 = []int{0, 1, 2, 3, 4, 5, 6, 7}
+			)
+			tc.slice(IntSlice(s1), rand.New(rand.NewSource(seed)))
+			tc.generic(s2, rand.New(rand.NewSource(seed)))
+			if fmt.Sprint(s1) != fmt.Sprint(s2) {
+				t.Errorf("TC %d: expected %v, got %v", i, s2, s1)
+			}
+		}
+	}
+}
+
+func TestMutInsertion(t *testing.T) {
+	// Exactly one gene is moved
+	var rng = newRand()
+	for i := 0; i < 20; i++ {
+		var genome = []int{0, 1, 2, 3, 4, 5}
+		MutInsertionInt(genome, rng)
+		var displaced int
+		for j := 1; j < len(genome); j++ {
+			if genome[j]-genome[j-1] != 1 {
+				displaced++
+			}
+		}
+		if displaced > 3 {
+			t.Errorf("More than one gene was moved in %v", genome)
+		}
+	}
+}
+
+func TestMutTwoOpt(t *testing.T) {
+	// A 2-opt move changes exactly 2 edges of the tour
+	var rng = newRand()
+	for i := 0; i < 50; i++ {
+		var (
+			genome = []int{0, 1, 2, 3, 4, 5, 6, 7}
+			n      = len(genome)
+		)
+		MutTwoOptInt(genome, rng)
+		var g = newTourGraph(genome)
+		var broken int
+		for c := 0; c < n; c++ {
+			if !g.has(c, (c+1)%n) {
+				broken++
+			}
+		}
+		if broken != 2 {
+			t.Errorf("Expected 2 broken edges, got %d in %v", broken, genome)
+		}
+	}
+}
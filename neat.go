@@ -0,0 +1,715 @@
+package eaopt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// A NEATNodeType indicates the role of a node in a NEAT network.
+type NEATNodeType int
+
+// Types of NEAT nodes.
+const (
+	NEATInput NEATNodeType = iota
+	NEATBias
+	NEATOutput
+	NEATHidden
+)
+
+// A NEATNode is a node gene.
+type NEATNode struct {
+	ID   int
+	Type NEATNodeType
+}
+
+// A NEATConn is a connection gene. The innovation number identifies the
+// structural mutation that created the connection, which makes it possible to
+// align the genes of two genomes.
+type NEATConn struct {
+	In, Out    int
+	Weight     float64
+	Enabled    bool
+	Innovation int
+}
+
+// A NEATGenome is a Genome made of node and connection genes that encodes a
+// neural network. The connection genes are sorted by innovation number.
+type NEATGenome struct {
+	Nodes   []NEATNode
+	Conns   []NEATConn
+	Fitness float64 // Set by Evaluate, used by Crossover to determine the fitter parent
+	NEAT    *NEAT
+}
+
+// Evaluate a NEATGenome with the NEAT's Fitness function.
+func (g *NEATGenome) Evaluate() (float64, error) {
+	var fitness, err = g.NEAT.Fitness(g)
+	g.Fitness = fitness
+	return fitness, err
+}
+
+// Mutate a NEATGenome by adding a node with probability NEAT.AddNodeRate,
+// adding a connection with probability NEAT.AddConnRate and mutating the
+// weights.
+func (g *NEATGenome) Mutate(rng *rand.Rand) {
+	if rng.Float64() < g.NEAT.AddNodeRate {
+		g.MutAddNode(rng)
+	}
+	if rng.Float64() < g.NEAT.AddConnRate {
+		g.MutAddConn(rng)
+	}
+	g.MutWeights(rng)
+}
+
+// MutWeights modifies each weight with probability NEAT.WeightMutRate. A
+// modified weight is either replaced with a new random weight, with
+// probability NEAT.WeightResetRate, or perturbed with Gaussian noise of
+// standard deviation NEAT.WeightStd.
+func (g *NEATGenome) MutWeights(rng *rand.Rand) {
+	for i := range g.Conns {
+		if rng.Float64() >= g.NEAT.WeightMutRate {
+			continue
+		}
+		if rng.Float64() < g.NEAT.WeightResetRate {
+			g.Conns[i].Weight = g.NEAT.newWeight(rng)
+		} else {
+			g.Conns[i].Weight += rng.NormFloat64() * g.NEAT.WeightStd
+		}
+	}
+}
+
+// hasNode checks if a NEATGenome contains a node.
+func (g *NEATGenome) hasNode(id int) bool {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// MutAddNode splits a random enabled connection in two. The connection is
+// disabled and replaced by a new node, a connection of weight 1 leading into
+// the new node and a connection with the old weight leading out of it.
+func (g *NEATGenome) MutAddNode(rng *rand.Rand) {
+	var enabled []int
+	for i, conn := range g.Conns {
+		if conn.Enabled {
+			enabled = append(enabled, i)
+		}
+	}
+	if len(enabled) == 0 {
+		return
+	}
+	var (
+		i    = enabled[rng.Intn(len(enabled))]
+		conn = g.Conns[i]
+		id   = g.NEAT.splitNode(conn.Innovation, g.hasNode)
+	)
+	g.Conns[i].Enabled = false
+	g.Nodes = append(g.Nodes, NEATNode{ID: id, Type: NEATHidden})
+	g.addConn(conn.In, id, 1)
+	g.addConn(id, conn.Out, conn.Weight)
+}
+
+// addConn adds an enabled connection and keeps the genes sorted.
+func (g *NEATGenome) addConn(in, out int, weight float64) {
+	g.Conns = append(g.Conns, NEATConn{
+		In:         in,
+		Out:        out,
+		Weight:     weight,
+		Enabled:    true,
+		Innovation: g.NEAT.innovation(in, out),
+	})
+	sort.Slice(g.Conns, func(i, j int) bool { return g.Conns[i].Innovation < g.Conns[j].Innovation })
+}
+
+// reaches checks if there is a path from node a to node b.
+func (g *NEATGenome) reaches(a, b int) bool {
+	var (
+		visited = map[int]bool{a: true}
+		stack   = []int{a}
+	)
+	for len(stack) > 0 {
+		var n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n == b {
+			return true
+		}
+		for _, conn := range g.Conns {
+			if conn.In == n && !visited[conn.Out] {
+				visited[conn.Out] = true
+				stack = append(stack, conn.Out)
+			}
+		}
+	}
+	return false
+}
+
+// MutAddConn adds a connection with a random weight between two unconnected
+// nodes. Connections can't lead into input or bias nodes. If NEAT.Recurrent is
+// false then connections that would create a cycle are not allowed. Nothing
+// happens if no valid pair of nodes is found after a few attempts.
+func (g *NEATGenome) MutAddConn(rng *rand.Rand) {
+	var existing = make(map[[2]int]bool)
+	for _, conn := range g.Conns {
+		existing[[2]int{conn.In, conn.Out}] = true
+	}
+	for attempt := 0; attempt < 20; attempt++ {
+		var in, out = g.Nodes[rng.Intn(len(g.Nodes))], g.Nodes[rng.Intn(len(g.Nodes))]
+		if out.Type == NEATInput || out.Type == NEATBias || existing[[2]int{in.ID, out.ID}] {
+			continue
+		}
+		if !g.NEAT.Recurrent && (in.ID == out.ID || g.reaches(out.ID, in.ID)) {
+			continue
+		}
+		g.addConn(in.ID, out.ID, g.NEAT.newWeight(rng))
+		return
+	}
+}
+
+// Crossover a NEATGenome with another NEATGenome. Genes are aligned with their
+// innovation numbers. Matching genes are inherited at random from either
+// parent whereas disjoint and excess genes are inherited from the fitter
+// parent. A gene that is disabled in either parent is disabled with
+// probability 0.75. Both offsprings are generated in this manner.
+func (g *NEATGenome) Crossover(q Genome, rng *rand.Rand) {
+	var (
+		h           = q.(*NEATGenome)
+		fitter, oth = g, h
+	)
+	if h.Fitness < g.Fitness {
+		fitter, oth = h, g
+	}
+	var o1, o2 = neatCross(fitter, oth, rng), neatCross(fitter, oth, rng)
+	g.Nodes, g.Conns = o1.Nodes, o1.Conns
+	h.Nodes, h.Conns = o2.Nodes, o2.Conns
+}
+
+func neatCross(fitter, other *NEATGenome, rng *rand.Rand) *NEATGenome {
+	var (
+		matches   = make(map[int]NEATConn)
+		offspring = &NEATGenome{
+			Nodes: append([]NEATNode(nil), fitter.Nodes...),
+			Conns: make([]NEATConn, len(fitter.Conns)),
+			NEAT:  fitter.NEAT,
+		}
+	)
+	for _, conn := range other.Conns {
+		matches[conn.Innovation] = conn
+	}
+	for i, conn := range fitter.Conns {
+		offspring.Conns[i] = conn
+		var match, ok = matches[conn.Innovation]
+		if !ok {
+			continue
+		}
+		if rng.Float64() < 0.5 {
+			offspring.Conns[i].Weight = match.Weight
+		}
+		offspring.Conns[i].Enabled = true
+		if (!conn.Enabled || !match.Enabled) && rng.Float64() < 0.75 {
+			offspring.Conns[i].Enabled = false
+		}
+	}
+	return offspring
+}
+
+// Clone returns a deep copy of a NEATGenome.
+func (g NEATGenome) Clone() Genome {
+	return &NEATGenome{
+		Nodes:   append([]NEATNode(nil), g.Nodes...),
+		Conns:   append([]NEATConn(nil), g.Conns...),
+		Fitness: g.Fitness,
+		NEAT:    g.NEAT,
+	}
+}
+
+// String representation of a NEATGenome.
+func (g NEATGenome) String() string {
+	var s = fmt.Sprintf("%d nodes", len(g.Nodes))
+	for _, conn := range g.Conns {
+		if conn.Enabled {
+			s += fmt.Sprintf(", %d->%d (%.3f)", conn.In, conn.Out, conn.Weight)
+		}
+	}
+	return s
+}
+
+// A NEATNetwork is a neural network decoded from a NEATGenome. A feedforward
+// network is activated in topological order. A recurrent network is
+// activated one time step at a time: each activation propagates the values of
+// the previous time step through each connection once.
+type NEATNetwork struct {
+	Recurrent  bool
+	Activation func(x float64) float64
+	inputs     []int // Indexes of the input nodes
+	bias       int   // Index of the bias node
+	outputs    []int // Indexes of the output nodes
+	order      []int // Indexes of the non-input nodes in activation order
+	incoming   [][]NEATConn
+	index      map[int]int
+	values     []float64
+}
+
+// NEATSigmoid is the steepened sigmoid used in the original NEAT paper.
+func NEATSigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-4.9*x))
+}
+
+// Network decodes a NEATGenome into a NEATNetwork. The network is recurrent if
+// the enabled connections contain a cycle.
+func (g *NEATGenome) Network() *NEATNetwork {
+	var net = &NEATNetwork{
+		Activation: NEATSigmoid,
+		bias:       -1,
+		incoming:   make([][]NEATConn, len(g.Nodes)),
+		index:      make(map[int]int),
+		values:     make([]float64, len(g.Nodes)),
+	}
+	for i, node := range g.Nodes {
+		net.index[node.ID] = i
+		switch node.Type {
+		case NEATInput:
+			net.inputs = append(net.inputs, i)
+		case NEATBias:
+			net.bias = i
+		case NEATOutput:
+			net.outputs = append(net.outputs, i)
+		}
+	}
+	for _, conn := range g.Conns {
+		if conn.Enabled {
+			net.incoming[net.index[conn.Out]] = append(net.incoming[net.index[conn.Out]], conn)
+		}
+	}
+	// Sort the nodes topologically with Kahn's algorithm
+	var (
+		inDegree = make([]int, len(g.Nodes))
+		queue    []int
+	)
+	for i, conns := range net.incoming {
+		inDegree[i] = len(conns)
+	}
+	for i, node := range g.Nodes {
+		if node.Type == NEATInput || node.Type == NEATBias {
+			inDegree[i] = 0
+		}
+		if inDegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	var sorted []int
+	for len(queue) > 0 {
+		var n = queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, n)
+		for _, conn := range g.Conns {
+			var j = net.index[conn.Out]
+			if conn.Enabled && net.index[conn.In] == n && inDegree[j] > 0 {
+				if inDegree[j]--; inDegree[j] == 0 {
+					queue = append(queue, j)
+				}
+			}
+		}
+	}
+	net.Recurrent = len(sorted) < len(g.Nodes)
+	if net.Recurrent {
+		sorted = newInts(uint(len(g.Nodes)))
+	}
+	for _, i := range sorted {
+		if g.Nodes[i].Type != NEATInput && g.Nodes[i].Type != NEATBias {
+			net.order = append(net.order, i)
+		}
+	}
+	return net
+}
+
+// Reset the state of a recurrent NEATNetwork.
+func (net *NEATNetwork) Reset() {
+	for i := range net.values {
+		net.values[i] = 0
+	}
+}
+
+// Activate a NEATNetwork with the given inputs and return the values of the
+// output nodes.
+func (net *NEATNetwork) Activate(inputs []float64) []float64 {
+	for i, idx := range net.inputs {
+		net.values[idx] = inputs[i]
+	}
+	if net.bias >= 0 {
+		net.values[net.bias] = 1
+	}
+	var (
+		values = net.values
+		next   = net.values
+	)
+	if net.Recurrent {
+		next = copyFloat64s(values)
+	}
+	for _, i := range net.order {
+		var sum float64
+		for _, conn := range net.incoming[i] {
+			sum += conn.Weight * values[net.index[conn.In]]
+		}
+		next[i] = net.Activation(sum)
+	}
+	net.values = next
+	var outputs = make([]float64, len(net.outputs))
+	for i, idx := range net.outputs {
+		outputs[i] = net.values[idx]
+	}
+	return outputs
+}
+
+// NEAT contains the parameters of NeuroEvolution of Augmenting Topologies and
+// keeps track of the innovation numbers. The NewGenome method can be given to
+// GA.Minimize, preferably along with ModNEAT.
+// Reference: http://nn.cs.utexas.edu/downloads/papers/stanley.ec02.pdf
+type NEAT struct {
+	NInputs         uint
+	NOutputs        uint
+	Recurrent       bool    // Whether recurrent connections are allowed
+	WeightRange     float64 // New weights are sampled in [-WeightRange, WeightRange]
+	WeightMutRate   float64
+	WeightResetRate float64
+	WeightStd       float64
+	AddConnRate     float64
+	AddNodeRate     float64
+	C1, C2, C3      float64 // Coefficients of the excess genes, disjoint genes and weight differences in Distance
+	Fitness         func(g *NEATGenome) (float64, error)
+
+	innovations map[[2]int]int // Innovation number of each connection
+	splits      map[int]int    // Node created by splitting each connection
+	nextInnov   int
+	nextNode    int
+	mutex       sync.Mutex
+}
+
+// NewNEAT returns a NEAT with the default parameters of the original paper.
+func NewNEAT(nInputs, nOutputs uint, fitness func(g *NEATGenome) (float64, error)) *NEAT {
+	return &NEAT{
+		NInputs:         nInputs,
+		NOutputs:        nOutputs,
+		WeightRange:     1,
+		WeightMutRate:   0.8,
+		WeightResetRate: 0.1,
+		WeightStd:       0.5,
+		AddConnRate:     0.05,
+		AddNodeRate:     0.03,
+		C1:              1,
+		C2:              1,
+		C3:              0.4,
+		Fitness:         fitness,
+	}
+}
+
+func (neat *NEAT) newWeight(rng *rand.Rand) float64 {
+	return (2*rng.Float64() - 1) * neat.WeightRange
+}
+
+func (neat *NEAT) lazyInit() {
+	if neat.innovations == nil {
+		neat.innovations = make(map[[2]int]int)
+		neat.splits = make(map[int]int)
+		neat.nextNode = int(neat.NInputs + neat.NOutputs + 1)
+	}
+}
+
+// innovation returns the global innovation number of a connection.
+func (neat *NEAT) innovation(in, out int) int {
+	neat.mutex.Lock()
+	defer neat.mutex.Unlock()
+	neat.lazyInit()
+	var key = [2]int{in, out}
+	if innov, ok := neat.innovations[key]; ok {
+		return innov
+	}
+	neat.innovations[key] = neat.nextInnov
+	neat.nextInnov++
+	return neat.nextInnov - 1
+}
+
+// splitNode returns the ID of the node created by splitting a connection. The
+// same ID is returned each time the same connection is split, unless taken
+// indicates that the ID is already used.
+func (neat *NEAT) splitNode(innovation int, taken func(id int) bool) int {
+	neat.mutex.Lock()
+	defer neat.mutex.Unlock()
+	neat.lazyInit()
+	if id, ok := neat.splits[innovation]; ok && !taken(id) {
+		return id
+	}
+	var id = neat.nextNode
+	neat.nextNode++
+	neat.splits[innovation] = id
+	return id
+}
+
+// NewGenome returns a NEATGenome where each input node and the bias node are
+// connected to each output node with random weights.
+func (neat *NEAT) NewGenome(rng *rand.Rand) Genome {
+	var g = &NEATGenome{NEAT: neat, Fitness: math.Inf(1)}
+	for i := 0; i < int(neat.NInputs); i++ {
+		g.Nodes = append(g.Nodes, NEATNode{ID: i, Type: NEATInput})
+	}
+	g.Nodes = append(g.Nodes, NEATNode{ID: int(neat.NInputs), Type: NEATBias})
+	for i := 0; i < int(neat.NOutputs); i++ {
+		var out = int(neat.NInputs) + 1 + i
+		g.Nodes = append(g.Nodes, NEATNode{ID: out, Type: NEATOutput})
+		for in := 0; in <= int(neat.NInputs); in++ {
+			g.addConn(in, out, neat.newWeight(rng))
+		}
+	}
+	return g
+}
+
+// Distance returns the compatibility distance between two Individuals whose
+// Genomes are NEATGenomes. It can be used as a Metric.
+func (neat *NEAT) Distance(a, b Individual) float64 {
+	var (
+		g, h             = a.Genome.(*NEATGenome), b.Genome.(*NEATGenome)
+		i, j             int
+		excess, disjoint float64
+		weightDiff       float64
+		nMatching        float64
+	)
+	for i < len(g.Conns) && j < len(h.Conns) {
+		switch ig, ih := g.Conns[i].Innovation, h.Conns[j].Innovation; {
+		case ig == ih:
+			weightDiff += math.Abs(g.Conns[i].Weight - h.Conns[j].Weight)
+			nMatching++
+			i++
+			j++
+		case ig < ih:
+			disjoint++
+			i++
+		default:
+			disjoint++
+			j++
+		}
+	}
+	excess = float64(len(g.Conns) - i + len(h.Conns) - j)
+	var n = math.Max(float64(len(g.Conns)), float64(len(h.Conns)))
+	if n < 20 {
+		n = 1
+	}
+	var dist = neat.C1*excess/n + neat.C2*disjoint/n
+	if nMatching > 0 {
+		dist += neat.C3 * weightDiff / nMatching
+	}
+	return dist
+}
+
+// Validate NEAT fields.
+func (neat *NEAT) Validate() error {
+	if neat.NInputs == 0 || neat.NOutputs == 0 {
+		return errors.New("NInputs and NOutputs should be higher than 0")
+	}
+	for _, rate := range []float64{neat.WeightMutRate, neat.WeightResetRate, neat.AddConnRate, neat.AddNodeRate} {
+		if rate < 0 || rate > 1 {
+			return errors.New("rates should be between 0 and 1")
+		}
+	}
+	if neat.Fitness == nil {
+		return errors.New("fitness function has to be provided")
+	}
+	return nil
+}
+
+// A NEATSpecies is a group of similar individuals that persists across
+// generations.
+type NEATSpecies = Species
+
+// SpecNEAT is a Speciator that keeps track of species across generations. Each
+// individual is assigned to the first species whose representative is within
+// Threshold, else it founds a new species. Once every individual has been
+// assigned, a random member of each species becomes it's representative for
+// the next generation. SpecNEAT is stateful, it should hence be used through
+// a pointer and not be shared between Populations.
+type SpecNEAT struct {
+	Metric    Metric
+	Threshold float64
+	Species   []NEATSpecies // Current species, in the same order as returned by Apply
+
+	nextID int
+}
+
+// Apply SpecNEAT.
+func (spec *SpecNEAT) Apply(indis Individuals, rng *rand.Rand) ([]Individuals, error) {
+	for i := range spec.Species {
+		spec.Species[i].Members = nil
+	}
+	for _, indi := range indis {
+		var found bool
+		for i := range spec.Species {
+			if spec.Metric(indi, spec.Species[i].Representative) < spec.Threshold {
+				spec.Species[i].Members = append(spec.Species[i].Members, indi)
+				found = true
+				break
+			}
+		}
+		if !found {
+			spec.Species = append(spec.Species, NEATSpecies{
+				ID:             spec.nextID,
+				Representative: indi,
+				Members:        Individuals{indi},
+				BestFitness:    math.Inf(1),
+			})
+			spec.nextID++
+		}
+	}
+	// Remove the empty species and update the others
+	var (
+		alive   = spec.Species[:0]
+		species []Individuals
+	)
+	for _, s := range spec.Species {
+		if len(s.Members) == 0 {
+			continue
+		}
+		s.Representative = s.Members[rng.Intn(len(s.Members))]
+		s.record(s.Members)
+		alive = append(alive, s)
+		species = append(species, s.Members)
+	}
+	spec.Species = alive
+	return species, nil
+}
+
+// Validate SpecNEAT fields.
+func (spec *SpecNEAT) Validate() error {
+	if spec.Metric == nil {
+		return errors.New("metric field has to be provided")
+	}
+	if spec.Threshold <= 0 {
+		return errors.New("threshold should be positive")
+	}
+	return nil
+}
+
+// ModNEAT implements the reproduction scheme of NEAT. The population is
+// speciated with a SpecNEAT. Species that haven't improved for MaxStagnation
+// generations are removed, unless they contain the best individual. Explicit
+// fitness sharing then determines how many offsprings each species produces:
+// because fitnesses are minimized, the score of an individual is the
+// difference between the worst fitness of the population and it's own fitness,
+// divided by the size of it's species. Each species produces offsprings from
+// it's best SurvivalRate proportion of members. If KeepChampions is true then
+// the best member of each species that produces offsprings is copied
+// unchanged.
+type ModNEAT struct {
+	Spec          *SpecNEAT
+	MaxStagnation uint // 0 means species are never removed
+	SurvivalRate  float64
+	KeepChampions bool
+	MutRate       float64
+	CrossRate     float64
+}
+
+// allocateOffsprings distributes n offsprings proportionally to a list of
+// shares with the largest remainder method.
+func allocateOffsprings(n int, shares []float64) []int {
+	var (
+		counts     = make([]int, len(shares))
+		total      = sumFloat64s(shares)
+		remainders = make([]float64, len(shares))
+		allocated  int
+	)
+	if total <= 0 || math.IsInf(total, 0) || math.IsNaN(total) {
+		shares = make([]float64, len(shares))
+		for i := range shares {
+			shares[i] = 1
+		}
+		total = float64(len(shares))
+	}
+	for i, share := range shares {
+		var exact = float64(n) * share / total
+		counts[i] = int(exact)
+		remainders[i] = exact - float64(counts[i])
+		allocated += counts[i]
+	}
+	var order = newInts(uint(len(shares)))
+	sort.SliceStable(order, func(i, j int) bool { return remainders[order[i]] > remainders[order[j]] })
+	for i := 0; allocated < n; i++ {
+		counts[order[i%len(order)]]++
+		allocated++
+	}
+	return counts
+}
+
+// Apply ModNEAT.
+func (mod ModNEAT) Apply(pop *Population) error {
+	var species, err = mod.Spec.Apply(pop.Individuals, pop.RNG)
+	if err != nil {
+		return err
+	}
+	// Remove the stagnant species, except the one containing the best
+	// individual
+	var best = pop.Individuals.FitMin()
+	if mod.MaxStagnation > 0 {
+		var kept []Individuals
+		for i, s := range species {
+			if mod.Spec.Species[i].Stagnation < mod.MaxStagnation || s.FitMin() == best {
+				kept = append(kept, s)
+			}
+		}
+		species = kept
+	}
+	// Explicit fitness sharing and reproduction of each species
+	var (
+		counts     = allocateOffsprings(len(pop.Individuals), adjustedShares(species))
+		offsprings = make(Individuals, 0, len(pop.Individuals))
+	)
+	for i, s := range species {
+		if counts[i] == 0 {
+			continue
+		}
+		var parents = s.Clone(pop.RNG)
+		parents.SortByFitness()
+		parents = parents[:maxInt(1, int(math.Ceil(mod.SurvivalRate*float64(len(parents)))))]
+		var k int
+		if mod.KeepChampions {
+			offsprings = append(offsprings, parents[0].Clone(pop.RNG))
+			k++
+		}
+		for ; k < counts[i]; k++ {
+			var offspring = parents[pop.RNG.Intn(len(parents))].Clone(pop.RNG)
+			if pop.RNG.Float64() < mod.CrossRate {
+				var mate = parents[pop.RNG.Intn(len(parents))].Clone(pop.RNG)
+				offspring.Crossover(mate, pop.RNG)
+			}
+			if pop.RNG.Float64() < mod.MutRate {
+				offspring.Mutate(pop.RNG)
+			}
+			offsprings = append(offsprings, offspring)
+		}
+	}
+	copy(pop.Individuals, offsprings)
+	return nil
+}
+
+// Validate ModNEAT fields.
+func (mod ModNEAT) Validate() error {
+	if mod.Spec == nil {
+		return errors.New("Spec has to be provided")
+	}
+	if err := mod.Spec.Validate(); err != nil {
+		return err
+	}
+	if mod.SurvivalRate <= 0 || mod.SurvivalRate > 1 {
+		return errors.New("SurvivalRate should be in (0, 1]")
+	}
+	if mod.MutRate < 0 || mod.MutRate > 1 {
+		return errInvalidMutRate
+	}
+	if mod.CrossRate < 0 || mod.CrossRate > 1 {
+		return errInvalidCrossRate
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package eaopt
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var xorCases = [][3]float64{{0, 0, 0}, {0, 1, 1}, {1, 0, 1}, {1, 1, 0}}
+
+func xorFitness(g *NEATGenome) (float64, error) {
+	var (
+		net = g.Network()
+		err float64
+	)
+	for _, c := range xorCases {
+		var y = net.Activate(c[:2])[0]
+		err += (y - c[2]) * (y - c[2])
+	}
+	return err, nil
+}
+
+func TestNEATNewGenome(t *testing.T) {
+	var (
+		neat = NewNEAT(2, 1, xorFitness)
+		g1   = neat.NewGenome(newRand()).(*NEATGenome)
+		g2   = neat.NewGenome(newRand()).(*NEATGenome)
+	)
+	if len(g1.Nodes) != 4 || len(g1.Conns) != 3 {
+		t.Errorf("Expected 4 nodes and 3 connections, got %d and %d", len(g1.Nodes), len(g1.Conns))
+	}
+	// Identical structures share innovation numbers
+	for i := range g1.Conns {
+		if g1.Conns[i].Innovation != g2.Conns[i].Innovation {
+			t.Error("Identical connections should have the same innovation number")
+		}
+	}
+}
+
+func TestNEATInnovationTracking(t *testing.T) {
+	var (
+		neat = NewNEAT(2, 1, xorFitness)
+		rng  = newRand()
+		g1   = neat.NewGenome(rng).(*NEATGenome)
+		g2   = g1.Clone().(*NEATGenome)
+	)
+	// Splitting the same connection twice yields the same innovations
+	g1.Conns = g1.Conns[:1]
+	g2.Conns = g2.Conns[:1]
+	g1.MutAddNode(rng)
+	g2.MutAddNode(rng)
+	if len(g1.Conns) != 3 || g1.Conns[0].Enabled {
+		t.Errorf("Expected the split connection to be disabled, got %v", g1.Conns)
+	}
+	for i := range g1.Conns {
+		if g1.Conns[i].Innovation != g2.Conns[i].Innovation {
+			t.Error("Identical structural mutations should share innovation numbers")
+		}
+	}
+	if g1.Nodes[len(g1.Nodes)-1].ID != g2.Nodes[len(g2.Nodes)-1].ID {
+		t.Error("Identical splits should create nodes with the same ID")
+	}
+	// Splitting the same connection again in the same genome creates a new node
+	g1.Conns[0].Enabled = true
+	var before = len(g1.Nodes)
+	g1.Conns = append(g1.Conns[:0], g1.Conns[0])
+	g1.MutAddNode(rng)
+	if g1.Nodes[before].ID == g1.Nodes[before-1].ID {
+		t.Error("A genome should not contain the same node twice")
+	}
+}
+
+func TestNEATAddNodePreservesBehaviour(t *testing.T) {
+	var (
+		neat = NewNEAT(2, 1, xorFitness)
+		rng  = newRand()
+		g    = neat.NewGenome(rng).(*NEATGenome)
+	)
+	neat.AddConnRate = 1
+	for i := 0; i < 10; i++ {
+		g.MutAddConn(rng)
+		g.MutAddNode(rng)
+	}
+	var net = g.Network()
+	if net.Recurrent {
+		t.Error("Network should not be recurrent")
+	}
+	for _, conn := range g.Conns {
+		if conn.In == conn.Out || g.reaches(conn.Out, conn.In) && conn.Enabled {
+			t.Errorf("Connection %v creates a cycle", conn)
+		}
+	}
+}
+
+func TestNEATNetwork(t *testing.T) {
+	var (
+		neat = NewNEAT(2, 1, xorFitness)
+		g    = &NEATGenome{
+			Nodes: []NEATNode{{0, NEATInput}, {1, NEATInput}, {2, NEATBias}, {3, NEATOutput}, {4, NEATHidden}},
+			Conns: []NEATConn{
+				{In: 0, Out: 4, Weight: 1, Enabled: true, Innovation: 0},
+				{In: 1, Out: 4, Weight: 1, Enabled: true, Innovation: 1},
+				{In: 2, Out: 3, Weight: 5, Enabled: false, Innovation: 2},
+				{In: 4, Out: 3, Weight: 2, Enabled: true, Innovation: 3},
+			},
+			NEAT: neat,
+		}
+		net = g.Network()
+		y   = net.Activate([]float64{0.5, -0.25})[0]
+	)
+	if net.Recurrent {
+		t.Error("Network should not be recurrent")
+	}
+	if expected := NEATSigmoid(2 * NEATSigmoid(0.25)); math.Abs(y-expected) > 1e-12 {
+		t.Errorf("Expected %f, got %f", expected, y)
+	}
+	// Add a recurrent connection
+	g.Conns = append(g.Conns, NEATConn{In: 3, Out: 4, Weight: 1, Enabled: true, Innovation: 4})
+	net = g.Network()
+	if !net.Recurrent {
+		t.Error("Network should be recurrent")
+	}
+	var y1 = net.Activate([]float64{0, 0})[0]
+	var y2 = net.Activate([]float64{0, 0})[0]
+	if y1 == y2 {
+		t.Error("The state of a recurrent network should evolve")
+	}
+	net.Reset()
+	if y := net.Activate([]float64{0, 0})[0]; y != y1 {
+		t.Errorf("Expected %f after reset, got %f", y1, y)
+	}
+}
+
+func TestNEATCrossover(t *testing.T) {
+	var (
+		neat = NewNEAT(2, 1, xorFitness)
+		rng  = newRand()
+		g1   = neat.NewGenome(rng).(*NEATGenome)
+		g2   = g1.Clone().(*NEATGenome)
+	)
+	g1.MutAddNode(rng)
+	g1.Fitness, g2.Fitness = 1, 2
+	g1.Crossover(g2, rng)
+	// Both offsprings inherit the structure of the fitter parent
+	for _, g := range []*NEATGenome{g1, g2} {
+		if len(g.Nodes) != 5 || len(g.Conns) != 5 {
+			t.Errorf("Expected 5 nodes and 5 connections, got %d and %d", len(g.Nodes), len(g.Conns))
+		}
+	}
+}
+
+func TestNEATDistance(t *testing.T) {
+	var (
+		neat = NewNEAT(2, 1, xorFitness)
+		rng  = newRand()
+		g1   = neat.NewGenome(rng).(*NEATGenome)
+		g2   = g1.Clone().(*NEATGenome)
+		a    = Individual{Genome: g1, ID: "a"}
+		b    = Individual{Genome: g2, ID: "b"}
+	)
+	if d := neat.Distance(a, b); d != 0 {
+		t.Errorf("Expected 0, got %f", d)
+	}
+	g2.Conns[0].Weight += 1
+	if d := neat.Distance(a, b); math.Abs(d-neat.C3/3) > 1e-12 {
+		t.Errorf("Expected %f, got %f", neat.C3/3, d)
+	}
+	g2.MutAddNode(rng)
+	if d := neat.Distance(a, b); d <= neat.C3/3 {
+		t.Errorf("Expected distance to increase, got %f", d)
+	}
+}
+
+func TestAllocateOffsprings(t *testing.T) {
+	var testCases = []struct {
+		n      int
+		shares []float64
+		counts []int
+	}{
+		{10, []float64{1, 1}, []int{5, 5}},
+		{10, []float64{3, 1}, []int{8, 2}},
+		{10, []float64{0, 0}, []int{5, 5}},
+		{7, []float64{1, 1, 1}, []int{3, 2, 2}},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var counts = allocateOffsprings(tc.n, tc.shares)
+			if fmt.Sprint(counts) != fmt.Sprint(tc.counts) {
+				t.Errorf("Expected %v, got %v", tc.counts, counts)
+			}
+		})
+	}
+}
+
+func TestSpecNEATPersistence(t *testing.T) {
+	var (
+		rng   = newRand()
+		spec  = &SpecNEAT{Metric: l1Distance, Threshold: 1}
+		indis = Individuals{
+			{Genome: Vector{0, 0}, ID: "a", Fitness: 2},
+			{Genome: Vector{0.1, 0}, ID: "b", Fitness: 1},
+			{Genome: Vector{10, 10}, ID: "c", Fitness: 3},
+		}
+	)
+	var species, _ = spec.Apply(indis, rng)
+	if len(species) != 2 || len(spec.Species) != 2 {
+		t.Fatalf("Expected 2 species, got %d", len(species))
+	}
+	var ids = []int{spec.Species[0].ID, spec.Species[1].ID}
+	// The species persist across generations
+	indis[2].Fitness = 0.5
+	species, _ = spec.Apply(indis, rng)
+	if len(species) != 2 || spec.Species[0].ID != ids[0] || spec.Species[1].ID != ids[1] {
+		t.Errorf("Expected species %v, got %v", ids, spec.Species)
+	}
+	if spec.Species[0].Stagnation != 1 || spec.Species[1].Stagnation != 0 {
+		t.Errorf("Unexpected stagnation counters %d and %d", spec.Species[0].Stagnation, spec.Species[1].Stagnation)
+	}
+	if spec.Species[0].Age != 2 {
+		t.Errorf("Expected age 2, got %d", spec.Species[0].Age)
+	}
+	// Empty species disappear
+	species, _ = spec.Apply(indis[:2], rng)
+	if len(species) != 1 {
+		t.Errorf("Expected 1 species, got %d", len(species))
+	}
+}
+
+func TestModNEATValidate(t *testing.T) {
+	var spec = &SpecNEAT{Metric: l1Distance, Threshold: 1}
+	var testCases = []ModNEAT{
+		{Spec: nil, SurvivalRate: 0.2},
+		{Spec: &SpecNEAT{Threshold: 1}, SurvivalRate: 0.2},
+		{Spec: spec, SurvivalRate: 0},
+		{Spec: spec, SurvivalRate: 0.2, MutRate: 2},
+		{Spec: spec, SurvivalRate: 0.2, CrossRate: -1},
+	}
+	for i, mod := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			if mod.Validate() == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestNEATMinimize(t *testing.T) {
+	var (
+		neat = NewNEAT(2, 1, xorFitness)
+		conf = NewDefaultGAConfig()
+	)
+	conf.PopSize = 100
+	conf.NGenerations = 50
+	conf.RNG = rand.New(rand.NewSource(42))
+	conf.Model = ModNEAT{
+		Spec:          &SpecNEAT{Metric: neat.Distance, Threshold: 3},
+		MaxStagnation: 15,
+		SurvivalRate:  0.2,
+		KeepChampions: true,
+		MutRate:       0.8,
+		CrossRate:     0.75,
+	}
+	var ga, err = conf.NewGA()
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	var initial float64
+	ga.Callback = func(ga *GA) {
+		if ga.Generations == 0 {
+			initial = ga.HallOfFame[0].Fitness
+		}
+		if l := len(ga.Populations[0].Individuals); l != 100 {
+			t.Errorf("Expected 100 individuals, got %d", l)
+		}
+	}
+	if err = ga.Minimize(neat.NewGenome); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if ga.HallOfFame[0].Fitness >= initial {
+		t.Errorf("Expected the fitness to improve from %f, got %f", initial, ga.HallOfFame[0].Fitness)
+	}
+}
@@ -0,0 +1,198 @@
+package eaopt
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	errNilMetric         = errors.New("Metric cannot be nil")
+	errInvalidRadius     = errors.New("Radius should be higher than 0")
+	errNichedEvaluations = errors.New("The Model wrapped by a niching model cannot evaluate " +
+		"Individuals during Apply, use a Model such as ModGenerational or ModSteadyState " +
+		"without KeepBest")
+)
+
+// applyNiched replaces the fitnesses of a Population's Individuals with the
+// niched fitnesses returned by niche, applies a Model and then restores the
+// raw fitnesses of the Individuals that are still evaluated. The raw fitnesses
+// are carried by the Individuals themselves so that they survive cloning. The
+// Model is given a RunContext of its own, an error is returned if it
+// evaluates Individuals because it would then compare raw fitnesses with
+// niched ones.
+func applyNiched(pop *Population, model Model, niche func(indis Individuals, raw []float64) []float64) error {
+	if err := pop.evaluateAll(pop.Run.parallel()); err != nil {
+		return err
+	}
+	var raw, err = checkFitnesses(pop.Individuals)
+	if err != nil {
+		return err
+	}
+	var niched = niche(pop.Individuals, raw)
+	for i := range pop.Individuals {
+		pop.Individuals[i].raw = raw[i]
+		pop.Individuals[i].Fitness = niched[i]
+	}
+	var run = pop.Run
+	pop.Run = &RunContext{}
+	if run != nil {
+		*pop.Run = RunContext{
+			Generation:   run.Generation,
+			NGenerations: run.NGenerations,
+			Start:        run.Start,
+			parallelEval: run.parallelEval,
+		}
+	}
+	err = model.Apply(pop)
+	if err == nil && pop.Run.Evaluations() > 0 {
+		err = errNichedEvaluations
+	}
+	pop.Run = run
+	for i := range pop.Individuals {
+		if pop.Individuals[i].Evaluated {
+			pop.Individuals[i].Fitness = pop.Individuals[i].raw
+		}
+	}
+	return err
+}
+
+// validateNiched checks the fields shared by the niching models.
+func validateNiched(model Model, metric Metric, radius float64) error {
+	if model == nil {
+		return errors.New("Model cannot be nil")
+	}
+	if err := model.Validate(); err != nil {
+		return err
+	}
+	if metric == nil {
+		return errNilMetric
+	}
+	if radius <= 0 {
+		return errInvalidRadius
+	}
+	return nil
+}
+
+// ModFitnessSharing implements fitness sharing, which makes Individuals that
+// are crowded in the same region of the search space less likely to be
+// selected so that several optima can be maintained at once. The fitnesses
+// are first turned into non-negative rewards with Scaler, which defaults to
+// ScaleWindowing{Offset: 1}. Each reward is then divided by the Individual's
+// niche count, which is the sum of sh(d) over the population with
+// sh(d) = 1 - (d / Radius)^Alpha if d < Radius and 0 otherwise, d being given
+// by Metric. The wrapped Model sees the opposite of the shared rewards as
+// fitnesses, the raw fitnesses are restored once it has been applied. The
+// wrapped Model must not evaluate Individuals during Apply, which rules out
+// Models such as ModRing or ModDownToSize.
+// Reference: Goldberg, D. E., & Richardson, J. (1987), Genetic algorithms with
+// sharing for multimodal function optimization
+type ModFitnessSharing struct {
+	Model  Model
+	Metric Metric
+	Radius float64
+	Alpha  float64
+	Scaler FitnessScaler
+}
+
+// Apply ModFitnessSharing.
+func (mod ModFitnessSharing) Apply(pop *Population) error {
+	return applyNiched(pop, mod.Model, mod.share)
+}
+
+// share returns the shared fitnesses of a slice of Individuals.
+func (mod ModFitnessSharing) share(indis Individuals, raw []float64) []float64 {
+	var scaler = mod.Scaler
+	if scaler == nil {
+		scaler = ScaleWindowing{Offset: 1}
+	}
+	var (
+		rewards = scaleFitnesses(raw, scaler)
+		dm      = newDistanceMemoizer(mod.Metric)
+		shared  = make([]float64, len(indis))
+	)
+	for i, a := range indis {
+		var count float64
+		for _, b := range indis {
+			if d := dm.GetDistance(a, b); d < mod.Radius {
+				count += 1 - math.Pow(d/mod.Radius, mod.Alpha)
+			}
+		}
+		shared[i] = -rewards[i] / math.Max(count, 1)
+	}
+	return shared
+}
+
+// Validate ModFitnessSharing fields.
+func (mod ModFitnessSharing) Validate() error {
+	if err := validateNiched(mod.Model, mod.Metric, mod.Radius); err != nil {
+		return err
+	}
+	if mod.Alpha <= 0 {
+		return errors.New("Alpha should be higher than 0")
+	}
+	if mod.Scaler != nil {
+		return mod.Scaler.Validate()
+	}
+	return nil
+}
+
+// ModClearing implements clearing. The Individuals are sorted by fitness and
+// the best Individual that hasn't been cleared yet becomes the winner of a
+// niche made up of the Individuals within Radius of it. The Capacity best
+// Individuals of each niche keep their fitness whereas the other ones are
+// cleared, meaning that the wrapped Model sees them with a fitness of +Inf.
+// The raw fitnesses are restored once the Model has been applied. As with
+// ModFitnessSharing, the wrapped Model must not evaluate Individuals.
+// Reference: Pétrowski, A. (1996), A clearing procedure as a niching method
+// for genetic algorithms
+type ModClearing struct {
+	Model    Model
+	Metric   Metric
+	Radius   float64
+	Capacity uint
+}
+
+// Apply ModClearing.
+func (mod ModClearing) Apply(pop *Population) error {
+	return applyNiched(pop, mod.Model, mod.clear)
+}
+
+// clear returns the fitnesses of a slice of Individuals after clearing.
+func (mod ModClearing) clear(indis Individuals, raw []float64) []float64 {
+	var (
+		order   = argsortFloat64s(raw)
+		dm      = newDistanceMemoizer(mod.Metric)
+		cleared = copyFloat64s(raw)
+		done    = make([]bool, len(indis))
+	)
+	for i, w := range order {
+		if done[w] {
+			continue
+		}
+		done[w] = true
+		var winners uint = 1
+		for _, j := range order[i+1:] {
+			if done[j] || dm.GetDistance(indis[w], indis[j]) >= mod.Radius {
+				continue
+			}
+			done[j] = true
+			if winners < mod.Capacity {
+				winners++
+			} else {
+				cleared[j] = math.Inf(1)
+			}
+		}
+	}
+	return cleared
+}
+
+// Validate ModClearing fields.
+func (mod ModClearing) Validate() error {
+	if err := validateNiched(mod.Model, mod.Metric, mod.Radius); err != nil {
+		return err
+	}
+	if mod.Capacity == 0 {
+		return errors.New("Capacity should be higher than 0")
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package eaopt
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// modRecord clones the individuals of a population and records the fitnesses
+// it was given.
+type modRecord struct {
+	fitnesses *[]float64
+}
+
+func (mod modRecord) Apply(pop *Population) error {
+	*mod.fitnesses = pop.Individuals.getFitnesses()
+	copy(pop.Individuals, pop.Individuals.Clone(pop.RNG))
+	return nil
+}
+func (mod modRecord) Validate() error { return nil }
+
+func newLineIndividuals(xs ...float64) Individuals {
+	var indis = make(Individuals, len(xs))
+	for i, x := range xs {
+		indis[i] = NewIndividual(Vector{x}, newRand())
+	}
+	return indis
+}
+
+func TestModFitnessSharing(t *testing.T) {
+	var (
+		seen []float64
+		pop  = Population{Individuals: newLineIndividuals(0, 0.1, 0.2, 5), RNG: newRand()}
+		mod  = ModFitnessSharing{Model: modRecord{&seen}, Metric: l1Distance, Radius: 1, Alpha: 1}
+	)
+	if err := mod.Apply(&pop); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	// The rewards are 6, 5.9, 5.8 and 1, the first three individuals share
+	// their rewards whereas the last one is alone in its niche
+	var expected = []float64{-6 / 2.7, -5.9 / 2.8, -5.8 / 2.7, -1}
+	for i := range expected {
+		if math.Abs(seen[i]-expected[i]) > 1e-10 {
+			t.Fatalf("Expected %v, got %v", expected, seen)
+		}
+	}
+	// The raw fitnesses of the clones are restored
+	for i, indi := range pop.Individuals {
+		if !indi.Evaluated || indi.Fitness != indi.Genome.(Vector)[0] {
+			t.Errorf("Individual %d wasn't restored: %v", i, indi)
+		}
+	}
+}
+
+func TestModClearing(t *testing.T) {
+	var (
+		inf       = math.Inf(1)
+		testCases = []struct {
+			capacity uint
+			cleared  []float64
+		}{
+			{1, []float64{inf, 0, inf, 5, inf}},
+			{2, []float64{0.1, 0, inf, 5, 5.1}},
+		}
+	)
+	for _, tc := range testCases {
+		var (
+			seen []float64
+			pop  = Population{Individuals: newLineIndividuals(0.1, 0, 0.2, 5, 5.1), RNG: newRand()}
+			mod  = ModClearing{Model: modRecord{&seen}, Metric: l1Distance, Radius: 1, Capacity: tc.capacity}
+		)
+		if err := mod.Apply(&pop); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		for i := range tc.cleared {
+			if seen[i] != tc.cleared[i] {
+				t.Errorf("Expected %v, got %v", tc.cleared, seen)
+				break
+			}
+		}
+		// The raw fitnesses of the clones are restored, even for cleared
+		// individuals that share the same niched fitness
+		for i, indi := range pop.Individuals {
+			if !indi.Evaluated || indi.Fitness != indi.Genome.(Vector)[0] {
+				t.Errorf("Individual %d wasn't restored: %v", i, indi)
+			}
+		}
+	}
+}
+
+func TestNichingErrors(t *testing.T) {
+	var valid = []Model{
+		ModFitnessSharing{Model: ModIdentity{}, Metric: l1Distance, Radius: 1, Alpha: 1},
+		ModClearing{Model: ModIdentity{}, Metric: l1Distance, Radius: 1, Capacity: 1},
+	}
+	for _, mod := range valid {
+		if err := mod.Validate(); err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+	}
+	var invalid = []Model{
+		ModFitnessSharing{Metric: l1Distance, Radius: 1, Alpha: 1},
+		ModFitnessSharing{Model: ModValidateError{}, Metric: l1Distance, Radius: 1, Alpha: 1},
+		ModFitnessSharing{Model: ModIdentity{}, Radius: 1, Alpha: 1},
+		ModFitnessSharing{Model: ModIdentity{}, Metric: l1Distance, Alpha: 1},
+		ModFitnessSharing{Model: ModIdentity{}, Metric: l1Distance, Radius: 1},
+		ModFitnessSharing{Model: ModIdentity{}, Metric: l1Distance, Radius: 1, Alpha: 1, Scaler: ScaleLinear{}},
+		ModClearing{Model: ModIdentity{}, Metric: l1Distance, Radius: 1},
+	}
+	for i, mod := range invalid {
+		if err := mod.Validate(); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+	var pop = Population{Individuals: newLineIndividuals(0, math.NaN()), RNG: newRand()}
+	if err := valid[0].Apply(&pop); err == nil {
+		t.Error("Expected error")
+	}
+	pop = Population{Individuals: newLineIndividuals(0, 1), RNG: newRand()}
+	if err := (ModClearing{Model: ModRuntimeError{}, Metric: l1Distance, Radius: 1, Capacity: 1}).Apply(&pop); err == nil {
+		t.Error("Expected error")
+	}
+	// Models that evaluate individuals during Apply can't be wrapped
+	pop = Population{Individuals: newLineIndividuals(0, 1, 2), RNG: newRand()}
+	var ring = ModRing{Selector: SelTournament{1}, MutRate: 1}
+	if err := (ModClearing{Model: ring, Metric: l1Distance, Radius: 1, Capacity: 1}).Apply(&pop); err != errNichedEvaluations {
+		t.Errorf("Expected %v, got %v", errNichedEvaluations, err)
+	}
+}
+
+// twoWells has two global minima, at -5 and 5.
+type twoWells struct{ Vector }
+
+func (g twoWells) Evaluate() (float64, error) {
+	var x = g.Vector[0]
+	return math.Min((x+5)*(x+5), (x-5)*(x-5)), nil
+}
+func (g twoWells) Mutate(rng *rand.Rand) {
+	MutGaussianFloat64(g.Vector, 1, []float64{0.3}, []float64{-10}, []float64{10}, rng)
+}
+func (g twoWells) Crossover(h Genome, rng *rand.Rand) { g.Vector.Crossover(h.(twoWells).Vector, rng) }
+func (g twoWells) Clone() Genome                      { return twoWells{g.Vector.Clone().(Vector)} }
+
+func TestGANiching(t *testing.T) {
+	var (
+		metric = func(a, b Individual) float64 {
+			return math.Abs(a.Genome.(twoWells).Vector[0] - b.Genome.(twoWells).Vector[0])
+		}
+		models = []Model{
+			ModFitnessSharing{
+				Model:  ModGenerational{Selector: SelTournament{NContestants: 2}, MutRate: 0.5},
+				Metric: metric,
+				Radius: 3,
+				Alpha:  1,
+			},
+			ModClearing{
+				Model:    ModGenerational{Selector: SelTournament{NContestants: 2}, MutRate: 0.5},
+				Metric:   metric,
+				Radius:   3,
+				Capacity: 2,
+			},
+		}
+	)
+	for _, model := range models {
+		var ga, err = GAConfig{
+			NPops:        1,
+			PopSize:      40,
+			NGenerations: 30,
+			HofSize:      1,
+			Model:        model,
+			RNG:          rand.New(rand.NewSource(1)),
+		}.NewGA()
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		err = ga.Minimize(func(rng *rand.Rand) Genome {
+			return twoWells{Vector(InitUnifFloat64(1, -10, 10, rng))}
+		})
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		// Both optima are found
+		var left, right bool
+		for _, indi := range ga.Populations[0].Individuals {
+			var x = indi.Genome.(twoWells).Vector[0]
+			left = left || math.Abs(x+5) < 0.5
+			right = right || math.Abs(x-5) < 0.5
+		}
+		if !left || !right {
+			t.Errorf("%T: expected both optima to be found", model)
+		}
+	}
+}
@@ -17,6 +17,7 @@ type Population struct {
 	Generations uint          `json:"generations"`
 	ID          string        `json:"id"`
 	RNG         *rand.Rand
+	Run         *RunContext `json:"-"` // Progress of the run, set by the GA
 }
 
 // Generate a new population.
@@ -49,11 +50,17 @@ type Populations []Population
 
 // Apply a function to a slice of Populations.
 func (pops Populations) Apply(f func(pop *Population) error) error {
+	return pops.applyIndexed(func(i int, pop *Population) error { return f(pop) })
+}
+
+// applyIndexed is the same as Apply except that f is also given the index of
+// the Population.
+func (pops Populations) applyIndexed(f func(i int, pop *Population) error) error {
 	var g errgroup.Group
 	for i := range pops {
 		i := i // https://golang.org/doc/faq#closures_and_goroutines
 		g.Go(func() error {
-			return f(&pops[i])
+			return f(i, &pops[i])
 		})
 	}
 	return g.Wait()
@@ -0,0 +1,94 @@
+package eaopt
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// A RunContext describes the progress of the run a Population is evolved in.
+// The GA shares a single RunContext between its Populations through their Run
+// field, which allows Models to adapt their behaviour to the progress of the
+// run. The Run field of a Population that isn't evolved by a GA is nil, in
+// which case the methods of RunContext return zero values.
+type RunContext struct {
+	Generation   uint      // Current generation, 1 during the first call to Model.Apply
+	NGenerations uint      // Number of generations the run is allowed to last
+	Start        time.Time // Time at which the run started
+	evaluations  uint64
+	parallelEval bool
+}
+
+// newRunContext returns a RunContext for a run of nGenerations generations.
+func newRunContext(nGenerations uint) *RunContext {
+	return &RunContext{NGenerations: nGenerations, Start: time.Now()}
+}
+
+// Progress returns the proportion of the generations that have been run, it is
+// thus comprised between 0 and 1.
+func (rc *RunContext) Progress() float64 {
+	if rc == nil || rc.NGenerations == 0 {
+		return 0
+	}
+	return float64(minUint(rc.Generation, rc.NGenerations)) / float64(rc.NGenerations)
+}
+
+// Evaluations returns the number of Genome evaluations performed so far.
+func (rc *RunContext) Evaluations() uint64 {
+	if rc == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&rc.evaluations)
+}
+
+// Elapsed returns the time elapsed since the start of the run.
+func (rc *RunContext) Elapsed() time.Duration {
+	if rc == nil {
+		return 0
+	}
+	return time.Since(rc.Start)
+}
+
+// CountEvaluations adds n to the number of evaluations. The GA counts the
+// evaluations it performs itself, Models only have to call this method for
+// the evaluations they perform within Apply. It is safe for concurrent use.
+func (rc *RunContext) CountEvaluations(n int) {
+	if rc == nil {
+		return
+	}
+	atomic.AddUint64(&rc.evaluations, uint64(n))
+}
+
+// parallel indicates if the evaluations of whole Populations should be done in
+// parallel, as set by the GA's ParallelEval field.
+func (rc *RunContext) parallel() bool {
+	return rc != nil && rc.parallelEval
+}
+
+// evaluate evaluates an Individual and counts the evaluation in the
+// Population's RunContext.
+func (pop *Population) evaluate(indi *Individual) error {
+	if !indi.Evaluated {
+		pop.Run.CountEvaluations(1)
+	}
+	return indi.Evaluate()
+}
+
+// evaluateAll evaluates the Individuals of a Population and counts the
+// evaluations in the Population's RunContext.
+func (pop *Population) evaluateAll(parallel bool) error {
+	return pop.evaluateIndividuals(pop.Individuals, parallel)
+}
+
+// evaluateIndividuals evaluates Individuals on behalf of a Population, for
+// instance offsprings that are not part of it yet, and counts the evaluations
+// in the Population's RunContext.
+func (pop *Population) evaluateIndividuals(indis Individuals, parallel bool) error {
+	var n int
+	for _, indi := range indis {
+		if !indi.Evaluated {
+			n++
+		}
+	}
+	pop.Run.CountEvaluations(n)
+	return indis.Evaluate(parallel)
+}
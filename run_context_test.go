@@ -0,0 +1,163 @@
+package eaopt
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRunContextNil(t *testing.T) {
+	var rc *RunContext
+	rc.CountEvaluations(3)
+	if rc.Evaluations() != 0 || rc.Progress() != 0 || rc.Elapsed() != 0 {
+		t.Error("A nil RunContext should return zero values")
+	}
+}
+
+func TestRunContextProgress(t *testing.T) {
+	var rc = newRunContext(4)
+	for gen, progress := range []float64{0, 0.25, 0.5, 0.75, 1, 1} {
+		rc.Generation = uint(gen)
+		if p := rc.Progress(); p != progress {
+			t.Errorf("Expected %f, got %f", progress, p)
+		}
+	}
+	rc.CountEvaluations(2)
+	rc.CountEvaluations(3)
+	if n := rc.Evaluations(); n != 5 {
+		t.Errorf("Expected 5, got %d", n)
+	}
+}
+
+func TestGAEvaluations(t *testing.T) {
+	var ga, err = GAConfig{
+		NPops:        2,
+		PopSize:      10,
+		NGenerations: 5,
+		HofSize:      1,
+		Model:        ModMutationOnly{Strict: true},
+		RNG:          newRand(),
+	}.NewGA()
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	ga.Minimize(NewVector)
+	// Every individual is evaluated once at initialization and then mutated
+	// once per generation
+	if n := ga.Evaluations(); n != 120 {
+		t.Errorf("Expected 120 evaluations, got %d", n)
+	}
+	// The counter is reset at each run
+	ga.Minimize(NewVector)
+	if n := ga.Evaluations(); n != 120 {
+		t.Errorf("Expected 120 evaluations, got %d", n)
+	}
+}
+
+// wrapperModel is a Model that delegates to another Model, it checks that the
+// RunContext doesn't depend on the Model being known by the GA.
+type wrapperModel struct{ Model }
+
+func TestModSimulatedAnnealingRunContext(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		gens = make(map[uint]bool)
+		sa   = &ModSimulatedAnnealing{
+			Accept: func(gen, nGen uint, e0, e1 float64) float64 {
+				mu.Lock()
+				defer mu.Unlock()
+				if nGen != 10 {
+					t.Errorf("Expected 10 generations, got %d", nGen)
+				}
+				gens[gen] = true
+				return 0
+			},
+		}
+	)
+	for _, mod := range []Model{sa, wrapperModel{sa}} {
+		gens = make(map[uint]bool)
+		var ga, err = GAConfig{
+			NPops:        2,
+			PopSize:      10,
+			NGenerations: 10,
+			HofSize:      1,
+			Model:        mod,
+			Speciator:    SpecKMedoids{K: 2, MinPerCluster: 1, Metric: l1Distance, MaxIterations: 5},
+			RNG:          newRand(),
+		}.NewGA()
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		if err = ga.Minimize(NewVector); err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+		if !gens[1] || !gens[10] || gens[0] {
+			t.Errorf("Expected generations from 1 to 10, got %v", gens)
+		}
+	}
+}
+
+// countedVector is a Vector that counts how many times it is evaluated.
+type countedVector struct {
+	Vector
+	count *int64
+}
+
+func (cv countedVector) Evaluate() (float64, error) {
+	atomic.AddInt64(cv.count, 1)
+	return cv.Vector.Evaluate()
+}
+
+func (cv countedVector) Crossover(mate Genome, rng *rand.Rand) {
+	cv.Vector.Crossover(mate.(countedVector).Vector, rng)
+}
+
+func (cv countedVector) Clone() Genome {
+	return countedVector{cv.Vector.Clone().(Vector), cv.count}
+}
+
+func TestModelEvaluations(t *testing.T) {
+	var testCases = []struct {
+		model    Model
+		migrator Migrator
+	}{
+		{ModSteadyState{Selector: SelTournament{2}, KeepBest: true, MutRate: 0.5}, nil},
+		{ModDownToSize{NOffsprings: 5, SelectorA: SelTournament{2}, SelectorB: SelElitism{}, MutRate: 0.5}, nil},
+		{ModRing{Selector: SelTournament{1}, MutRate: 0.5}, nil},
+		{ModGenerational{Selector: SelTournament{2}, MutRate: 0.5}, MigTopology{
+			Topology:    TopoRing{},
+			NMigrants:   2,
+			Emigrants:   SelTournament{2},
+			Replacement: SelTournament{2},
+		}},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var count int64
+			var ga, err = GAConfig{
+				NPops:        2,
+				PopSize:      10,
+				NGenerations: 5,
+				HofSize:      1,
+				Model:        tc.model,
+				Migrator:     tc.migrator,
+				MigFrequency: 1,
+				RNG:          rand.New(rand.NewSource(42)),
+			}.NewGA()
+			if err != nil {
+				t.Fatalf("Expected nil, got %v", err)
+			}
+			err = ga.Minimize(func(rng *rand.Rand) Genome {
+				return countedVector{NewVector(rng).(Vector), &count}
+			})
+			if err != nil {
+				t.Fatalf("Expected nil, got %v", err)
+			}
+			if n := ga.Evaluations(); int64(n) != count {
+				t.Errorf("Expected %d evaluations, got %d", count, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package eaopt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A FitnessScaler converts fitnesses into non-negative selection weights,
+// which are used by fitness proportionate selectors such as SelRoulette and
+// SelSUS. Fitnesses are minimized, hence lower fitnesses should be mapped to
+// higher weights. The fitnesses given to Scale are finite and their order is
+// arbitrary.
+type FitnessScaler interface {
+	Scale(fitnesses []float64) []float64
+	Validate() error
+}
+
+// errNaNFitness is returned by the selectors that can't rank NaN fitnesses.
+func errNaNFitness(i int) error {
+	return fmt.Errorf("individual %d has a NaN fitness, selection is not possible", i)
+}
+
+// fitnessOf evaluates the i-th individual and returns its fitness, which can't
+// be NaN.
+func fitnessOf(indis Individuals, i int) (float64, error) {
+	if err := indis[i].Evaluate(); err != nil {
+		return 0, err
+	}
+	if math.IsNaN(indis[i].Fitness) {
+		return 0, errNaNFitness(i)
+	}
+	return indis[i].Fitness, nil
+}
+
+// checkFitnesses evaluates individuals and checks that none of them has a NaN
+// fitness.
+func checkFitnesses(indis Individuals) ([]float64, error) {
+	var fitnesses = make([]float64, len(indis))
+	for i := range indis {
+		var f, err = fitnessOf(indis, i)
+		if err != nil {
+			return nil, err
+		}
+		fitnesses[i] = f
+	}
+	return fitnesses, nil
+}
+
+// scaleFitnesses turns fitnesses into selection weights. Infinite fitnesses
+// are handled before the scaler is called: individuals with a fitness of -Inf
+// share all the weight, whereas individuals with a fitness of +Inf get no
+// weight. If all the weights are zero then every individual gets the same
+// weight.
+func scaleFitnesses(fitnesses []float64, scaler FitnessScaler) []float64 {
+	var (
+		weights = make([]float64, len(fitnesses))
+		finite  []float64
+		idxs    []int
+	)
+	for i, f := range fitnesses {
+		switch {
+		case math.IsInf(f, -1):
+			weights[i] = 1
+		case !math.IsInf(f, 1):
+			finite = append(finite, f)
+			idxs = append(idxs, i)
+		}
+	}
+	if sumFloat64s(weights) == 0 && len(finite) > 0 {
+		for i, w := range scaler.Scale(finite) {
+			weights[idxs[i]] = math.Max(w, 0)
+		}
+	}
+	if sum := sumFloat64s(weights); sum == 0 || math.IsInf(sum, 0) || math.IsNaN(sum) {
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	return weights
+}
+
+// argsortFloat64s returns the indexes that sort a float64 slice in
+// increasing order.
+func argsortFloat64s(floats []float64) []int {
+	var order = newInts(uint(len(floats)))
+	sort.SliceStable(order, func(i, j int) bool { return floats[order[i]] < floats[order[j]] })
+	return order
+}
+
+// linearRankWeights returns weights that decrease linearly with the rank, the
+// first index of order being the best one.
+func linearRankWeights(order []int, pressure float64) []float64 {
+	var (
+		size    = float64(len(order))
+		weights = make([]float64, len(order))
+	)
+	for rank, idx := range order {
+		weights[idx] = 2 - pressure
+		if size > 1 {
+			weights[idx] += 2 * (pressure - 1) * (size - 1 - float64(rank)) / (size - 1)
+		}
+	}
+	return weights
+}
+
+// ScaleWindowing subtracts each fitness from the worst fitness and adds
+// Offset, hence the worst individual has a weight of Offset. This is the
+// scaling used by SelRoulette and SelSUS when no FitnessScaler is provided,
+// with an Offset of 1.
+type ScaleWindowing struct {
+	Offset float64
+}
+
+// Scale method from FitnessScaler.
+func (s ScaleWindowing) Scale(fitnesses []float64) []float64 {
+	var (
+		worst   = maxFloat64s(fitnesses)
+		weights = make([]float64, len(fitnesses))
+	)
+	for i, f := range fitnesses {
+		weights[i] = worst - f + s.Offset
+	}
+	return weights
+}
+
+// Validate ScaleWindowing fields.
+func (s ScaleWindowing) Validate() error {
+	if s.Offset < 0 {
+		return errors.New("Offset should be positive")
+	}
+	return nil
+}
+
+// ScaleLinear implements Goldberg's linear scaling. The raw weights, which are
+// the distances to the worst fitness, are linearly transformed so that their
+// average is unchanged and that the best individual's weight is C times the
+// average, which keeps the selection pressure constant whatever the range of
+// the fitnesses. C is typically between 1.2 and 2. If the transformation
+// would produce negative weights, the weights are instead stretched so that
+// the worst individual has a weight of 0.
+// Reference: Goldberg, D. E. (1989), Genetic Algorithms in Search,
+// Optimization and Machine Learning
+type ScaleLinear struct {
+	C float64
+}
+
+// Scale method from FitnessScaler.
+func (s ScaleLinear) Scale(fitnesses []float64) []float64 {
+	var (
+		raw  = ScaleWindowing{}.Scale(fitnesses)
+		avg  = meanFloat64s(raw)
+		max  = maxFloat64s(raw)
+		min  = minFloat64s(raw)
+		a, b float64
+	)
+	if max == avg {
+		return raw
+	}
+	if min > (s.C*avg-max)/(s.C-1) {
+		a = (s.C - 1) * avg / (max - avg)
+		b = avg * (max - s.C*avg) / (max - avg)
+	} else {
+		a = avg / (avg - min)
+		b = -min * avg / (avg - min)
+	}
+	for i, r := range raw {
+		raw[i] = a*r + b
+	}
+	return raw
+}
+
+// Validate ScaleLinear fields.
+func (s ScaleLinear) Validate() error {
+	if s.C <= 1 {
+		return errors.New("C should be higher than 1")
+	}
+	return nil
+}
+
+// ScaleSigmaTruncation gives each individual a weight of
+// max(0, mean + C * std - fitness), where mean and std are the average and the
+// standard deviation of the fitnesses. Individuals that are more than C
+// standard deviations worse than the average are thus never selected.
+// Typical values of C are between 1 and 3.
+type ScaleSigmaTruncation struct {
+	C float64
+}
+
+// Scale method from FitnessScaler.
+func (s ScaleSigmaTruncation) Scale(fitnesses []float64) []float64 {
+	var (
+		threshold = meanFloat64s(fitnesses) + s.C*math.Sqrt(varianceFloat64s(fitnesses))
+		weights   = make([]float64, len(fitnesses))
+	)
+	for i, f := range fitnesses {
+		weights[i] = math.Max(threshold-f, 0)
+	}
+	return weights
+}
+
+// Validate ScaleSigmaTruncation fields.
+func (s ScaleSigmaTruncation) Validate() error {
+	if s.C <= 0 {
+		return errors.New("C should be higher than 0")
+	}
+	return nil
+}
+
+// ScalePower normalizes the fitnesses so that the best individual has a
+// weight of 1 and the worst a weight of 0 and then raises the weights to the
+// power K. K > 1 increases the selection pressure whereas K < 1 decreases it.
+type ScalePower struct {
+	K float64
+}
+
+// Scale method from FitnessScaler.
+func (s ScalePower) Scale(fitnesses []float64) []float64 {
+	var (
+		best    = minFloat64s(fitnesses)
+		worst   = maxFloat64s(fitnesses)
+		weights = make([]float64, len(fitnesses))
+	)
+	if worst == best {
+		return weights
+	}
+	for i, f := range fitnesses {
+		weights[i] = math.Pow((worst-f)/(worst-best), s.K)
+	}
+	return weights
+}
+
+// Validate ScalePower fields.
+func (s ScalePower) Validate() error {
+	if s.K <= 0 {
+		return errors.New("K should be higher than 0")
+	}
+	return nil
+}
+
+// ScaleRank ignores the values of the fitnesses and only uses their ranks. The
+// weights decrease linearly with the rank, Pressure being the ratio between
+// the weight of the best individual and the average weight. Pressure should
+// be in [1, 2], see SelLinearRank.
+type ScaleRank struct {
+	Pressure float64
+}
+
+// Scale method from FitnessScaler.
+func (s ScaleRank) Scale(fitnesses []float64) []float64 {
+	return linearRankWeights(argsortFloat64s(fitnesses), s.Pressure)
+}
+
+// Validate ScaleRank fields.
+func (s ScaleRank) Validate() error {
+	if s.Pressure < 1 || s.Pressure > 2 {
+		return errors.New("Pressure should be between 1 and 2")
+	}
+	return nil
+}
+
+// ScaleBoltzmann gives each individual a weight of exp(-(f - fmin) / T),
+// where fmin is the lowest fitness and T is the Temperature. SelBoltzmann
+// relies on it.
+type ScaleBoltzmann struct {
+	Temperature float64
+}
+
+// Scale method from FitnessScaler.
+func (s ScaleBoltzmann) Scale(fitnesses []float64) []float64 {
+	var (
+		best    = minFloat64s(fitnesses)
+		weights = make([]float64, len(fitnesses))
+	)
+	for i, f := range fitnesses {
+		weights[i] = math.Exp(-(f - best) / s.Temperature)
+	}
+	return weights
+}
+
+// Validate ScaleBoltzmann fields.
+func (s ScaleBoltzmann) Validate() error {
+	if s.Temperature <= 0 {
+		return errors.New("Temperature should be higher than 0")
+	}
+	return nil
+}
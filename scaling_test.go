@@ -0,0 +1,114 @@
+package eaopt
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestScalers(t *testing.T) {
+	var testCases = []struct {
+		scaler    FitnessScaler
+		fitnesses []float64
+		weights   []float64
+	}{
+		{ScaleWindowing{Offset: 1}, []float64{3, -2, 0}, []float64{1, 6, 4}},
+		{ScaleWindowing{Offset: 0}, []float64{3, -2, 0}, []float64{0, 5, 3}},
+		// Raw weights are 0, 1, 2 with an average of 1, the best individual
+		// should get a weight of C times the average
+		{ScaleLinear{C: 1.5}, []float64{2, 1, 0}, []float64{0.5, 1, 1.5}},
+		// Scaling with C = 4 would make the weights negative
+		{ScaleLinear{C: 4}, []float64{2, 1, 0}, []float64{0, 1, 2}},
+		{ScaleLinear{C: 2}, []float64{1, 1}, []float64{0, 0}},
+		{ScaleSigmaTruncation{C: 1}, []float64{1, 3}, []float64{2, 0}},
+		{ScalePower{K: 2}, []float64{4, 2, 0}, []float64{0, 0.25, 1}},
+		{ScalePower{K: 2}, []float64{4, 4}, []float64{0, 0}},
+		{ScaleRank{Pressure: 2}, []float64{-100, 100, 0}, []float64{2, 0, 1}},
+		{ScaleBoltzmann{Temperature: 1}, []float64{0, 1}, []float64{1, math.Exp(-1)}},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			if err := tc.scaler.Validate(); err != nil {
+				t.Errorf("Expected nil, got %v", err)
+			}
+			var weights = tc.scaler.Scale(tc.fitnesses)
+			for j := range weights {
+				if math.Abs(weights[j]-tc.weights[j]) > 1e-10 {
+					t.Errorf("Expected %v, got %v", tc.weights, weights)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestScalersValidate(t *testing.T) {
+	var invalid = []FitnessScaler{
+		ScaleWindowing{Offset: -1},
+		ScaleLinear{C: 1},
+		ScaleSigmaTruncation{C: 0},
+		ScalePower{K: 0},
+		ScaleRank{Pressure: 3},
+		ScaleBoltzmann{},
+	}
+	for i, scaler := range invalid {
+		if scaler.Validate() == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+	if (SelRoulette{Scaler: ScaleLinear{}}).Validate() == nil || (SelSUS{Scaler: ScalePower{}}).Validate() == nil {
+		t.Error("Expected the selectors to validate their scaler")
+	}
+}
+
+func TestScaleFitnesses(t *testing.T) {
+	var (
+		inf       = math.Inf(1)
+		testCases = []struct {
+			fitnesses []float64
+			weights   []float64
+		}{
+			// Equal fitnesses
+			{[]float64{2, 2, 2}, []float64{1, 1, 1}},
+			// Infinitely bad individuals are never selected
+			{[]float64{inf, 2, 0}, []float64{0, 0, 2}},
+			{[]float64{inf, inf}, []float64{1, 1}},
+			// Infinitely good individuals are always selected
+			{[]float64{-inf, 1, -inf}, []float64{1, 0, 1}},
+		}
+	)
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
+			var weights = scaleFitnesses(tc.fitnesses, ScaleWindowing{})
+			for j := range weights {
+				if weights[j] != tc.weights[j] {
+					t.Errorf("Expected %v, got %v", tc.weights, weights)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestSelectorsScaling(t *testing.T) {
+	var (
+		indis     = newRankedIndividuals(3)
+		selectors = []Selector{
+			SelRoulette{Scaler: ScalePower{K: 1}},
+			SelSUS{Scaler: ScalePower{K: 1}},
+		}
+	)
+	// The worst individual has a weight of 0
+	for _, sel := range selectors {
+		if counts := selectionCounts(t, sel, 100, indis); counts[0] != 0 || counts[2] <= counts[1] {
+			t.Errorf("Unexpected counts %v", counts)
+		}
+	}
+	// Negative fitnesses are fine
+	for i := range indis {
+		indis[i].Fitness -= 1000
+	}
+	if counts := selectionCounts(t, SelSUS{}, 60, indis); counts[0] != 10 || counts[2] != 30 {
+		t.Errorf("Expected [10 20 30], got %v", counts)
+	}
+}
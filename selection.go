@@ -3,22 +3,44 @@ package eaopt
 import (
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
 	"sort"
 )
 
 // Selector chooses a subset of size n from a group of individuals. The group of
-// individuals a Selector is applied to is expected to be sorted.
+// individuals a Selector is applied to is expected to be sorted. Within a GA,
+// the individuals given to a Selector have already been evaluated and counted
+// by the GA, the Model or the Migrator, the evaluations performed by the
+// Selectors themselves are only useful when they are used on their own.
 type Selector interface {
 	Apply(n uint, indis Individuals, rng *rand.Rand) (selected Individuals, indexes []int, err error)
 	Validate() error
 }
 
+// A runSelector is a Selector whose behaviour depends on the progress of the
+// run, such as SelBoltzmann.
+type runSelector interface {
+	withRun(run *RunContext) Selector
+}
+
+// selectorFor returns the Selector to apply to the individuals of a Population
+// whose RunContext is run.
+func selectorFor(sel Selector, run *RunContext) Selector {
+	if rs, ok := sel.(runSelector); ok {
+		return rs.withRun(run)
+	}
+	return sel
+}
+
 // SelElitism selection returns the n best individuals of a group.
 type SelElitism struct{}
 
 // Apply SelElitism.
 func (sel SelElitism) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	if _, err := checkFitnesses(indis); err != nil {
+		return nil, nil, err
+	}
 	indis.SortByFitness()
 	return indis[:n].Clone(rng), newInts(n), nil
 }
@@ -56,19 +78,17 @@ func (sel SelTournament) Apply(n uint, indis Individuals, rng *rand.Rand) (Indiv
 			winnerIdx            int
 		)
 		// Find the best contestant
-		winners[i] = indis[contestants[0]]
-		err := winners[i].Evaluate()
-		if err != nil {
-			return nil, nil, err
-		}
-		winnerIdx = idxs[0]
+		var best float64
 		for j, idx := range contestants {
-			if indis[idx].GetFitness() < winners[i].Fitness {
-				winners[i] = indis[idx]
-				indexes[i] = idx
-				winnerIdx = idxs[j]
+			var f, err = fitnessOf(indis, idx)
+			if err != nil {
+				return nil, nil, err
+			}
+			if j == 0 || f < best {
+				best, indexes[i], winnerIdx = f, idx, idxs[j]
 			}
 		}
+		winners[i] = indis[indexes[i]]
 		// Ban the winner from re-participating
 		notSelectedIdxs = append(notSelectedIdxs[:winnerIdx], notSelectedIdxs[winnerIdx+1:]...)
 	}
@@ -84,30 +104,36 @@ func (sel SelTournament) Validate() error {
 }
 
 // SelRoulette samples individuals through roulette wheel selection (also known
-// as fitness proportionate selection).
-type SelRoulette struct{}
+// as fitness proportionate selection). The fitnesses are converted to
+// selection weights with Scaler, ScaleWindowing{Offset: 1} being used if
+// Scaler is nil.
+type SelRoulette struct {
+	Scaler FitnessScaler
+}
 
-func buildWheel(fitnesses []float64) []float64 {
-	var (
-		n     = len(fitnesses)
-		wheel = make([]float64, n)
-	)
-	for i, v := range fitnesses {
-		wheel[i] = fitnesses[n-1] - v + 1
+// buildWheel returns the cumulative selection probabilities of individuals.
+func buildWheel(fitnesses []float64, scaler FitnessScaler) []float64 {
+	if scaler == nil {
+		scaler = ScaleWindowing{Offset: 1}
 	}
+	var wheel = scaleFitnesses(fitnesses, scaler)
 	return cumsum(divide(wheel, sumFloat64s(wheel)))
 }
 
 // Apply SelRoulette.
 func (sel SelRoulette) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	var fitnesses, err = checkFitnesses(indis)
+	if err != nil {
+		return nil, nil, err
+	}
 	var (
 		selected = make(Individuals, n)
 		indexes  = make([]int, n)
-		wheel    = buildWheel(indis.getFitnesses())
+		wheel    = buildWheel(fitnesses, sel.Scaler)
 	)
 	for i := range selected {
 		var (
-			index  = sort.SearchFloat64s(wheel, rng.Float64())
+			index  = minInt(sort.SearchFloat64s(wheel, rng.Float64()), len(indis)-1)
 			winner = indis[index]
 		)
 		indexes[i] = index
@@ -118,5 +144,383 @@ func (sel SelRoulette) Apply(n uint, indis Individuals, rng *rand.Rand) (Individ
 
 // Validate SelRoulette fields.
 func (sel SelRoulette) Validate() error {
+	if sel.Scaler != nil {
+		return sel.Scaler.Validate()
+	}
+	return nil
+}
+
+// A Sizer is a Genome whose size can be measured, for instance the number of
+// nodes of a GPTree. Selectors that control bloat rely on it; Genomes that do
+// not implement it are considered to have a size of 0.
+type Sizer interface {
+	Size() int
+}
+
+func genomeSize(indi Individual) int {
+	if s, ok := unwrapped(indi.Genome).(Sizer); ok {
+		return s.Size()
+	}
+	return 0
+}
+
+// SelLexicographicTournament is a tournament where ties between the fitnesses
+// of the contestants are broken by choosing the smallest contestant, which
+// helps controlling bloat. Individuals may be selected more than once.
+// Reference: https://cs.gmu.edu/~sean/papers/lexicographic.pdf
+type SelLexicographicTournament struct {
+	NContestants uint
+}
+
+// Apply SelLexicographicTournament.
+func (sel SelLexicographicTournament) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	if len(indis) < int(sel.NContestants) {
+		return nil, nil, fmt.Errorf("not enough individuals to hold a tournament with "+
+			"NContestants = %d, have %d individuals", sel.NContestants, len(indis))
+	}
+	var (
+		selected = make(Individuals, n)
+		indexes  = make([]int, n)
+	)
+	for i := range selected {
+		var (
+			contestants = randomInts(sel.NContestants, 0, len(indis), rng)
+			fw          float64
+		)
+		for j, idx := range contestants {
+			var fi, err = fitnessOf(indis, idx)
+			if err != nil {
+				return nil, nil, err
+			}
+			if j == 0 || fi < fw || (fi == fw && genomeSize(indis[idx]) < genomeSize(indis[indexes[i]])) {
+				indexes[i], fw = idx, fi
+			}
+		}
+		selected[i] = indis[indexes[i]]
+	}
+	return selected.Clone(rng), indexes, nil
+}
+
+// Validate SelLexicographicTournament fields.
+func (sel SelLexicographicTournament) Validate() error {
+	if sel.NContestants < 1 {
+		return errors.New("NContestants should be higher than 0")
+	}
+	return nil
+}
+
+// SelDoubleTournament holds two fitness tournaments of NContestants
+// individuals each and then makes both winners compete on size. The smallest
+// winner is chosen with probability ParsimonyPressure / 2, hence
+// ParsimonyPressure should be in [1, 2] where 1 means that size doesn't matter.
+// Individuals may be selected more than once.
+// Reference: https://cs.gmu.edu/~sean/papers/gecco02.pdf
+type SelDoubleTournament struct {
+	NContestants      uint
+	ParsimonyPressure float64
+}
+
+// Apply SelDoubleTournament.
+func (sel SelDoubleTournament) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	var (
+		fitnessSel = SelLexicographicTournament{NContestants: sel.NContestants}
+		selected   = make(Individuals, n)
+		indexes    = make([]int, n)
+	)
+	for i := range selected {
+		var _, idxs, err = fitnessSel.Apply(2, indis, rng)
+		if err != nil {
+			return nil, nil, err
+		}
+		var small, large = idxs[0], idxs[1]
+		if genomeSize(indis[large]) < genomeSize(indis[small]) {
+			small, large = large, small
+		}
+		indexes[i] = large
+		if rng.Float64() < sel.ParsimonyPressure/2 {
+			indexes[i] = small
+		}
+		selected[i] = indis[indexes[i]]
+	}
+	return selected.Clone(rng), indexes, nil
+}
+
+// Validate SelDoubleTournament fields.
+func (sel SelDoubleTournament) Validate() error {
+	if sel.NContestants < 1 {
+		return errors.New("NContestants should be higher than 0")
+	}
+	if sel.ParsimonyPressure < 1 || sel.ParsimonyPressure > 2 {
+		return errors.New("ParsimonyPressure should be between 1 and 2")
+	}
+	return nil
+}
+
+// rankOrder returns the indexes of the individuals sorted by increasing
+// fitness, the first index thus being the one of the best individual.
+func rankOrder(indis Individuals) ([]int, error) {
+	var fitnesses, err = checkFitnesses(indis)
+	if err != nil {
+		return nil, err
+	}
+	return argsortFloat64s(fitnesses), nil
+}
+
+// spinWheel samples n individuals with a probability proportional to their
+// weight. The weights have to be non-negative and their sum has to be
+// positive.
+func spinWheel(n uint, indis Individuals, weights []float64, rng *rand.Rand) (Individuals, []int) {
+	var (
+		selected = make(Individuals, n)
+		indexes  = make([]int, n)
+		wheel    = cumsum(divide(weights, sumFloat64s(weights)))
+	)
+	for i := range selected {
+		indexes[i] = minInt(sort.SearchFloat64s(wheel, rng.Float64()), len(indis)-1)
+		selected[i] = indis[indexes[i]]
+	}
+	return selected.Clone(rng), indexes
+}
+
+// SelLinearRank samples individuals with a probability that decreases linearly
+// with their rank. Pressure is the expected number of times the best
+// individual is selected out of len(indis) draws; it should be in [1, 2]
+// where 1 means that every individual is equally likely to be selected and
+// 2 means that the worst individual is never selected.
+type SelLinearRank struct {
+	Pressure float64
+}
+
+// Apply SelLinearRank.
+func (sel SelLinearRank) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	var order, err = rankOrder(indis)
+	if err != nil {
+		return nil, nil, err
+	}
+	var weights = linearRankWeights(order, sel.Pressure)
+	if sumFloat64s(weights) == 0 {
+		weights[0] = 1
+	}
+	var selected, indexes = spinWheel(n, indis, weights, rng)
+	return selected, indexes, nil
+}
+
+// Validate SelLinearRank fields.
+func (sel SelLinearRank) Validate() error {
+	if sel.Pressure < 1 || sel.Pressure > 2 {
+		return errors.New("Pressure should be between 1 and 2")
+	}
+	return nil
+}
+
+// SelExponentialRank samples individuals with a probability proportional to
+// Base^rank, the best individual having rank 0. Base should be in (0, 1), the
+// lower it is the higher the selection pressure.
+type SelExponentialRank struct {
+	Base float64
+}
+
+// Apply SelExponentialRank.
+func (sel SelExponentialRank) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	var order, err = rankOrder(indis)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		weights = make([]float64, len(indis))
+		w       = 1.0
+	)
+	for _, idx := range order {
+		weights[idx] = w
+		w *= sel.Base
+	}
+	var selected, indexes = spinWheel(n, indis, weights, rng)
+	return selected, indexes, nil
+}
+
+// Validate SelExponentialRank fields.
+func (sel SelExponentialRank) Validate() error {
+	if sel.Base <= 0 || sel.Base >= 1 {
+		return errors.New("Base should be between 0 and 1 (excluded)")
+	}
+	return nil
+}
+
+// SelSUS samples individuals through stochastic universal sampling. The
+// individuals are laid out on the same wheel as SelRoulette, but a single
+// random number is drawn and the n individuals are picked at evenly spaced
+// positions on the wheel. This ensures the number of times an individual is
+// selected is as close as possible to its expected value.
+// Reference: https://dl.acm.org/doi/10.5555/42512.42515
+type SelSUS struct {
+	Scaler FitnessScaler
+}
+
+// Apply SelSUS.
+func (sel SelSUS) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	var fitnesses, err = checkFitnesses(indis)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		selected = make(Individuals, n)
+		indexes  = make([]int, n)
+		wheel    = buildWheel(fitnesses, sel.Scaler)
+		step     = 1 / float64(n)
+		pointer  = rng.Float64() * step
+		index    int
+	)
+	for i := range selected {
+		for index < len(wheel)-1 && wheel[index] < pointer {
+			index++
+		}
+		indexes[i] = index
+		selected[i] = indis[index]
+		pointer += step
+	}
+	// Shuffle the selected individuals so that their order doesn't depend on
+	// their position on the wheel
+	rng.Shuffle(len(selected), func(i, j int) {
+		selected[i], selected[j] = selected[j], selected[i]
+		indexes[i], indexes[j] = indexes[j], indexes[i]
+	})
+	return selected.Clone(rng), indexes, nil
+}
+
+// Validate SelSUS fields.
+func (sel SelSUS) Validate() error {
+	if sel.Scaler != nil {
+		return sel.Scaler.Validate()
+	}
+	return nil
+}
+
+// SelTruncation samples individuals uniformly among the best Proportion of the
+// individuals. Individuals may be selected more than once.
+type SelTruncation struct {
+	Proportion float64
+}
+
+// Apply SelTruncation.
+func (sel SelTruncation) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	var order, err = rankOrder(indis)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		k        = maxInt(int(math.Ceil(sel.Proportion*float64(len(indis)))), 1)
+		selected = make(Individuals, n)
+		indexes  = make([]int, n)
+	)
+	for i := range selected {
+		indexes[i] = order[rng.Intn(k)]
+		selected[i] = indis[indexes[i]]
+	}
+	return selected.Clone(rng), indexes, nil
+}
+
+// Validate SelTruncation fields.
+func (sel SelTruncation) Validate() error {
+	if sel.Proportion <= 0 || sel.Proportion > 1 {
+		return errors.New("Proportion should be in (0, 1]")
+	}
+	return nil
+}
+
+// SelBoltzmann samples individuals with a probability proportional to
+// exp(-(f - fmin) / T), where fmin is the lowest fitness and T is the
+// temperature. A high temperature makes the selection close to uniform
+// whereas a low temperature favors the best individuals. Temperature is called
+// each time the selector is applied with the RunContext of the Population the
+// selector is applied to, which allows implementing a temperature schedule,
+// for example by decreasing the temperature as run.Progress() increases. The
+// RunContext is nil outside of a GA, in which case its methods return zero
+// values.
+type SelBoltzmann struct {
+	Temperature func(run *RunContext) float64
+
+	run *RunContext
+}
+
+func (sel SelBoltzmann) withRun(run *RunContext) Selector {
+	sel.run = run
+	return sel
+}
+
+// Apply SelBoltzmann.
+func (sel SelBoltzmann) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	var t = sel.Temperature(sel.run)
+	if t <= 0 {
+		return nil, nil, fmt.Errorf("the temperature should be positive, got %f", t)
+	}
+	var fitnesses, err = checkFitnesses(indis)
+	if err != nil {
+		return nil, nil, err
+	}
+	var weights = scaleFitnesses(fitnesses, ScaleBoltzmann{Temperature: t})
+	var selected, indexes = spinWheel(n, indis, weights, rng)
+	return selected, indexes, nil
+}
+
+// Validate SelBoltzmann fields.
+func (sel SelBoltzmann) Validate() error {
+	if sel.Temperature == nil {
+		return errors.New("Temperature function has to be provided")
+	}
+	return nil
+}
+
+// SelStochasticTournament holds tournaments of NContestants distinct
+// individuals where the best contestant wins with probability P, the second
+// best with probability P(1-P), and so on, the worst contestant winning if no
+// other contestant did. Contrary to SelTournament, individuals may be selected
+// more than once. With NContestants = 2 this is the classic binary tournament
+// with probability P, and with P = 1 it is a deterministic tournament with
+// replacement.
+type SelStochasticTournament struct {
+	NContestants uint
+	P            float64
+}
+
+// Apply SelStochasticTournament.
+func (sel SelStochasticTournament) Apply(n uint, indis Individuals, rng *rand.Rand) (Individuals, []int, error) {
+	if len(indis) < int(sel.NContestants) {
+		return nil, nil, fmt.Errorf("not enough individuals to hold a tournament with "+
+			"NContestants = %d, have %d individuals", sel.NContestants, len(indis))
+	}
+	var (
+		selected = make(Individuals, n)
+		indexes  = make([]int, n)
+	)
+	for i := range selected {
+		var contestants = randomInts(sel.NContestants, 0, len(indis), rng)
+		for _, idx := range contestants {
+			if _, err := fitnessOf(indis, idx); err != nil {
+				return nil, nil, err
+			}
+		}
+		sort.Slice(contestants, func(a, b int) bool {
+			return indis[contestants[a]].Fitness < indis[contestants[b]].Fitness
+		})
+		indexes[i] = contestants[len(contestants)-1]
+		for _, idx := range contestants[:len(contestants)-1] {
+			if rng.Float64() < sel.P {
+				indexes[i] = idx
+				break
+			}
+		}
+		selected[i] = indis[indexes[i]]
+	}
+	return selected.Clone(rng), indexes, nil
+}
+
+// Validate SelStochasticTournament fields.
+func (sel SelStochasticTournament) Validate() error {
+	if sel.NContestants < 1 {
+		return errors.New("NContestants should be higher than 0")
+	}
+	if sel.P < 0 || sel.P > 1 {
+		return errors.New("P should be between 0 and 1")
+	}
 	return nil
 }
@@ -2,6 +2,9 @@ package eaopt
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
 	"testing"
 )
 
@@ -10,9 +13,24 @@ var (
 		SelElitism{},
 		SelTournament{3},
 		SelRoulette{},
+		SelLinearRank{1.5},
+		SelExponentialRank{0.9},
+		SelSUS{},
+		SelTruncation{0.3},
+		SelBoltzmann{Temperature: func(run *RunContext) float64 { return 1 }},
+		SelStochasticTournament{2, 0.8},
 	}
 	invalidSelectors = []Selector{
 		SelTournament{0},
+		SelLinearRank{0.5},
+		SelLinearRank{2.5},
+		SelExponentialRank{0},
+		SelExponentialRank{1},
+		SelTruncation{0},
+		SelTruncation{1.5},
+		SelBoltzmann{},
+		SelStochasticTournament{0, 0.8},
+		SelStochasticTournament{2, 1.5},
 	}
 )
 
@@ -111,7 +129,7 @@ func TestBuildWheel(t *testing.T) {
 	}
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("TC %d", i), func(t *testing.T) {
-			var weights = buildWheel(tc.fitnesses)
+			var weights = buildWheel(tc.fitnesses, nil)
 			for i := range weights {
 				if weights[i] != tc.weights[i] {
 					t.Error("buildWheel didn't work as expected")
@@ -152,3 +170,264 @@ func TestSelectorsValidate(t *testing.T) {
 		}
 	}
 }
+
+type sizedVector struct {
+	Vector
+	size int
+}
+
+func (sv sizedVector) Size() int { return sv.size }
+
+func TestSelLexicographicTournament(t *testing.T) {
+	var (
+		rng   = newRand()
+		indis = Individuals{
+			{Genome: sizedVector{size: 10}, Fitness: 1, Evaluated: true},
+			{Genome: sizedVector{size: 3}, Fitness: 1, Evaluated: true},
+			{Genome: sizedVector{size: 1}, Fitness: 2, Evaluated: true},
+		}
+	)
+	var _, indexes, err = SelLexicographicTournament{3}.Apply(5, indis, rng)
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	for _, idx := range indexes {
+		if idx != 1 {
+			t.Errorf("Expected 1, got %d", idx)
+		}
+	}
+	if _, _, err = (SelLexicographicTournament{4}).Apply(1, indis, rng); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestSelDoubleTournament(t *testing.T) {
+	var (
+		rng   = newRand()
+		indis = Individuals{
+			{Genome: sizedVector{size: 10}, Fitness: 1, Evaluated: true},
+			{Genome: sizedVector{size: 1}, Fitness: 2, Evaluated: true},
+		}
+	)
+	// With a pressure of 1 the size doesn't matter
+	var _, indexes, err = SelDoubleTournament{2, 1}.Apply(20, indis, rng)
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	for _, idx := range indexes {
+		if idx != 0 {
+			t.Errorf("Expected 0, got %d", idx)
+		}
+	}
+	// With a pressure of 2 the smallest of the two fitness winners is always
+	// chosen, hence the largest individual is only chosen if it wins both
+	// fitness tournaments
+	_, indexes, _ = SelDoubleTournament{1, 2}.Apply(200, indis, rng)
+	if sumInts(indexes) < 100 {
+		t.Errorf("Expected the smallest individual to be selected most of the time, got %d/200", sumInts(indexes))
+	}
+	if (SelDoubleTournament{1, 3}).Validate() == nil || (SelDoubleTournament{0, 1}).Validate() == nil {
+		t.Error("Expected error")
+	}
+}
+
+// selectionCounts applies a Selector and counts how many times each individual
+// is selected. The RNG is seeded so that the counts are reproducible.
+func selectionCounts(t *testing.T, sel Selector, n uint, indis Individuals) []int {
+	var selected, indexes, err = sel.Apply(n, indis, rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if len(selected) != int(n) || len(indexes) != int(n) {
+		t.Fatalf("Expected %d individuals, got %d", n, len(selected))
+	}
+	var counts = make([]int, len(indis))
+	for i, idx := range indexes {
+		if selected[i].Fitness != indis[idx].Fitness {
+			t.Errorf("Index %d doesn't match the selected individual", idx)
+		}
+		counts[idx]++
+	}
+	return counts
+}
+
+// newRankedIndividuals returns n evaluated individuals with fitnesses
+// n-1, ..., 1, 0, in other words sorted from worst to best.
+func newRankedIndividuals(n int) Individuals {
+	var indis = make(Individuals, n)
+	for i := range indis {
+		indis[i] = Individual{Genome: Vector{}, Fitness: float64(n - 1 - i), Evaluated: true}
+	}
+	return indis
+}
+
+func TestSelLinearRank(t *testing.T) {
+	var indis = newRankedIndividuals(4)
+	// The worst individual is never selected with a pressure of 2
+	var counts = selectionCounts(t, SelLinearRank{2}, 1000, indis)
+	if counts[0] != 0 || counts[3] <= counts[2] || counts[2] <= counts[1] {
+		t.Errorf("Unexpected counts %v", counts)
+	}
+	// A pressure of 1 is uniform
+	counts = selectionCounts(t, SelLinearRank{1}, 1000, indis)
+	for _, c := range counts {
+		if c < 200 || c > 300 {
+			t.Errorf("Expected uniform counts, got %v", counts)
+		}
+	}
+	// A single individual can always be selected
+	counts = selectionCounts(t, SelLinearRank{2}, 3, indis[:1])
+	if counts[0] != 3 {
+		t.Errorf("Expected 3, got %d", counts[0])
+	}
+}
+
+func TestSelExponentialRank(t *testing.T) {
+	var counts = selectionCounts(t, SelExponentialRank{0.1}, 1000, newRankedIndividuals(3))
+	// The expected proportions are 100/111, 10/111 and 1/111
+	if counts[2] < 850 || counts[1] > 150 || counts[0] > 30 {
+		t.Errorf("Unexpected counts %v", counts)
+	}
+}
+
+func TestSelSUS(t *testing.T) {
+	// Sorted fitnesses 0, 1 and 2 give weights 3, 2 and 1 on the wheel, hence
+	// the counts are exactly 3/6, 2/6 and 1/6 of the number of selected
+	// individuals
+	var indis = newRankedIndividuals(3)
+	indis.SortByFitness()
+	var counts = selectionCounts(t, SelSUS{}, 60, indis)
+	if counts[0] != 30 || counts[1] != 20 || counts[2] != 10 {
+		t.Errorf("Expected [30 20 10], got %v", counts)
+	}
+}
+
+func TestSelTruncation(t *testing.T) {
+	var counts = selectionCounts(t, SelTruncation{0.5}, 100, newRankedIndividuals(4))
+	if counts[0] != 0 || counts[1] != 0 || counts[2] == 0 || counts[3] == 0 {
+		t.Errorf("Only the best half should be selected, got %v", counts)
+	}
+	// At least one individual is kept
+	counts = selectionCounts(t, SelTruncation{0.01}, 10, newRankedIndividuals(4))
+	if counts[3] != 10 {
+		t.Errorf("Only the best individual should be selected, got %v", counts)
+	}
+}
+
+func TestSelBoltzmann(t *testing.T) {
+	var (
+		indis = newRankedIndividuals(3)
+		temp  = 100.0
+		sel   = SelBoltzmann{Temperature: func(run *RunContext) float64 { return temp }}
+	)
+	// A high temperature is close to uniform
+	var counts = selectionCounts(t, sel, 900, indis)
+	for _, c := range counts {
+		if c < 250 || c > 350 {
+			t.Errorf("Expected uniform counts, got %v", counts)
+		}
+	}
+	// A low temperature selects the best individual
+	temp = 0.01
+	if counts = selectionCounts(t, sel, 100, indis); counts[2] != 100 {
+		t.Errorf("Expected the best individual to be selected, got %v", counts)
+	}
+	temp = 0
+	if _, _, err := sel.Apply(1, indis, newRand()); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestSelBoltzmannRunContext(t *testing.T) {
+	var (
+		progress []float64
+		sel      = SelBoltzmann{Temperature: func(run *RunContext) float64 {
+			progress = append(progress, run.Progress())
+			return 1
+		}}
+	)
+	// Outside of a GA the RunContext is nil
+	selectionCounts(t, sel, 1, newRankedIndividuals(2))
+	if len(progress) != 1 || progress[0] != 0 {
+		t.Errorf("Expected [0], got %v", progress)
+	}
+	progress = nil
+	var ga, err = GAConfig{
+		NPops:        1,
+		PopSize:      4,
+		NGenerations: 4,
+		HofSize:      1,
+		Model:        ModSteadyState{Selector: sel},
+		RNG:          newRand(),
+	}.NewGA()
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if err = ga.Minimize(NewVector); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if !reflect.DeepEqual(progress, []float64{0.25, 0.5, 0.75, 1}) {
+		t.Errorf("Expected the progress of the run, got %v", progress)
+	}
+}
+
+func TestSelStochasticTournament(t *testing.T) {
+	var indis = newRankedIndividuals(2)
+	// With P = 1 the best contestant always wins and repetitions are allowed
+	var counts = selectionCounts(t, SelStochasticTournament{2, 1}, 10, indis)
+	if counts[1] != 10 {
+		t.Errorf("Expected [0 10], got %v", counts)
+	}
+	// With P = 0 the worst contestant always wins
+	counts = selectionCounts(t, SelStochasticTournament{2, 0}, 10, indis)
+	if counts[0] != 10 {
+		t.Errorf("Expected [10 0], got %v", counts)
+	}
+	counts = selectionCounts(t, SelStochasticTournament{2, 0.75}, 1000, indis)
+	if counts[1] < 700 || counts[1] > 800 {
+		t.Errorf("Expected the best individual to win 75%% of the time, got %v", counts)
+	}
+	if _, _, err := (SelStochasticTournament{3, 1}).Apply(1, indis, newRand()); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestSelectorsRejectNaN(t *testing.T) {
+	var (
+		indis     = newRankedIndividuals(3)
+		selectors = []Selector{
+			SelElitism{},
+			SelTournament{NContestants: 3},
+			SelRoulette{},
+			SelLexicographicTournament{NContestants: 3},
+			SelDoubleTournament{NContestants: 3, ParsimonyPressure: 1.4},
+			SelSUS{},
+			SelLinearRank{Pressure: 1.5},
+			SelExponentialRank{Base: 0.5},
+			SelTruncation{Proportion: 0.5},
+			SelBoltzmann{Temperature: func(run *RunContext) float64 { return 1 }},
+			SelStochasticTournament{NContestants: 3, P: 0.5},
+		}
+	)
+	// Every tournament contains the individual with a NaN fitness
+	indis[1].Fitness = math.NaN()
+	for _, sel := range selectors {
+		if _, _, err := sel.Apply(1, indis, newRand()); err == nil {
+			t.Errorf("%T: expected error", sel)
+		}
+	}
+}
+
+func TestSelTournamentIndexes(t *testing.T) {
+	var indis = newRankedIndividuals(10)
+	var selected, indexes, err = SelTournament{NContestants: 1}.Apply(5, indis, newRand())
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	// The first contestant can win, in which case its index is returned too
+	for i := range selected {
+		if selected[i].Fitness != indis[indexes[i]].Fitness {
+			t.Errorf("Index %d doesn't match the selected individual", indexes[i])
+		}
+	}
+}
@@ -15,10 +15,27 @@ type Slice interface {
 	Copy() Slice
 }
 
-// Search for the first index of an element in a Slice.
-func search(v interface{}, s Slice) (int, error) {
-	for i := 0; i < s.Len(); i++ {
-		if s.At(i) == v {
+// boxSlice copies the genes of a Slice into a []interface{} so that the
+// generic operators can be applied to any Slice implementation.
+func boxSlice(s Slice) []interface{} {
+	var genes = make([]interface{}, s.Len())
+	for i := range genes {
+		genes[i] = s.At(i)
+	}
+	return genes
+}
+
+// unboxSlice writes genes back into a Slice.
+func unboxSlice(s Slice, genes []interface{}) {
+	for i, v := range genes {
+		s.Set(i, v)
+	}
+}
+
+// Search for the first index of an element in a slice.
+func search[T comparable](v T, s []T) (int, error) {
+	for i := range s {
+		if s[i] == v {
 			return i, nil
 		}
 	}
@@ -27,33 +44,33 @@ func search(v interface{}, s Slice) (int, error) {
 }
 
 // Make a lookup table from a slice, mapping values to indexes.
-func newIndexLookup(s Slice) map[interface{}]int {
-	var lookup = make(map[interface{}]int)
-	for i := 0; i < s.Len(); i++ {
-		lookup[s.At(i)] = i
+func newIndexLookup[T comparable](s []T) map[T]int {
+	var lookup = make(map[T]int, len(s))
+	for i, v := range s {
+		lookup[v] = i
 	}
 	return lookup
 }
 
 // getCycles determines the cycles that exist between two slices. A cycle is a
 // list of indexes indicating mirroring values between each slice.
-func getCycles(s1, s2 Slice) (cycles [][]int) {
+func getCycles[T comparable](s1, s2 []T) (cycles [][]int) {
 	var (
-		s1Lookup = newIndexLookup(s1) // Matches values to indexes for quick lookup
-		visited  = make(map[int]bool) // Indicates if an index is already in a cycle or not
+		s1Lookup = newIndexLookup(s1)    // Matches values to indexes for quick lookup
+		visited  = make([]bool, len(s1)) // Indicates if an index is already in a cycle or not
 	)
-	for i := 0; i < s1.Len(); i++ {
+	for i := range s1 {
 		if !visited[i] {
 			visited[i] = true
 			var (
 				cycle = []int{i}
-				j     = s1Lookup[s2.At(i)]
+				j     = s1Lookup[s2[i]]
 			)
 			// Continue building the cycle until it closes in on itself
 			for j != cycle[0] {
 				cycle = append(cycle, j)
 				visited[j] = true
-				j = s1Lookup[s2.At(j)]
+				j = s1Lookup[s2[j]]
 			}
 			cycles = append(cycles, cycle)
 		}
@@ -63,165 +80,74 @@ func getCycles(s1, s2 Slice) (cycles [][]int) {
 
 // getNeighbours converts a slice into an adjacency map mapping values to left
 // and right neighbours. The values of the map are sets.
-func getNeighbours(s Slice) map[interface{}]set {
+func getNeighbours[T comparable](s []T) map[T]map[T]bool {
 	var (
-		neighbours = make(map[interface{}]set)
-		n          = s.Len()
+		neighbours = make(map[T]map[T]bool, len(s))
+		n          = len(s)
 	)
-	neighbours[s.At(0)] = set{s.At(n - 1): true, s.At(1): true}
+	neighbours[s[0]] = map[T]bool{s[n-1]: true, s[1]: true}
 	for i := 1; i < n-1; i++ {
-		neighbours[s.At(i)] = set{s.At(i - 1): true, s.At(i + 1): true}
+		neighbours[s[i]] = map[T]bool{s[i-1]: true, s[i+1]: true}
 	}
-	neighbours[s.At(n-1)] = set{s.At(n - 2): true, s.At(0): true}
+	neighbours[s[n-1]] = map[T]bool{s[n-2]: true, s[0]: true}
 	return neighbours
 }
 
-// IntSlice attaches the methods of Slice to []float64
-type IntSlice []int
+// SliceOf attaches the methods of Slice to []T.
+type SliceOf[T comparable] []T
 
 // At method from Slice
-func (s IntSlice) At(i int) interface{} {
+func (s SliceOf[T]) At(i int) interface{} {
 	return s[i]
 }
 
 // Set method from Slice
-func (s IntSlice) Set(i int, v interface{}) {
-	s[i] = v.(int)
+func (s SliceOf[T]) Set(i int, v interface{}) {
+	s[i] = v.(T)
 }
 
 // Len method from Slice
-func (s IntSlice) Len() int {
+func (s SliceOf[T]) Len() int {
 	return len(s)
 }
 
 // Swap method from Slice
-func (s IntSlice) Swap(i, j int) {
+func (s SliceOf[T]) Swap(i, j int) {
 	s[i], s[j] = s[j], s[i]
 }
 
 // Slice method from Slice
-func (s IntSlice) Slice(a, b int) Slice {
+func (s SliceOf[T]) Slice(a, b int) Slice {
 	return s[a:b]
 }
 
 // Split method from Slice
-func (s IntSlice) Split(k int) (Slice, Slice) {
+func (s SliceOf[T]) Split(k int) (Slice, Slice) {
 	return s[:k], s[k:]
 }
 
 // Append method from Slice
-func (s IntSlice) Append(t Slice) Slice {
-	return append(s, t.(IntSlice)...)
+func (s SliceOf[T]) Append(t Slice) Slice {
+	return append(s, t.(SliceOf[T])...)
 }
 
 // Replace method from Slice
-func (s IntSlice) Replace(t Slice) {
-	copy(s, t.(IntSlice))
+func (s SliceOf[T]) Replace(t Slice) {
+	copy(s, t.(SliceOf[T]))
 }
 
 // Copy method from Slice
-func (s IntSlice) Copy() Slice {
-	var t = make(IntSlice, len(s))
+func (s SliceOf[T]) Copy() Slice {
+	var t = make(SliceOf[T], len(s))
 	copy(t, s)
 	return t
 }
 
-// Float64Slice attaches the methods of Slice to []float64
-type Float64Slice []float64
-
-// At method from Slice
-func (s Float64Slice) At(i int) interface{} {
-	return s[i]
-}
-
-// Set method from Slice
-func (s Float64Slice) Set(i int, v interface{}) {
-	s[i] = v.(float64)
-}
-
-// Len method from Slice
-func (s Float64Slice) Len() int {
-	return len(s)
-}
-
-// Swap method from Slice
-func (s Float64Slice) Swap(i, j int) {
-	s[i], s[j] = s[j], s[i]
-}
-
-// Slice method from Slice
-func (s Float64Slice) Slice(a, b int) Slice {
-	return s[a:b]
-}
+// IntSlice attaches the methods of Slice to []int
+type IntSlice = SliceOf[int]
 
-// Split method from Slice
-func (s Float64Slice) Split(k int) (Slice, Slice) {
-	return s[:k], s[k:]
-}
-
-// Append method from Slice
-func (s Float64Slice) Append(t Slice) Slice {
-	return append(s, t.(Float64Slice)...)
-}
-
-// Replace method from Slice
-func (s Float64Slice) Replace(t Slice) {
-	copy(s, t.(Float64Slice))
-}
-
-// Copy method from Slice
-func (s Float64Slice) Copy() Slice {
-	var t = make(Float64Slice, len(s))
-	copy(t, s)
-	return t
-}
-
-// StringSlice attaches the methods of Slice to []float64
-type StringSlice []string
-
-// At method from Slice
-func (s StringSlice) At(i int) interface{} {
-	return s[i]
-}
-
-// Set method from Slice
-func (s StringSlice) Set(i int, v interface{}) {
-	s[i] = v.(string)
-}
-
-// Len method from Slice
-func (s StringSlice) Len() int {
-	return len(s)
-}
-
-// Swap method from Slice
-func (s StringSlice) Swap(i, j int) {
-	s[i], s[j] = s[j], s[i]
-}
-
-// Slice method from Slice
-func (s StringSlice) Slice(a, b int) Slice {
-	return s[a:b]
-}
-
-// Split method from Slice
-func (s StringSlice) Split(k int) (Slice, Slice) {
-	return s[:k], s[k:]
-}
-
-// Append method from Slice
-func (s StringSlice) Append(t Slice) Slice {
-	return append(s, t.(StringSlice)...)
-}
-
-// Replace method from Slice
-func (s StringSlice) Replace(t Slice) {
-	copy(s, t.(StringSlice))
-}
+// Float64Slice attaches the methods of Slice to []float64
+type Float64Slice = SliceOf[float64]
 
-// Copy method from Slice
-func (s StringSlice) Copy() Slice {
-	var t = make(StringSlice, len(s))
-	copy(t, s)
-	return t
-}
+// StringSlice attaches the methods of Slice to []string
+type StringSlice = SliceOf[string]
@@ -26,12 +26,12 @@ func TestSearch(t *testing.T) {
 
 func TestNewIndexLookup(t *testing.T) {
 	var testCases = []struct {
-		slice  Slice
-		lookup map[interface{}]int
+		slice  []int
+		lookup map[int]int
 	}{
 		{
-			slice: IntSlice{1, 2, 3},
-			lookup: map[interface{}]int{
+			slice: []int{1, 2, 3},
+			lookup: map[int]int{
 				1: 0,
 				2: 1,
 				3: 2,
@@ -82,12 +82,12 @@ func TestGetCycles(t *testing.T) {
 
 func TestGetNeighbours(t *testing.T) {
 	var testCases = []struct {
-		x          Slice
-		neighbours map[interface{}]set
+		x          []int
+		neighbours map[int]map[int]bool
 	}{
 		{
-			x: IntSlice{1, 2, 3, 4, 5, 6, 7, 8, 9},
-			neighbours: map[interface{}]set{
+			x: []int{1, 2, 3, 4, 5, 6, 7, 8, 9},
+			neighbours: map[int]map[int]bool{
 				1: {9: true, 2: true},
 				2: {1: true, 3: true},
 				3: {2: true, 4: true},
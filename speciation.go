@@ -3,7 +3,9 @@ package eaopt
 import (
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
+	"sync"
 )
 
 // A Speciator partitions a population into n smaller subpopulations. Each
@@ -138,3 +140,494 @@ func (spec SpecFitnessInterval) Validate() error {
 	}
 	return nil
 }
+
+// SpecThreshold assigns each individual to the first species whose
+// representative is within Threshold of it according to Metric, else the
+// individual founds a new species and becomes its representative. The
+// individuals are processed in the order they are given, which means the best
+// individuals become representatives if the population is sorted by fitness.
+// If NSpecies is higher than 0 then the threshold is adjusted by Step after
+// each call to Apply in order to reach NSpecies species: it increases if there
+// are too many species and decreases if there are too few, without going under
+// Step. SpecThreshold is stateful, it should hence be used through a pointer.
+type SpecThreshold struct {
+	Metric    Metric
+	Threshold float64 // Initial threshold
+	NSpecies  uint    // Target number of species, 0 means no adjustment
+	Step      float64
+
+	mu        sync.Mutex
+	threshold float64
+}
+
+// CurrentThreshold returns the threshold used by the next call to Apply.
+func (spec *SpecThreshold) CurrentThreshold() float64 {
+	spec.mu.Lock()
+	defer spec.mu.Unlock()
+	if spec.threshold == 0 {
+		return spec.Threshold
+	}
+	return spec.threshold
+}
+
+// Apply SpecThreshold.
+func (spec *SpecThreshold) Apply(indis Individuals, rng *rand.Rand) ([]Individuals, error) {
+	var (
+		threshold = spec.CurrentThreshold()
+		dm        = newDistanceMemoizer(spec.Metric)
+		reps      Individuals
+		species   []Individuals
+	)
+	for _, indi := range indis {
+		var found bool
+		for i, rep := range reps {
+			if dm.GetDistance(indi, rep) < threshold {
+				species[i] = append(species[i], indi)
+				found = true
+				break
+			}
+		}
+		if !found {
+			reps = append(reps, indi)
+			species = append(species, Individuals{indi})
+		}
+	}
+	if spec.NSpecies > 0 {
+		switch {
+		case len(species) > int(spec.NSpecies):
+			threshold += spec.Step
+		case len(species) < int(spec.NSpecies):
+			threshold = math.Max(threshold-spec.Step, spec.Step)
+		}
+		spec.mu.Lock()
+		spec.threshold = threshold
+		spec.mu.Unlock()
+	}
+	return species, nil
+}
+
+// Validate SpecThreshold fields.
+func (spec *SpecThreshold) Validate() error {
+	if spec.Metric == nil {
+		return errors.New("metric field has to be provided")
+	}
+	if spec.Threshold <= 0 {
+		return errors.New("threshold should be positive")
+	}
+	if spec.NSpecies > 0 && spec.Step <= 0 {
+		return errors.New("step should be positive")
+	}
+	return nil
+}
+
+// SpecDBSCAN implements density-based spatial clustering. An individual is a
+// core individual if at least MinPts individuals, itself included, are within
+// Eps of it according to Metric. Core individuals that are within Eps of each
+// other belong to the same species, along with the non-core individuals that
+// are within Eps of them. Noise individuals, which are not within Eps of any
+// core individual, join the species of the closest core individual. If there
+// are no core individuals then all the individuals are put in a single
+// species. The number of species doesn't have to be known in advance.
+// Reference: Ester, M., Kriegel, H. P., Sander, J., & Xu, X. (1996), A
+// density-based algorithm for discovering clusters in large spatial databases
+// with noise
+type SpecDBSCAN struct {
+	Metric Metric
+	Eps    float64
+	MinPts uint
+}
+
+// Apply SpecDBSCAN.
+func (spec SpecDBSCAN) Apply(indis Individuals, rng *rand.Rand) ([]Individuals, error) {
+	var (
+		dm         = newDistanceMemoizer(spec.Metric)
+		neighbours = make([][]int, len(indis))
+		labels     = make([]int, len(indis))
+		cores      []int
+		nClusters  int
+	)
+	for i := range indis {
+		labels[i] = -1
+		for j := range indis {
+			if dm.GetDistance(indis[i], indis[j]) <= spec.Eps {
+				neighbours[i] = append(neighbours[i], j)
+			}
+		}
+		if len(neighbours[i]) >= int(spec.MinPts) {
+			cores = append(cores, i)
+		}
+	}
+	if len(cores) == 0 {
+		return []Individuals{indis}, nil
+	}
+	var isCore = make(map[int]bool, len(cores))
+	for _, i := range cores {
+		isCore[i] = true
+	}
+	// Expand a cluster from each core individual that hasn't been labelled yet
+	for _, c := range cores {
+		if labels[c] != -1 {
+			continue
+		}
+		var queue = []int{c}
+		labels[c] = nClusters
+		for len(queue) > 0 {
+			var i = queue[0]
+			queue = queue[1:]
+			if !isCore[i] {
+				continue
+			}
+			for _, j := range neighbours[i] {
+				if labels[j] == -1 {
+					labels[j] = nClusters
+					queue = append(queue, j)
+				}
+			}
+		}
+		nClusters++
+	}
+	// Assign the noise to the closest core individual
+	var species = make([]Individuals, nClusters)
+	for i, indi := range indis {
+		if labels[i] == -1 {
+			var closest, min = 0, math.Inf(1)
+			for _, c := range cores {
+				if d := dm.GetDistance(indi, indis[c]); d < min {
+					closest, min = c, d
+				}
+			}
+			labels[i] = labels[closest]
+		}
+		species[labels[i]] = append(species[labels[i]], indi)
+	}
+	return species, nil
+}
+
+// Validate SpecDBSCAN fields.
+func (spec SpecDBSCAN) Validate() error {
+	if spec.Metric == nil {
+		return errors.New("metric field has to be provided")
+	}
+	if spec.Eps <= 0 {
+		return errors.New("eps should be positive")
+	}
+	if spec.MinPts < 1 {
+		return errors.New("minPts should be higher than 0")
+	}
+	return nil
+}
+
+// A Linkage determines the distance between two clusters of individuals in
+// hierarchical clustering.
+type Linkage int
+
+// Available linkages.
+const (
+	LinkageSingle   Linkage = iota // Distance between the closest individuals
+	LinkageComplete                // Distance between the farthest individuals
+	LinkageAverage                 // Average distance between the individuals
+)
+
+// SpecHierarchical implements agglomerative hierarchical clustering. Each
+// individual starts in its own species and the two closest species, according
+// to Metric and Linkage, are merged until there are K species left or until
+// the two closest species are farther than MaxDistance from each other. At
+// least one of K and MaxDistance has to be provided. Contrary to SpecKMedoids,
+// if there are less than K individuals then each individual has its own
+// species.
+type SpecHierarchical struct {
+	Metric      Metric
+	Linkage     Linkage
+	K           uint
+	MaxDistance float64
+}
+
+// Apply SpecHierarchical.
+func (spec SpecHierarchical) Apply(indis Individuals, rng *rand.Rand) ([]Individuals, error) {
+	var (
+		matrix   = NewDistanceMatrix(indis, spec.Metric, false)
+		clusters = singletons(indis)
+		// The distances of a cluster are stored in the row of the matrix of
+		// the first Individual it contained
+		rows = newInts(uint(len(indis)))
+	)
+	for len(clusters) > 1 && len(clusters) > int(spec.K) {
+		// Find the two closest clusters
+		var a, b, min = 0, 0, math.Inf(1)
+		for i := range clusters {
+			for j := i + 1; j < len(clusters); j++ {
+				if d := matrix.At(rows[i], rows[j]); d < min {
+					a, b, min = i, j, d
+				}
+			}
+		}
+		if spec.MaxDistance > 0 && min > spec.MaxDistance {
+			break
+		}
+		// Update the distances of the merged cluster with the Lance-Williams
+		// formula of the linkage
+		var na, nb = float64(len(clusters[a])), float64(len(clusters[b]))
+		for k := range clusters {
+			if k == a || k == b {
+				continue
+			}
+			var (
+				da = matrix.At(rows[a], rows[k])
+				db = matrix.At(rows[b], rows[k])
+				d  float64
+			)
+			switch spec.Linkage {
+			case LinkageSingle:
+				d = math.Min(da, db)
+			case LinkageComplete:
+				d = math.Max(da, db)
+			default:
+				d = (na*da + nb*db) / (na + nb)
+			}
+			matrix.set(rows[a], rows[k], d)
+		}
+		clusters[a] = append(clusters[a], clusters[b]...)
+		// Remove cluster b
+		clusters = append(clusters[:b], clusters[b+1:]...)
+		rows = append(rows[:b], rows[b+1:]...)
+	}
+	return clusters, nil
+}
+
+// Validate SpecHierarchical fields.
+func (spec SpecHierarchical) Validate() error {
+	if spec.Metric == nil {
+		return errors.New("metric field has to be provided")
+	}
+	if spec.Linkage < LinkageSingle || spec.Linkage > LinkageAverage {
+		return errors.New("unknown linkage")
+	}
+	if spec.MaxDistance < 0 {
+		return errors.New("maxDistance should be positive")
+	}
+	if spec.K == 0 && spec.MaxDistance == 0 {
+		return errors.New("either k or maxDistance has to be provided")
+	}
+	return nil
+}
+
+// assignToMedoids assigns each individual to its closest medoid, the medoids
+// being given as indexes of indis. Each medoid is assigned to itself. The
+// total distance between the individuals and their medoids is returned along
+// with the species.
+func assignToMedoids(indis Individuals, medoids []int, metric Metric) ([]Individuals, float64) {
+	var (
+		species  = make([]Individuals, len(medoids))
+		isMedoid = make(map[int]int, len(medoids))
+		total    float64
+	)
+	for k, m := range medoids {
+		isMedoid[m] = k
+		species[k] = Individuals{indis[m]}
+	}
+	for i, indi := range indis {
+		if _, ok := isMedoid[i]; ok {
+			continue
+		}
+		var closest, min = 0, math.Inf(1)
+		for k, m := range medoids {
+			if d := metric(indi, indis[m]); d < min {
+				closest, min = k, d
+			}
+		}
+		species[closest] = append(species[closest], indi)
+		total += min
+	}
+	return species, total
+}
+
+// singletons puts each individual in its own species.
+func singletons(indis Individuals) []Individuals {
+	var species = make([]Individuals, len(indis))
+	for i, indi := range indis {
+		species[i] = Individuals{indi}
+	}
+	return species
+}
+
+// SpecCLARA implements CLARA (Clustering LARge Applications), a sampled
+// version of k-medoids clustering for large populations. NSamples samples of
+// SampleSize individuals are drawn and k-medoids clustering is run on each
+// one of them with a dense DistanceMatrix, which is computed in parallel if
+// Parallel is true. Each set of medoids is then evaluated on the whole
+// population by assigning every individual to its closest medoid, and the set
+// with the lowest total distance is kept. Only NSamples * SampleSize^2 / 2 +
+// NSamples * K * n distances are computed, instead of n^2 / 2 for
+// SpecKMedoids. If there are less than K individuals then each individual has
+// its own species.
+// Reference: Kaufman, L., & Rousseeuw, P. J. (1990), Finding groups in data:
+// An introduction to cluster analysis
+type SpecCLARA struct {
+	K             uint
+	Metric        Metric
+	NSamples      uint
+	SampleSize    uint // Typically 40 + 2 * K
+	MaxIterations uint
+	Parallel      bool
+}
+
+// Apply SpecCLARA.
+func (spec SpecCLARA) Apply(indis Individuals, rng *rand.Rand) ([]Individuals, error) {
+	if len(indis) <= int(spec.K) {
+		return singletons(indis), nil
+	}
+	var (
+		best    []Individuals
+		minCost = math.Inf(1)
+	)
+	for s := uint(0); s < spec.NSamples; s++ {
+		var (
+			idxs   = randomInts(minUint(maxUint(spec.SampleSize, spec.K), uint(len(indis))), 0, len(indis), rng)
+			sample = make(Individuals, len(idxs))
+		)
+		for i, idx := range idxs {
+			sample[i] = indis[idx]
+		}
+		var (
+			medoids = kMedoidsSample(NewDistanceMatrix(sample, spec.Metric, spec.Parallel), spec.K, spec.MaxIterations, rng)
+			global  = make([]int, len(medoids))
+		)
+		for k, m := range medoids {
+			global[k] = idxs[m]
+		}
+		if species, cost := assignToMedoids(indis, global, spec.Metric); cost < minCost {
+			best, minCost = species, cost
+		}
+	}
+	return best, nil
+}
+
+// kMedoidsSample runs k-medoids clustering with alternating assignment and
+// medoid updates on the individuals of a DistanceMatrix. The initial medoids
+// are drawn at random. The indexes of the medoids are returned.
+func kMedoidsSample(dm DistanceMatrix, k, maxIterations uint, rng *rand.Rand) []int {
+	var medoids = randomInts(k, 0, dm.Len(), rng)
+	for it := uint(0); it < maxIterations; it++ {
+		var clusters = make([][]int, k)
+		for i := 0; i < dm.Len(); i++ {
+			var closest, min = 0, math.Inf(1)
+			for c, m := range medoids {
+				if m == i {
+					closest = c
+					break
+				}
+				if d := dm.At(i, m); d < min {
+					closest, min = c, d
+				}
+			}
+			clusters[closest] = append(clusters[closest], i)
+		}
+		var changed bool
+		for c, cluster := range clusters {
+			if m := dm.Medoid(cluster); m != medoids[c] {
+				medoids[c] = m
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return medoids
+}
+
+// Validate SpecCLARA fields.
+func (spec SpecCLARA) Validate() error {
+	if spec.K < 2 {
+		return errors.New("k should be higher than 1")
+	}
+	if spec.Metric == nil {
+		return errors.New("metric field has to be provided")
+	}
+	if spec.NSamples < 1 {
+		return errors.New("nSamples should be higher than 0")
+	}
+	if spec.SampleSize < spec.K {
+		return errors.New("sampleSize should be at least k")
+	}
+	if spec.MaxIterations < 1 {
+		return errors.New("maxIterations should be higher than 0")
+	}
+	return nil
+}
+
+// SpecCLARANS implements CLARANS (Clustering Large Applications based on
+// RANdomized Search), which searches for good medoids without computing the
+// full distance matrix. Starting from random medoids, a random medoid is
+// swapped with a random non-medoid and the swap is kept if it lowers the total
+// distance between the individuals and their closest medoids. The search stops
+// once MaxNeighbours swaps in a row have failed, and it is restarted NumLocal
+// times from different medoids, the best medoids being kept. If there are less
+// than K individuals then each individual has its own species.
+// Reference: Ng, R. T., & Han, J. (2002), CLARANS: A method for clustering
+// objects for spatial data mining
+type SpecCLARANS struct {
+	K             uint
+	Metric        Metric
+	NumLocal      uint
+	MaxNeighbours uint
+}
+
+// Apply SpecCLARANS.
+func (spec SpecCLARANS) Apply(indis Individuals, rng *rand.Rand) ([]Individuals, error) {
+	if len(indis) <= int(spec.K) {
+		return singletons(indis), nil
+	}
+	var (
+		dm      = newDistanceMemoizer(spec.Metric)
+		metric  = dm.GetDistance
+		best    []Individuals
+		minCost = math.Inf(1)
+	)
+	for l := uint(0); l < spec.NumLocal; l++ {
+		var (
+			medoids       = randomInts(spec.K, 0, len(indis), rng)
+			species, cost = assignToMedoids(indis, medoids, metric)
+		)
+		for failures := uint(0); failures < spec.MaxNeighbours; {
+			// Swap a random medoid with a random non-medoid
+			var (
+				neighbour = append([]int(nil), medoids...)
+				k         = rng.Intn(len(medoids))
+				candidate = rng.Intn(len(indis))
+			)
+			if containsInt(medoids, candidate) {
+				failures++
+				continue
+			}
+			neighbour[k] = candidate
+			if s, c := assignToMedoids(indis, neighbour, metric); c < cost {
+				medoids, species, cost = neighbour, s, c
+				failures = 0
+			} else {
+				failures++
+			}
+		}
+		if cost < minCost {
+			best, minCost = species, cost
+		}
+	}
+	return best, nil
+}
+
+// Validate SpecCLARANS fields.
+func (spec SpecCLARANS) Validate() error {
+	if spec.K < 2 {
+		return errors.New("k should be higher than 1")
+	}
+	if spec.Metric == nil {
+		return errors.New("metric field has to be provided")
+	}
+	if spec.NumLocal < 1 {
+		return errors.New("numLocal should be higher than 0")
+	}
+	if spec.MaxNeighbours < 1 {
+		return errors.New("maxNeighbours should be higher than 0")
+	}
+	return nil
+}
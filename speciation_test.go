@@ -3,6 +3,9 @@ package eaopt
 import (
 	"errors"
 	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -142,3 +145,224 @@ func TestSpecFitnessIntervalValidate(t *testing.T) {
 		t.Error("Validation should have raised error")
 	}
 }
+
+// speciesSizes returns the sizes of a partition, sorted in increasing order.
+func speciesSizes(species []Individuals) []int {
+	var sizes = make([]int, len(species))
+	for i, s := range species {
+		sizes[i] = len(s)
+	}
+	sort.Ints(sizes)
+	return sizes
+}
+
+func TestSpecThresholdApply(t *testing.T) {
+	var (
+		indis = newLineIndividuals(0, 0.5, 3, 3.2, 10, 1.2)
+		spec  = &SpecThreshold{Metric: l1Distance, Threshold: 1}
+	)
+	var species, err = spec.Apply(indis, newRand())
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	// 1.2 is within the threshold of 0.5 but not of 0, which is the
+	// representative, hence it founds a new species
+	if sizes := speciesSizes(species); !reflect.DeepEqual(sizes, []int{1, 1, 2, 2}) {
+		t.Errorf("Unexpected species sizes %v", sizes)
+	}
+	if species[0][0].ID != indis[0].ID || species[0][1].ID != indis[1].ID {
+		t.Errorf("The first individual should be the representative of the first species")
+	}
+}
+
+func TestSpecThresholdAdjustment(t *testing.T) {
+	var (
+		indis = newLineIndividuals(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+		spec  = &SpecThreshold{Metric: l1Distance, Threshold: 0.5, NSpecies: 3, Step: 0.5}
+		n     int
+	)
+	for i := 0; i < 20; i++ {
+		var species, err = spec.Apply(indis, newRand())
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		n = len(species)
+	}
+	if n < 2 || n > 4 || spec.CurrentThreshold() <= 0.5 {
+		t.Errorf("Expected around 3 species with a larger threshold, got %d with %f", n, spec.CurrentThreshold())
+	}
+	// The threshold doesn't go under Step
+	spec = &SpecThreshold{Metric: l1Distance, Threshold: 1, NSpecies: 20, Step: 0.25}
+	for i := 0; i < 10; i++ {
+		spec.Apply(indis, newRand())
+	}
+	if spec.CurrentThreshold() != 0.25 {
+		t.Errorf("Expected 0.25, got %f", spec.CurrentThreshold())
+	}
+}
+
+func TestSpecDBSCANApply(t *testing.T) {
+	var testCases = []struct {
+		indis  Individuals
+		spec   SpecDBSCAN
+		sizes  []int
+		noiseX float64 // Position of an individual and of a member of its species
+		withX  float64
+	}{
+		// Two dense groups, 20 is noise and joins the closest core individual
+		{
+			indis:  newLineIndividuals(0, 0.5, 1, 1.5, 10, 10.5, 11, 20),
+			spec:   SpecDBSCAN{Metric: l1Distance, Eps: 0.6, MinPts: 2},
+			sizes:  []int{4, 4},
+			noiseX: 20,
+			withX:  11,
+		},
+		// Density connected individuals form a chain
+		{
+			indis: newLineIndividuals(0, 1, 2, 3, 4),
+			spec:  SpecDBSCAN{Metric: l1Distance, Eps: 1, MinPts: 3},
+			sizes: []int{5},
+		},
+		// No core individuals
+		{
+			indis: newLineIndividuals(0, 5, 10),
+			spec:  SpecDBSCAN{Metric: l1Distance, Eps: 1, MinPts: 2},
+			sizes: []int{3},
+		},
+	}
+	for i, tc := range testCases {
+		var species, err = tc.spec.Apply(tc.indis, newRand())
+		if err != nil {
+			t.Fatalf("Test %d: expected nil, got %v", i, err)
+		}
+		if sizes := speciesSizes(species); !reflect.DeepEqual(sizes, tc.sizes) {
+			t.Errorf("Test %d: expected sizes %v, got %v", i, tc.sizes, sizes)
+		}
+		if tc.noiseX == 0 {
+			continue
+		}
+		for _, s := range species {
+			var xs = make(map[float64]bool)
+			for _, indi := range s {
+				xs[indi.Genome.(Vector)[0]] = true
+			}
+			if xs[tc.noiseX] && !xs[tc.withX] {
+				t.Errorf("Test %d: %f should be with %f", i, tc.noiseX, tc.withX)
+			}
+		}
+	}
+}
+
+func TestSpecHierarchicalApply(t *testing.T) {
+	var (
+		indis     = newLineIndividuals(0, 1, 3, 10, 11, 30)
+		testCases = []struct {
+			spec  SpecHierarchical
+			sizes []int
+		}{
+			{SpecHierarchical{Metric: l1Distance, Linkage: LinkageSingle, K: 3}, []int{1, 2, 3}},
+			{SpecHierarchical{Metric: l1Distance, Linkage: LinkageComplete, K: 2}, []int{1, 5}},
+			{SpecHierarchical{Metric: l1Distance, Linkage: LinkageAverage, K: 1}, []int{6}},
+			{SpecHierarchical{Metric: l1Distance, Linkage: LinkageSingle, MaxDistance: 1.5}, []int{1, 1, 2, 2}},
+			{SpecHierarchical{Metric: l1Distance, Linkage: LinkageComplete, MaxDistance: 2.5}, []int{1, 1, 2, 2}},
+			// Less individuals than species
+			{SpecHierarchical{Metric: l1Distance, K: 10}, []int{1, 1, 1, 1, 1, 1}},
+		}
+	)
+	for i, tc := range testCases {
+		var species, err = tc.spec.Apply(indis, newRand())
+		if err != nil {
+			t.Fatalf("Test %d: expected nil, got %v", i, err)
+		}
+		if sizes := speciesSizes(species); !reflect.DeepEqual(sizes, tc.sizes) {
+			t.Errorf("Test %d: expected sizes %v, got %v", i, tc.sizes, sizes)
+		}
+	}
+}
+
+func TestSpeciatorsValidate(t *testing.T) {
+	var valid = []Speciator{
+		&SpecThreshold{Metric: l1Distance, Threshold: 1},
+		&SpecThreshold{Metric: l1Distance, Threshold: 1, NSpecies: 3, Step: 0.1},
+		SpecDBSCAN{Metric: l1Distance, Eps: 1, MinPts: 1},
+		SpecHierarchical{Metric: l1Distance, K: 2},
+		SpecHierarchical{Metric: l1Distance, Linkage: LinkageAverage, MaxDistance: 1},
+	}
+	for i, spec := range valid {
+		if err := spec.Validate(); err != nil {
+			t.Errorf("Test %d: expected nil, got %v", i, err)
+		}
+	}
+	var invalid = []Speciator{
+		&SpecThreshold{Threshold: 1},
+		&SpecThreshold{Metric: l1Distance},
+		&SpecThreshold{Metric: l1Distance, Threshold: 1, NSpecies: 3},
+		SpecDBSCAN{Eps: 1, MinPts: 1},
+		SpecDBSCAN{Metric: l1Distance, MinPts: 1},
+		SpecDBSCAN{Metric: l1Distance, Eps: 1},
+		SpecHierarchical{K: 2},
+		SpecHierarchical{Metric: l1Distance, Linkage: Linkage(3), K: 2},
+		SpecHierarchical{Metric: l1Distance, MaxDistance: -1, K: 2},
+		SpecHierarchical{Metric: l1Distance},
+	}
+	for i, spec := range invalid {
+		if err := spec.Validate(); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+}
+
+func TestSpecSampledKMedoidsApply(t *testing.T) {
+	var (
+		indis      = newLineIndividuals(0, 0.1, 0.2, 0.3, 10, 10.1, 10.2, 20, 20.1, 20.2, 20.3, 20.4)
+		speciators = []Speciator{
+			SpecCLARA{K: 3, Metric: l1Distance, NSamples: 5, SampleSize: 8, MaxIterations: 10},
+			SpecCLARA{K: 3, Metric: l1Distance, NSamples: 5, SampleSize: 8, MaxIterations: 10, Parallel: true},
+			SpecCLARANS{K: 3, Metric: l1Distance, NumLocal: 3, MaxNeighbours: 30},
+		}
+	)
+	for _, spec := range speciators {
+		// The samples are random, hence the RNG is seeded so that a good
+		// clustering is found
+		var species, err = spec.Apply(indis, rand.New(rand.NewSource(42)))
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		if sizes := speciesSizes(species); !reflect.DeepEqual(sizes, []int{3, 4, 5}) {
+			t.Errorf("%T: expected sizes [3 4 5], got %v", spec, sizes)
+		}
+		// Less individuals than species
+		species, err = spec.Apply(indis[:3], newRand())
+		if err != nil || len(species) != 3 {
+			t.Errorf("%T: expected 3 species, got %v", spec, species)
+		}
+	}
+}
+
+func TestSpecSampledKMedoidsValidate(t *testing.T) {
+	var valid = []Speciator{
+		SpecCLARA{K: 2, Metric: l1Distance, NSamples: 1, SampleSize: 2, MaxIterations: 1},
+		SpecCLARANS{K: 2, Metric: l1Distance, NumLocal: 1, MaxNeighbours: 1},
+	}
+	for i, spec := range valid {
+		if err := spec.Validate(); err != nil {
+			t.Errorf("Test %d: expected nil, got %v", i, err)
+		}
+	}
+	var invalid = []Speciator{
+		SpecCLARA{K: 1, Metric: l1Distance, NSamples: 1, SampleSize: 2, MaxIterations: 1},
+		SpecCLARA{K: 2, NSamples: 1, SampleSize: 2, MaxIterations: 1},
+		SpecCLARA{K: 2, Metric: l1Distance, SampleSize: 2, MaxIterations: 1},
+		SpecCLARA{K: 2, Metric: l1Distance, NSamples: 1, SampleSize: 1, MaxIterations: 1},
+		SpecCLARA{K: 2, Metric: l1Distance, NSamples: 1, SampleSize: 2},
+		SpecCLARANS{K: 1, Metric: l1Distance, NumLocal: 1, MaxNeighbours: 1},
+		SpecCLARANS{K: 2, NumLocal: 1, MaxNeighbours: 1},
+		SpecCLARANS{K: 2, Metric: l1Distance, MaxNeighbours: 1},
+		SpecCLARANS{K: 2, Metric: l1Distance, NumLocal: 1},
+	}
+	for i, spec := range invalid {
+		if err := spec.Validate(); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package eaopt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// A Species is a group of similar individuals that persists across
+// generations.
+type Species struct {
+	ID             int
+	Representative Individual
+	Members        Individuals
+	BestFitness    float64
+	History        []float64 // Best fitness of the members at each generation
+	Age            uint      // Number of generations the species has existed for
+	Stagnation     uint      // Number of generations since BestFitness improved
+}
+
+// record updates a Species with the members it has at the current generation.
+func (s *Species) record(members Individuals) {
+	var best = members.FitMin()
+	s.Members = members
+	s.History = append(s.History, best)
+	s.Age++
+	s.Stagnation++
+	if best < s.BestFitness {
+		s.BestFitness = best
+		s.Stagnation = 0
+	}
+}
+
+// adjustedShares implements explicit fitness sharing: because fitnesses are
+// minimized, the score of an individual is the difference between the worst
+// finite fitness of all the species and its own fitness, divided by the size
+// of its species. The share of a species is the sum of the scores of its
+// members.
+func adjustedShares(species []Individuals) []float64 {
+	var (
+		worst  = math.Inf(-1)
+		shares = make([]float64, len(species))
+	)
+	for _, s := range species {
+		for _, indi := range s {
+			if !math.IsInf(indi.Fitness, 0) && indi.Fitness > worst {
+				worst = indi.Fitness
+			}
+		}
+	}
+	for i, s := range species {
+		for _, indi := range s {
+			if !math.IsInf(indi.Fitness, 0) {
+				shares[i] += (worst - indi.Fitness) / float64(len(s))
+			}
+		}
+	}
+	return shares
+}
+
+// resizeSpecies returns n individuals from a species. If the species is too
+// large its worst members are discarded, if it is too small clones of random
+// members are added.
+func resizeSpecies(members Individuals, n int, rng *rand.Rand) Individuals {
+	var resized = make(Individuals, len(members), maxInt(n, len(members)))
+	copy(resized, members)
+	if n <= len(resized) {
+		resized.SortByFitness()
+		return resized[:n]
+	}
+	for len(resized) < n {
+		resized = append(resized, members[rng.Intn(len(members))].Clone(rng))
+	}
+	return resized
+}
+
+// A speciesTracker is a Speciator that keeps track of the species of a
+// Population across generations. Along with the species, track returns the
+// number of individuals each species should contain once the Model has been
+// applied, a size of 0 meaning that the species goes extinct.
+type speciesTracker interface {
+	track(pop *Population) ([]Species, []int, error)
+}
+
+// SpecPersistent turns a Speciator into one whose species persist across
+// generations. At each generation the clusters produced by Speciator are
+// matched with the species of the previous generation: the closest pairs of
+// cluster medoid and species representative, according to Metric, are matched
+// first. Clusters that can't be matched, or whose medoid is farther than
+// MaxDistance from the representatives that are left, found new species.
+// MaxDistance = 0 means no limit. The medoid of each cluster becomes the
+// representative of its species.
+//
+// When SpecPersistent is used as the Speciator of a GA, the species that
+// haven't improved for MaxStagnation generations go extinct, except the one
+// that contains the best individual. MaxStagnation = 0 means species never
+// stagnate. If Allocate is true then the size of each species in the next
+// generation is proportional to its adjusted fitness, see ModNEAT, otherwise
+// it is proportional to its current size. Species that would get less than
+// MinSize individuals go extinct so that the Model always has enough
+// individuals to work with. Callback, if provided, is called with the ID of
+// the Population and its species after each speciation, it may be called
+// concurrently for different Populations.
+//
+// SpecPersistent is stateful, it should hence be used through a pointer. The
+// species are tracked separately for each Population.
+type SpecPersistent struct {
+	Speciator     Speciator
+	Metric        Metric
+	MaxDistance   float64
+	MaxStagnation uint
+	Allocate      bool
+	MinSize       uint
+	Callback      func(popID string, species []Species)
+
+	mu      sync.Mutex
+	species map[string][]Species
+	nextID  int
+}
+
+// Species returns a copy of the current species of a Population.
+func (spec *SpecPersistent) Species(popID string) []Species {
+	spec.mu.Lock()
+	defer spec.mu.Unlock()
+	return append([]Species(nil), spec.species[popID]...)
+}
+
+// Apply SpecPersistent. The species are tracked under an empty Population ID,
+// no species goes extinct.
+func (spec *SpecPersistent) Apply(indis Individuals, rng *rand.Rand) ([]Individuals, error) {
+	var species, err = spec.match("", indis, rng)
+	if err != nil {
+		return nil, err
+	}
+	spec.mu.Lock()
+	spec.species[""] = species
+	spec.mu.Unlock()
+	var partition = make([]Individuals, len(species))
+	for i, s := range species {
+		partition[i] = s.Members
+	}
+	return partition, nil
+}
+
+// track method from speciesTracker.
+func (spec *SpecPersistent) track(pop *Population) ([]Species, []int, error) {
+	var species, err = spec.match(pop.ID, pop.Individuals, pop.RNG)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		sizes     = spec.allocate(species, len(pop.Individuals))
+		survivors []Species
+	)
+	for i, s := range species {
+		if sizes[i] > 0 {
+			survivors = append(survivors, s)
+		}
+	}
+	spec.mu.Lock()
+	spec.species[pop.ID] = survivors
+	spec.mu.Unlock()
+	if spec.Callback != nil {
+		spec.Callback(pop.ID, append([]Species(nil), species...))
+	}
+	return species, sizes, nil
+}
+
+// A speciesPair is a candidate match between a cluster and a species.
+type speciesPair struct {
+	dist             float64
+	cluster, species int
+}
+
+// match partitions a slice of individuals and matches the clusters with the
+// species stored under the given Population ID.
+func (spec *SpecPersistent) match(popID string, indis Individuals, rng *rand.Rand) ([]Species, error) {
+	var clusters, err = spec.Speciator.Apply(indis, rng)
+	if err != nil {
+		return nil, err
+	}
+	spec.mu.Lock()
+	defer spec.mu.Unlock()
+	if spec.species == nil {
+		spec.species = make(map[string][]Species)
+	}
+	var (
+		previous = spec.species[popID]
+		dm       = newDistanceMemoizer(spec.Metric)
+		medoids  = make(Individuals, len(clusters))
+		pairs    []speciesPair
+	)
+	for i, cluster := range clusters {
+		if len(cluster) == 0 {
+			return nil, fmt.Errorf("cluster %d has 0 individuals", i)
+		}
+		cluster.SortByDistanceToMedoid(dm)
+		medoids[i] = cluster[0]
+		for j, s := range previous {
+			var d = dm.GetDistance(medoids[i], s.Representative)
+			if spec.MaxDistance == 0 || d <= spec.MaxDistance {
+				pairs = append(pairs, speciesPair{d, i, j})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool { return pairs[a].dist < pairs[b].dist })
+	var (
+		species = make([]Species, len(clusters))
+		matched = make([]bool, len(clusters))
+		taken   = make([]bool, len(previous))
+	)
+	for _, p := range pairs {
+		var i, j = p.cluster, p.species
+		if matched[i] || taken[j] {
+			continue
+		}
+		species[i] = previous[j]
+		species[i].History = append([]float64(nil), previous[j].History...)
+		matched[i], taken[j] = true, true
+	}
+	for i, cluster := range clusters {
+		if !matched[i] {
+			species[i] = Species{ID: spec.nextID, BestFitness: math.Inf(1)}
+			spec.nextID++
+		}
+		species[i].Representative = medoids[i]
+		species[i].record(cluster)
+	}
+	return species, nil
+}
+
+// allocate decides how many individuals each species contains in the next
+// generation.
+func (spec *SpecPersistent) allocate(species []Species, n int) []int {
+	var (
+		members = make([]Individuals, len(species))
+		alive   = make([]bool, len(species))
+		best    int
+		shares  []float64
+	)
+	for i, s := range species {
+		members[i] = s.Members
+		alive[i] = spec.MaxStagnation == 0 || s.Stagnation < spec.MaxStagnation
+		if s.Members.FitMin() < species[best].Members.FitMin() {
+			best = i
+		}
+	}
+	alive[best] = true
+	if spec.Allocate {
+		shares = adjustedShares(members)
+	} else {
+		shares = make([]float64, len(species))
+		for i, s := range species {
+			shares[i] = float64(len(s.Members))
+		}
+	}
+	for {
+		var (
+			idxs      []int
+			subShares []float64
+		)
+		for i := range species {
+			if alive[i] {
+				idxs = append(idxs, i)
+				subShares = append(subShares, shares[i])
+			}
+		}
+		var (
+			sizes  = make([]int, len(species))
+			weak   = -1
+			counts = allocateOffsprings(n, subShares)
+		)
+		for k, i := range idxs {
+			sizes[i] = counts[k]
+			if i != best && counts[k] < int(spec.MinSize) && (weak == -1 || shares[i] < shares[weak]) {
+				weak = i
+			}
+		}
+		if weak == -1 {
+			return sizes
+		}
+		alive[weak] = false
+	}
+}
+
+// Validate SpecPersistent fields.
+func (spec *SpecPersistent) Validate() error {
+	if spec.Speciator == nil {
+		return errors.New("Speciator cannot be nil")
+	}
+	if err := spec.Speciator.Validate(); err != nil {
+		return err
+	}
+	if spec.Metric == nil {
+		return errNilMetric
+	}
+	if spec.MaxDistance < 0 {
+		return errors.New("MaxDistance should be positive")
+	}
+	return nil
+}
+
+// evolveSpecies applies a Model to each species of a Population separately,
+// each species being resized to the size decided by the speciesTracker. The
+// subpopulations are given IDs derived from the IDs of their species so that
+// they can be identified across generations.
+func (pop *Population) evolveSpecies(tracker speciesTracker, model Model) error {
+	var species, sizes, err = tracker.track(pop)
+	if err != nil {
+		return err
+	}
+	var offsprings = make(Individuals, 0, len(pop.Individuals))
+	for i, s := range species {
+		if sizes[i] == 0 {
+			continue
+		}
+		var subpop = Population{
+			Individuals: resizeSpecies(s.Members, sizes[i], pop.RNG),
+			Age:         pop.Age,
+			Generations: pop.Generations,
+			ID:          fmt.Sprintf("%s-%d", pop.ID, s.ID),
+			RNG:         pop.RNG,
+			Run:         pop.Run,
+		}
+		if err = model.Apply(&subpop); err != nil {
+			return err
+		}
+		offsprings = append(offsprings, subpop.Individuals...)
+	}
+	copy(pop.Individuals, offsprings)
+	return nil
+}
@@ -0,0 +1,199 @@
+package eaopt
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpeciesRecord(t *testing.T) {
+	var s = Species{BestFitness: math.Inf(1)}
+	for _, xs := range [][]float64{{3, 2}, {1, 4}, {5}} {
+		var members = newLineIndividuals(xs...)
+		members.Evaluate(false)
+		s.record(members)
+	}
+	if s.Age != 3 || s.Stagnation != 1 || s.BestFitness != 1 {
+		t.Errorf("Unexpected species %+v", s)
+	}
+	if len(s.History) != 3 || s.History[0] != 2 || s.History[2] != 5 {
+		t.Errorf("Unexpected history %v", s.History)
+	}
+}
+
+func TestResizeSpecies(t *testing.T) {
+	var members = newLineIndividuals(3, 1, 2)
+	members.Evaluate(false)
+	if resized := resizeSpecies(members, 2, newRand()); len(resized) != 2 || resized[0].Fitness != 1 || resized[1].Fitness != 2 {
+		t.Errorf("Expected the 2 best members, got %v", resized)
+	}
+	if members[0].Fitness != 3 {
+		t.Error("The members should be left untouched")
+	}
+	if resized := resizeSpecies(members, 5, newRand()); len(resized) != 5 || resized[3].ID == resized[4].ID {
+		t.Errorf("Expected 5 individuals, got %v", resized)
+	}
+}
+
+func TestSpecPersistentIDs(t *testing.T) {
+	var (
+		spec = &SpecPersistent{
+			Speciator:   SpecKMedoids{K: 2, MinPerCluster: 1, Metric: l1Distance, MaxIterations: 10},
+			Metric:      l1Distance,
+			MaxDistance: 1,
+		}
+		groups = []Individuals{
+			newLineIndividuals(0, 0.1, 0.2, 10, 10.1),
+			newLineIndividuals(10.2, 10.3, 0.3, 0.1, 0.2),
+			newLineIndividuals(0, 0.1, 50, 50.1, 50.2),
+		}
+		near = make([]int, len(groups)) // ID of the species around 0
+		far  = make([]int, len(groups)) // ID of the other species
+	)
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	for i, indis := range groups {
+		indis.Evaluate(false)
+		var species, err = spec.Apply(indis, newRand())
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		if len(species) != 2 || len(species[0])+len(species[1]) != len(indis) {
+			t.Fatalf("Expected a partition in 2 species, got %v", species)
+		}
+		for _, s := range spec.Species("") {
+			if s.Representative.Genome.(Vector)[0] < 5 {
+				near[i] = s.ID
+			} else {
+				far[i] = s.ID
+			}
+		}
+	}
+	// The species are carried over from one group to the next, except the
+	// species around 10 which is replaced by a new one in the third group
+	if near[0] != near[1] || near[1] != near[2] || far[0] != far[1] || far[2] != 2 {
+		t.Errorf("Unexpected species IDs %v and %v", near, far)
+	}
+	for _, s := range spec.Species("") {
+		if s.ID == near[0] && (s.Age != 3 || len(s.History) != 3) || s.ID == 2 && s.Age != 1 {
+			t.Errorf("Unexpected species %+v", s)
+		}
+	}
+}
+
+func newSpecies(id int, stagnation uint, xs ...float64) Species {
+	var s = Species{ID: id, Members: newLineIndividuals(xs...), Stagnation: stagnation}
+	s.Members.Evaluate(false)
+	return s
+}
+
+func TestSpecPersistentAllocate(t *testing.T) {
+	var (
+		species = []Species{
+			newSpecies(0, 0, 0, 0),
+			newSpecies(1, 0, 2, 2, 2, 2),
+			newSpecies(2, 10, 1),
+			newSpecies(3, 0, 4, 4),
+		}
+		testCases = []struct {
+			spec  *SpecPersistent
+			sizes []int
+		}{
+			// Proportional to the current sizes
+			{&SpecPersistent{}, []int{2, 4, 1, 2}},
+			// The stagnant species goes extinct
+			{&SpecPersistent{MaxStagnation: 10}, []int{2, 5, 0, 2}},
+			// Proportional to the adjusted fitnesses 4, 2, 3 and 0
+			{&SpecPersistent{Allocate: true}, []int{4, 2, 3, 0}},
+			// The species that are too small go extinct
+			{&SpecPersistent{Allocate: true, MinSize: 4}, []int{5, 0, 4, 0}},
+			{&SpecPersistent{MaxStagnation: 1, MinSize: 10}, []int{9, 0, 0, 0}},
+		}
+	)
+	for i, tc := range testCases {
+		var sizes = tc.spec.allocate(species, 9)
+		for j := range sizes {
+			if sizes[j] != tc.sizes[j] {
+				t.Errorf("Test %d: expected %v, got %v", i, tc.sizes, sizes)
+				break
+			}
+		}
+	}
+}
+
+func TestGASpecPersistent(t *testing.T) {
+	var (
+		nCalls int
+		maxID  int
+		spec   = &SpecPersistent{
+			Speciator:     SpecKMedoids{K: 3, MinPerCluster: 1, Metric: l1Distance, MaxIterations: 10},
+			Metric:        l1Distance,
+			MaxStagnation: 3,
+			Allocate:      true,
+			MinSize:       3,
+			Callback: func(popID string, species []Species) {
+				nCalls++
+				var n int
+				for _, s := range species {
+					n += len(s.Members)
+					if s.ID > maxID {
+						maxID = s.ID
+					}
+				}
+				if n != 30 {
+					t.Errorf("Expected 30 members, got %d", n)
+				}
+			},
+		}
+		ga, err = GAConfig{
+			NPops:        1,
+			PopSize:      30,
+			NGenerations: 10,
+			HofSize:      1,
+			Model:        ModGenerational{Selector: SelTournament{NContestants: 2}, MutRate: 0.5},
+			Speciator:    spec,
+			RNG:          newRand(),
+		}.NewGA()
+	)
+	if err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if err = ga.Minimize(NewVector); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if nCalls != 10 || maxID < 2 {
+		t.Errorf("Unexpected number of calls %d and maximum ID %d", nCalls, maxID)
+	}
+	if n := len(ga.Populations[0].Individuals); n != 30 {
+		t.Errorf("Expected 30 individuals, got %d", n)
+	}
+	if species := spec.Species(ga.Populations[0].ID); len(species) == 0 {
+		t.Error("Expected the species to be tracked")
+	}
+}
+
+func TestSpecPersistentErrors(t *testing.T) {
+	var invalid = []*SpecPersistent{
+		{Metric: l1Distance},
+		{Speciator: SpecValidateError{}, Metric: l1Distance},
+		{Speciator: SpecFitnessInterval{K: 2}},
+		{Speciator: SpecFitnessInterval{K: 2}, Metric: l1Distance, MaxDistance: -1},
+	}
+	for i, spec := range invalid {
+		if err := spec.Validate(); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+	var spec = &SpecPersistent{Speciator: SpecRuntimeError{}, Metric: l1Distance}
+	if _, err := spec.Apply(newLineIndividuals(1, 2), newRand()); err == nil {
+		t.Error("Expected error")
+	}
+	var pop = Population{Individuals: newLineIndividuals(1, 2), RNG: newRand()}
+	if err := pop.speciateEvolveMerge(spec, ModIdentity{}); err == nil {
+		t.Error("Expected error")
+	}
+	spec.Speciator = SpecFitnessInterval{K: 1}
+	if err := pop.speciateEvolveMerge(spec, ModRuntimeError{}); err == nil {
+		t.Error("Expected error")
+	}
+}
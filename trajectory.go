@@ -0,0 +1,339 @@
+package eaopt
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// trajectories stores the state of the single-solution searches performed by
+// a trajectory model. Each Individual is an independent trajectory whose state
+// is indexed by the ID of the Individual, which means the state follows the
+// Individual through migration and speciation. Populations are evolved
+// concurrently, hence the mutex.
+type trajectories[S any] struct {
+	mu     sync.Mutex
+	states map[string]trajectory[S]
+}
+
+// A trajectory is the state of a search along with the generation at which it
+// was last updated.
+type trajectory[S any] struct {
+	state      S
+	generation uint
+}
+
+// get returns the state of the trajectory an Individual is on. The state of a
+// new trajectory is initialized with init.
+func (t *trajectories[S]) get(indi Individual, init func() S) S {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if traj, ok := t.states[indi.ID]; ok {
+		return traj.state
+	}
+	return init()
+}
+
+// set stores the state of a trajectory of a Population which has moved from
+// prev to next.
+func (t *trajectories[S]) set(pop *Population, prev, next Individual, state S) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.states == nil {
+		t.states = make(map[string]trajectory[S])
+	}
+	delete(t.states, prev.ID)
+	t.states[next.ID] = trajectory[S]{state, pop.Generations}
+}
+
+// prune deletes the trajectories that haven't been updated during the previous
+// generation of a Population. The Individuals they belong to have left every
+// Population the model is applied to, for instance because they have been
+// replaced by migrants or discarded by another Model.
+func (t *trajectories[S]) prune(pop *Population) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, traj := range t.states {
+		if traj.generation+1 < pop.Generations {
+			delete(t.states, id)
+		}
+	}
+}
+
+// A TabuMove modifies a Genome in place and returns an attribute that
+// describes the move, for example the indexes of two swapped genes. The
+// attribute is stored in the tabu list, so it should describe what must not
+// be undone. Attributes have to be comparable.
+type TabuMove func(genome Genome, rng *rand.Rand) interface{}
+
+// tabuState is the state of a tabu search trajectory.
+type tabuState struct {
+	list []interface{}
+	best float64
+}
+
+// isTabu checks if an attribute is contained in the tabu list.
+func (ts tabuState) isTabu(attr interface{}) bool {
+	for _, a := range ts.list {
+		if a == attr {
+			return true
+		}
+	}
+	return false
+}
+
+// push adds an attribute to the tabu list and forgets the oldest attributes.
+func (ts *tabuState) push(attr interface{}, tenure uint) {
+	ts.list = append(ts.list, attr)
+	if len(ts.list) > int(tenure) {
+		ts.list = ts.list[len(ts.list)-int(tenure):]
+	}
+}
+
+// ModTabuSearch implements tabu search. At each generation NNeighbours
+// neighbours of each Individual are sampled and the Individual moves to the
+// best one that isn't tabu, even if it is worse. A tabu neighbour is still
+// accepted if it is better than the best Individual the trajectory has ever
+// visited (the aspiration criterion).
+//
+// The tabu list contains the Tenure last move attributes returned by Move. If
+// Move is nil then neighbours are obtained by calling the Genome's Mutate
+// method, in which case Hash has to be provided and the tabu list contains
+// the hashes of the last Tenure visited Genomes. Hashes have to be
+// comparable.
+//
+// ModTabuSearch keeps track of the tabu lists between generations, it thus
+// has to be used through a pointer and shouldn't be shared between GAs.
+type ModTabuSearch struct {
+	NNeighbours uint
+	Tenure      uint
+	Move        TabuMove
+	Hash        func(genome Genome) interface{}
+
+	trajs trajectories[tabuState]
+}
+
+// Apply ModTabuSearch.
+func (mod *ModTabuSearch) Apply(pop *Population) error {
+	mod.trajs.prune(pop)
+	for i, indi := range pop.Individuals {
+		var (
+			state    = mod.trajs.get(indi, func() tabuState { return tabuState{best: math.Inf(1)} })
+			next     Individual
+			nextAttr interface{}
+			found    bool
+		)
+		if indi.Fitness < state.best {
+			state.best = indi.Fitness
+		}
+		for j := uint(0); j < mod.NNeighbours; j++ {
+			var (
+				neighbour = indi.Clone(pop.RNG)
+				attr      interface{}
+			)
+			if mod.Move != nil {
+				attr = mod.Move(neighbour.Genome, pop.RNG)
+				neighbour.Evaluated = false
+			} else {
+				neighbour.Mutate(pop.RNG)
+				attr = mod.Hash(neighbour.Genome)
+			}
+			if err := pop.evaluate(&neighbour); err != nil {
+				return err
+			}
+			if found && neighbour.Fitness >= next.Fitness {
+				continue
+			}
+			if state.isTabu(attr) && neighbour.Fitness >= state.best {
+				continue
+			}
+			next, nextAttr, found = neighbour, attr, true
+		}
+		// Every neighbour is tabu, the Individual stays where it is
+		if !found {
+			mod.trajs.set(pop, indi, indi, state)
+			continue
+		}
+		pop.Individuals[i] = next
+		state.push(nextAttr, mod.Tenure)
+		if next.Fitness < state.best {
+			state.best = next.Fitness
+		}
+		mod.trajs.set(pop, indi, next, state)
+	}
+	return nil
+}
+
+// Validate ModTabuSearch fields.
+func (mod *ModTabuSearch) Validate() error {
+	if mod.NNeighbours == 0 {
+		return errors.New("NNeighbours should be higher than 0")
+	}
+	if mod.Tenure == 0 {
+		return errors.New("Tenure should be higher than 0")
+	}
+	if mod.Move == nil && mod.Hash == nil {
+		return errors.New("Hash has to be provided if Move is nil")
+	}
+	return nil
+}
+
+// ModILS implements iterated local search. At each generation each Individual
+// is perturbed and then improved with Search, which may evaluate at most
+// Budget Genomes. The result replaces the Individual if it is at least as
+// good. The strength of the perturbation starts at MinStrength; it is
+// increased by one after each failed iteration, up to MaxStrength, and reset
+// to MinStrength after each improvement. If Perturb is nil the perturbation
+// consists in calling the Genome's Mutate method strength times.
+//
+// ModILS keeps track of the perturbation strengths between generations, it
+// thus has to be used through a pointer and shouldn't be shared between GAs.
+type ModILS struct {
+	Search      LocalSearch
+	Budget      uint
+	Perturb     func(genome Genome, strength uint, rng *rand.Rand)
+	MinStrength uint
+	MaxStrength uint
+
+	trajs trajectories[uint]
+}
+
+// perturb applies a perturbation of a given strength to a Genome.
+func (mod *ModILS) perturb(genome Genome, strength uint, rng *rand.Rand) {
+	if mod.Perturb != nil {
+		mod.Perturb(genome, strength, rng)
+		return
+	}
+	for i := uint(0); i < strength; i++ {
+		genome.Mutate(rng)
+	}
+}
+
+// Apply ModILS.
+func (mod *ModILS) Apply(pop *Population) error {
+	mod.trajs.prune(pop)
+	for i, indi := range pop.Individuals {
+		var (
+			strength  = mod.trajs.get(indi, func() uint { return mod.MinStrength })
+			candidate = indi.Clone(pop.RNG)
+		)
+		mod.perturb(candidate.Genome, strength, pop.RNG)
+		candidate.Evaluated = false
+		if err := pop.evaluate(&candidate); err != nil {
+			return err
+		}
+		var genome, fitness, used, err = mod.Search.Apply(candidate.Genome, candidate.Fitness, mod.Budget, pop.RNG)
+		pop.Run.CountEvaluations(int(used))
+		if err != nil {
+			return err
+		}
+		if fitness < candidate.Fitness {
+			candidate.Genome, candidate.Fitness = genome, fitness
+		}
+		if candidate.Fitness < indi.Fitness {
+			strength = mod.MinStrength
+		} else if strength < mod.MaxStrength {
+			strength++
+		}
+		if candidate.Fitness <= indi.Fitness {
+			pop.Individuals[i] = candidate
+		}
+		mod.trajs.set(pop, indi, pop.Individuals[i], strength)
+	}
+	return nil
+}
+
+// Validate ModILS fields.
+func (mod *ModILS) Validate() error {
+	if mod.Search == nil {
+		return errors.New("Search cannot be nil")
+	}
+	if err := mod.Search.Validate(); err != nil {
+		return err
+	}
+	if mod.Budget == 0 {
+		return errors.New("Budget should be higher than 0")
+	}
+	if mod.MinStrength == 0 {
+		return errors.New("MinStrength should be higher than 0")
+	}
+	if mod.MaxStrength < mod.MinStrength {
+		return errors.New("MaxStrength should be higher or equal to MinStrength")
+	}
+	return nil
+}
+
+// ModVNS implements variable neighbourhood search. Neighbourhoods is a list of
+// mutation operators ordered from the smallest to the largest neighbourhood.
+// At each generation each Individual is shaken with the current
+// neighbourhood and then improved with Search, which may evaluate at most
+// Budget Genomes. If the result is better, it replaces the Individual and the
+// search goes back to the first neighbourhood; if not the next neighbourhood
+// is used, wrapping around after the last one. Search can be nil, in which
+// case the model implements reduced VNS.
+//
+// ModVNS keeps track of the current neighbourhoods between generations, it
+// thus has to be used through a pointer and shouldn't be shared between GAs.
+type ModVNS struct {
+	Neighbourhoods []func(genome Genome, rng *rand.Rand)
+	Search         LocalSearch
+	Budget         uint
+
+	trajs trajectories[int]
+}
+
+// Apply ModVNS.
+func (mod *ModVNS) Apply(pop *Population) error {
+	mod.trajs.prune(pop)
+	for i, indi := range pop.Individuals {
+		var (
+			k         = mod.trajs.get(indi, func() int { return 0 })
+			candidate = indi.Clone(pop.RNG)
+		)
+		mod.Neighbourhoods[k](candidate.Genome, pop.RNG)
+		candidate.Evaluated = false
+		if err := pop.evaluate(&candidate); err != nil {
+			return err
+		}
+		if mod.Search != nil {
+			var genome, fitness, used, err = mod.Search.Apply(candidate.Genome, candidate.Fitness, mod.Budget, pop.RNG)
+			pop.Run.CountEvaluations(int(used))
+			if err != nil {
+				return err
+			}
+			if fitness < candidate.Fitness {
+				candidate.Genome, candidate.Fitness = genome, fitness
+			}
+		}
+		if candidate.Fitness < indi.Fitness {
+			pop.Individuals[i] = candidate
+			k = 0
+		} else {
+			k = (k + 1) % len(mod.Neighbourhoods)
+		}
+		mod.trajs.set(pop, indi, pop.Individuals[i], k)
+	}
+	return nil
+}
+
+// Validate ModVNS fields.
+func (mod *ModVNS) Validate() error {
+	if len(mod.Neighbourhoods) == 0 {
+		return errors.New("Neighbourhoods should contain at least one operator")
+	}
+	for _, nb := range mod.Neighbourhoods {
+		if nb == nil {
+			return errors.New("Neighbourhoods cannot contain nil operators")
+		}
+	}
+	if mod.Search != nil {
+		if err := mod.Search.Validate(); err != nil {
+			return err
+		}
+		if mod.Budget == 0 {
+			return errors.New("Budget should be higher than 0")
+		}
+	}
+	return nil
+}
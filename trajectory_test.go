@@ -0,0 +1,261 @@
+package eaopt
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// swapMove swaps two cities of a lineTour and returns the sorted indexes of
+// the swapped cities.
+func swapMove(genome Genome, rng *rand.Rand) interface{} {
+	var (
+		tour = genome.(lineTour)
+		ij   = randomInts(2, 0, len(tour), rng)
+	)
+	tour[ij[0]], tour[ij[1]] = tour[ij[1]], tour[ij[0]]
+	return [2]int{minInt(ij[0], ij[1]), maxInt(ij[0], ij[1])}
+}
+
+func newLineTour(rng *rand.Rand) Genome {
+	var tour = make(lineTour, 8)
+	copy(tour, rng.Perm(8))
+	return tour
+}
+
+func TestTrajectoryModelsValidate(t *testing.T) {
+	var (
+		search = LSHillClimbing{Neighbour: func(genome Genome, rng *rand.Rand) {}}
+		nb     = func(genome Genome, rng *rand.Rand) {}
+		valid  = []Model{
+			&ModTabuSearch{NNeighbours: 5, Tenure: 3, Move: swapMove},
+			&ModTabuSearch{NNeighbours: 5, Tenure: 3, Hash: func(g Genome) interface{} { return 0 }},
+			&ModILS{Search: search, Budget: 10, MinStrength: 1, MaxStrength: 1},
+			&ModVNS{Neighbourhoods: []func(Genome, *rand.Rand){nb}},
+			&ModVNS{Neighbourhoods: []func(Genome, *rand.Rand){nb}, Search: search, Budget: 10},
+		}
+		invalid = []Model{
+			&ModTabuSearch{Tenure: 3, Move: swapMove},
+			&ModTabuSearch{NNeighbours: 5, Move: swapMove},
+			&ModTabuSearch{NNeighbours: 5, Tenure: 3},
+			&ModILS{Budget: 10, MinStrength: 1, MaxStrength: 1},
+			&ModILS{Search: LSHillClimbing{}, Budget: 10, MinStrength: 1, MaxStrength: 1},
+			&ModILS{Search: search, MinStrength: 1, MaxStrength: 1},
+			&ModILS{Search: search, Budget: 10, MaxStrength: 1},
+			&ModILS{Search: search, Budget: 10, MinStrength: 2, MaxStrength: 1},
+			&ModVNS{},
+			&ModVNS{Neighbourhoods: []func(Genome, *rand.Rand){nil}},
+			&ModVNS{Neighbourhoods: []func(Genome, *rand.Rand){nb}, Search: LSHillClimbing{}, Budget: 10},
+			&ModVNS{Neighbourhoods: []func(Genome, *rand.Rand){nb}, Search: search},
+		}
+	)
+	for i, mod := range valid {
+		if err := mod.Validate(); err != nil {
+			t.Errorf("Test %d: expected nil, got %v", i, err)
+		}
+	}
+	for i, mod := range invalid {
+		if err := mod.Validate(); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+}
+
+func TestModTabuSearchTenure(t *testing.T) {
+	var (
+		mod = &ModTabuSearch{NNeighbours: 4, Tenure: 3, Move: swapMove}
+		pop = Population{Individuals: newIndividuals(2, false, newLineTour, newRand()), RNG: newRand()}
+	)
+	pop.Individuals.Evaluate(false)
+	for i := 0; i < 10; i++ {
+		if err := mod.Apply(&pop); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		for _, indi := range pop.Individuals {
+			if !indi.Evaluated || !isPermutation(indi.Genome.(lineTour), []int{0, 1, 2, 3, 4, 5, 6, 7}) {
+				t.Errorf("Unexpected individual %v", indi)
+			}
+		}
+	}
+	if len(mod.trajs.states) != 2 {
+		t.Fatalf("Expected 2 trajectories, got %d", len(mod.trajs.states))
+	}
+	for _, indi := range pop.Individuals {
+		var state = mod.trajs.get(indi, func() tabuState { return tabuState{} })
+		if len(state.list) != 3 {
+			t.Errorf("Expected a tabu list of length 3, got %v", state.list)
+		}
+	}
+}
+
+func TestTrajectoriesPrune(t *testing.T) {
+	var (
+		mod   = &ModTabuSearch{NNeighbours: 2, Tenure: 3, Move: swapMove}
+		pop   = Population{RNG: newRand()}
+		first Individuals
+	)
+	for gen, n := range []int{2, 4, 4} {
+		// The individuals are replaced without going through the model
+		pop.Individuals = newIndividuals(2, false, newLineTour, pop.RNG)
+		pop.Individuals.Evaluate(false)
+		pop.Generations = uint(gen)
+		if err := mod.Apply(&pop); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		if gen == 0 {
+			first = pop.Individuals
+		}
+		if len(mod.trajs.states) != n {
+			t.Errorf("Generation %d: expected %d trajectories, got %d", gen, n, len(mod.trajs.states))
+		}
+	}
+	// The trajectories of the first individuals weren't updated at generation 1
+	for _, indi := range first {
+		if _, ok := mod.trajs.states[indi.ID]; ok {
+			t.Errorf("The trajectory of %s should have been pruned", indi.ID)
+		}
+	}
+}
+
+func TestModTabuSearchAllTabu(t *testing.T) {
+	// The only possible move is always tabu and never improves the fitness
+	var (
+		mod = &ModTabuSearch{
+			NNeighbours: 3,
+			Tenure:      1,
+			Move:        func(genome Genome, rng *rand.Rand) interface{} { return "noop" },
+		}
+		pop = Population{Individuals: newIndividuals(1, false, newLineTour, newRand()), RNG: newRand()}
+	)
+	pop.Individuals.Evaluate(false)
+	mod.Apply(&pop)
+	var id = pop.Individuals[0].ID
+	mod.Apply(&pop)
+	if pop.Individuals[0].ID != id {
+		t.Error("The Individual should not have moved")
+	}
+}
+
+func TestModILSStrength(t *testing.T) {
+	var (
+		strengths []uint
+		mod       = &ModILS{
+			Search: LSHillClimbing{Neighbour: func(genome Genome, rng *rand.Rand) {}},
+			Budget: 1,
+			// The perturbation never improves the fitness
+			Perturb: func(genome Genome, strength uint, rng *rand.Rand) {
+				strengths = append(strengths, strength)
+				genome.(shiftedSphere)[0] += 1
+			},
+			MinStrength: 1,
+			MaxStrength: 3,
+		}
+		pop = Population{Individuals: Individuals{NewIndividual(shiftedSphere{1}, newRand())}, RNG: newRand()}
+	)
+	pop.Individuals.Evaluate(false)
+	for i := 0; i < 5; i++ {
+		if err := mod.Apply(&pop); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+	}
+	var expected = []uint{1, 2, 3, 3, 3}
+	for i := range expected {
+		if strengths[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, strengths)
+			break
+		}
+	}
+	if pop.Individuals[0].Genome.(shiftedSphere)[0] != 1 {
+		t.Errorf("Worse solutions should not be accepted, got %v", pop.Individuals[0])
+	}
+}
+
+func TestModVNSNeighbourhoods(t *testing.T) {
+	var (
+		used []int
+		nb   = func(k int) func(Genome, *rand.Rand) {
+			return func(genome Genome, rng *rand.Rand) {
+				used = append(used, k)
+				// Only the last neighbourhood improves the fitness
+				if k == 2 {
+					genome.(shiftedSphere)[0] -= 1
+				}
+			}
+		}
+		mod = &ModVNS{Neighbourhoods: []func(Genome, *rand.Rand){nb(0), nb(1), nb(2)}}
+		pop = Population{Individuals: Individuals{NewIndividual(shiftedSphere{10}, newRand())}, RNG: newRand()}
+	)
+	pop.Individuals.Evaluate(false)
+	for i := 0; i < 6; i++ {
+		if err := mod.Apply(&pop); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+	}
+	var expected = []int{0, 1, 2, 0, 1, 2}
+	for i := range expected {
+		if used[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, used)
+			break
+		}
+	}
+	if x := pop.Individuals[0].Genome.(shiftedSphere)[0]; x != 8 {
+		t.Errorf("Expected 8, got %f", x)
+	}
+}
+
+func TestTrajectoryModelsErrors(t *testing.T) {
+	var (
+		search = LSHillClimbing{Neighbour: func(genome Genome, rng *rand.Rand) {}}
+		mods   = []Model{
+			&ModTabuSearch{NNeighbours: 2, Tenure: 2, Hash: func(g Genome) interface{} { return 0 }},
+			&ModILS{Search: search, Budget: 1, MinStrength: 1, MaxStrength: 1},
+			&ModVNS{Neighbourhoods: []func(Genome, *rand.Rand){func(Genome, *rand.Rand) {}}},
+		}
+	)
+	for i, mod := range mods {
+		var pop = Population{Individuals: Individuals{NewIndividual(ErrorGenome{}, newRand())}, RNG: newRand()}
+		if err := mod.Apply(&pop); err == nil {
+			t.Errorf("Test %d: expected error", i)
+		}
+	}
+}
+
+func TestGATrajectoryModelsIslands(t *testing.T) {
+	var mods = []Model{
+		&ModTabuSearch{NNeighbours: 10, Tenure: 5, Move: swapMove},
+		&ModILS{
+			Search:      LSTwoOpt{Slice: func(genome Genome) Slice { return IntSlice(genome.(lineTour)) }},
+			Budget:      50,
+			MinStrength: 1,
+			MaxStrength: 4,
+		},
+		&ModVNS{
+			Neighbourhoods: []func(Genome, *rand.Rand){
+				func(genome Genome, rng *rand.Rand) { MutPermuteInt(genome.(lineTour), 1, rng) },
+				func(genome Genome, rng *rand.Rand) { MutInversionInt(genome.(lineTour), rng) },
+				func(genome Genome, rng *rand.Rand) { MutPermuteInt(genome.(lineTour), 3, rng) },
+			},
+		},
+	}
+	for i, mod := range mods {
+		var ga, err = GAConfig{
+			NPops:        3,
+			PopSize:      4,
+			NGenerations: 50,
+			HofSize:      1,
+			Model:        mod,
+			Migrator:     MigRing{NMigrants: 1},
+			MigFrequency: 10,
+			RNG:          newRand(),
+			ParallelEval: true,
+		}.NewGA()
+		if err != nil {
+			t.Fatalf("Test %d: expected nil, got %v", i, err)
+		}
+		if err = ga.Minimize(newLineTour); err != nil {
+			t.Errorf("Test %d: expected nil, got %v", i, err)
+		}
+		if f := ga.HallOfFame[0].Fitness; f != 14 {
+			t.Errorf("Test %d: expected the optimal tour, got %f", i, f)
+		}
+	}
+}
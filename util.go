@@ -2,6 +2,7 @@ package eaopt
 
 import (
 	"math"
+	"sort"
 )
 
 func copyFloat64s(fs []float64) []float64 {
@@ -45,6 +46,14 @@ func minUint(a, b uint) uint {
 	return b
 }
 
+// Find the maximum between two uints.
+func maxUint(a, b uint) uint {
+	if a >= b {
+		return a
+	}
+	return b
+}
+
 // Find the minimum between two ints.
 func minInt(a, b int) int {
 	if a <= b {
@@ -53,6 +62,36 @@ func minInt(a, b int) int {
 	return b
 }
 
+// Find the maximum between two ints.
+func maxInt(a, b int) int {
+	if a >= b {
+		return a
+	}
+	return b
+}
+
+// Restrict a float64 to the [lower, upper] interval.
+func clampFloat64(x, lower, upper float64) float64 {
+	if x < lower {
+		return lower
+	}
+	if x > upper {
+		return upper
+	}
+	return x
+}
+
+// Restrict an int to the [lower, upper] interval.
+func clampInt(x, lower, upper int) int {
+	if x < lower {
+		return lower
+	}
+	if x > upper {
+		return upper
+	}
+	return x
+}
+
 // Compute the sum of an int slice.
 func sumInts(ints []int) (sum int) {
 	for _, v := range ints {
@@ -108,25 +147,49 @@ func varianceFloat64s(floats []float64) float64 {
 	return ss / float64(len(floats))
 }
 
-type set map[interface{}]bool
-
-type setInt map[interface{}]int
+// Compute the median of a float64 slice, the slice is left untouched.
+func medianFloat64s(floats []float64) float64 {
+	var (
+		sorted = copyFloat64s(floats)
+		n      = len(sorted)
+	)
+	sort.Float64s(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
 
-// union merges two slices and ignores duplicates.
-func union(x, y set) set {
+// Compute the median absolute deviation of a float64 slice.
+func madFloat64s(floats []float64) float64 {
 	var (
-		u         = make(set)
-		blackList = make(map[interface{}]bool)
+		median     = medianFloat64s(floats)
+		deviations = make([]float64, len(floats))
 	)
+	for i, x := range floats {
+		deviations[i] = math.Abs(x - median)
+	}
+	return medianFloat64s(deviations)
+}
+
+// Check if an int slice contains a given int.
+func containsInt(ints []int, x int) bool {
+	for _, i := range ints {
+		if i == x {
+			return true
+		}
+	}
+	return false
+}
+
+// union merges two sets and ignores duplicates.
+func union[T comparable](x, y map[T]bool) map[T]bool {
+	var u = make(map[T]bool, len(x)+len(y))
 	for i := range x {
 		u[i] = true
-		blackList[i] = true
 	}
 	for i := range y {
-		if !blackList[i] {
-			u[i] = true
-			blackList[i] = true
-		}
+		u[i] = true
 	}
 	return u
 }
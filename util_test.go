@@ -137,19 +137,19 @@ func TestCumsum(t *testing.T) {
 
 func TestUnion(t *testing.T) {
 	var testCases = []struct {
-		x set
-		y set
-		u set
+		x map[int]bool
+		y map[int]bool
+		u map[int]bool
 	}{
 		{
-			x: set{1: true, 2: true, 3: true},
-			y: set{4: true, 5: true, 6: true},
-			u: set{1: true, 2: true, 3: true, 4: true, 5: true, 6: true},
+			x: map[int]bool{1: true, 2: true, 3: true},
+			y: map[int]bool{4: true, 5: true, 6: true},
+			u: map[int]bool{1: true, 2: true, 3: true, 4: true, 5: true, 6: true},
 		},
 		{
-			x: set{1: true, 2: true, 3: true},
-			y: set{2: true, 3: true, 4: true},
-			u: set{1: true, 2: true, 3: true, 4: true},
+			x: map[int]bool{1: true, 2: true, 3: true},
+			y: map[int]bool{2: true, 3: true, 4: true},
+			u: map[int]bool{1: true, 2: true, 3: true, 4: true},
 		},
 	}
 	for _, test := range testCases {